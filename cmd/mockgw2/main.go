@@ -0,0 +1,66 @@
+// Package main runs a local mock of the Guild Wars 2 API, so the MCP server can be
+// developed and tested without network access or a real API key.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/AlyxPink/gw2-mcp/internal/gw2mock"
+)
+
+func main() {
+	logger := log.NewWithOptions(os.Stderr, log.Options{
+		ReportTimestamp: true,
+		Level:           log.InfoLevel,
+	})
+
+	addr := flag.String("addr", "localhost:8081", "Listen address")
+	latency := flag.Duration("latency", 0, "Delay added to every response (e.g. 200ms)")
+	rateLimitEvery := flag.Int("rate-limit-every", 0, "Answer every nth request with 429 Too Many Requests (0 disables)")
+	schemaErrors := flag.String("schema-errors", "",
+		"Comma-separated endpoints answering with numbers as strings, e.g. /v2/account/wallet, or all")
+	flag.Parse()
+
+	var opts []gw2mock.Option
+	opts = append(opts, gw2mock.WithLatency(*latency), gw2mock.WithRateLimitEvery(*rateLimitEvery))
+	if *schemaErrors != "" {
+		opts = append(opts, gw2mock.WithSchemaErrors(strings.Split(*schemaErrors, ",")...))
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           gw2mock.New(opts...),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("Failed to shut down cleanly", "error", err)
+		}
+	}()
+
+	logger.Info("Mock GW2 API listening", "base_url", "http://"+*addr+"/v2")
+	for _, key := range gw2mock.Keys() {
+		logger.Info("API key", "key", key.Value, "scopes", strings.Join(key.Permissions, ","))
+	}
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Fatal("Mock server failed", "error", err)
+	}
+}
@@ -0,0 +1,160 @@
+// Package auth provides bearer token authentication for the HTTP transports of the GW2 MCP server.
+// Each token maps to a profile holding the Guild Wars 2 API key used by that user's session.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// sessionIDHeader carries the session ID of the streamable HTTP transport
+	sessionIDHeader = "Mcp-Session-Id"
+	// sessionIDQueryParam carries the session ID of the SSE transport message endpoint
+	sessionIDQueryParam = "sessionId"
+)
+
+// Errors returned by Authenticate
+var (
+	ErrMissingToken = errors.New("missing bearer token")
+	ErrInvalidToken = errors.New("invalid bearer token")
+)
+
+// Profile is an authenticated user of the server along with their GW2 API key
+type Profile struct {
+	Name   string `json:"name"`
+	APIKey string `json:"api_key"`
+}
+
+// ProfileConfig is a profile entry of the profiles file
+type ProfileConfig struct {
+	Name   string `json:"name"`
+	Token  string `json:"token"`
+	APIKey string `json:"api_key"`
+}
+
+// profilesFile is the on-disk format of the profiles file
+type profilesFile struct {
+	Profiles []ProfileConfig `json:"profiles"`
+}
+
+// Store authenticates bearer tokens and binds MCP sessions to the profile that opened them
+type Store struct {
+	profiles map[[sha256.Size]byte]*Profile // keyed by token hash, tokens are never kept in memory
+	sessions sync.Map                       // session ID -> profile name
+}
+
+// contextKey is the type of the context key holding the authenticated profile
+type contextKey struct{}
+
+// NewStore creates a store from profile configurations
+func NewStore(configs []ProfileConfig) (*Store, error) {
+	store := &Store{
+		profiles: make(map[[sha256.Size]byte]*Profile, len(configs)),
+	}
+
+	names := make(map[string]bool, len(configs))
+	for i, config := range configs {
+		if config.Name == "" || config.Token == "" || config.APIKey == "" {
+			return nil, fmt.Errorf("profile %d: name, token and api_key are required", i)
+		}
+		if names[config.Name] {
+			return nil, fmt.Errorf("profile %q: duplicate name", config.Name)
+		}
+
+		tokenHash := sha256.Sum256([]byte(config.Token))
+		if _, exists := store.profiles[tokenHash]; exists {
+			return nil, fmt.Errorf("profile %q: token already used by another profile", config.Name)
+		}
+
+		names[config.Name] = true
+		store.profiles[tokenHash] = &Profile{Name: config.Name, APIKey: config.APIKey}
+	}
+
+	return store, nil
+}
+
+// LoadStore reads a JSON profiles file and creates a store from it
+func LoadStore(path string) (*Store, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is provided by the operator
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles file: %w", err)
+	}
+
+	var file profilesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles file: %w", err)
+	}
+
+	return NewStore(file.Profiles)
+}
+
+// Authenticate returns the profile matching the bearer token of a request
+func (s *Store) Authenticate(r *http.Request) (*Profile, error) {
+	header := r.Header.Get("Authorization")
+	token, found := strings.CutPrefix(header, "Bearer ")
+	if !found || strings.TrimSpace(token) == "" {
+		return nil, ErrMissingToken
+	}
+
+	profile, ok := s.profiles[sha256.Sum256([]byte(strings.TrimSpace(token)))]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	return profile, nil
+}
+
+// Middleware rejects unauthenticated requests and requests carrying a session bound to another
+// profile, and stores the authenticated profile in the request context for tool handlers
+func (s *Store) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		profile, err := s.Authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gw2-mcp"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		sessionID := r.Header.Get(sessionIDHeader)
+		if sessionID == "" {
+			sessionID = r.URL.Query().Get(sessionIDQueryParam)
+		}
+
+		if owner, ok := s.sessions.Load(sessionID); ok && owner != profile.Name {
+			http.Error(w, "session belongs to another profile", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithProfile(r.Context(), profile)))
+	})
+}
+
+// BindSession binds a session to the profile that opened it, as the transport registers the
+// session and before its ID is handed to the client
+func (s *Store) BindSession(sessionID string, profile *Profile) {
+	s.sessions.Store(sessionID, profile.Name)
+}
+
+// EndSession forgets the profile bound to a session, once the transport closed the session
+func (s *Store) EndSession(sessionID string) {
+	s.sessions.Delete(sessionID)
+}
+
+// WithProfile returns a copy of ctx carrying the authenticated profile
+func WithProfile(ctx context.Context, profile *Profile) context.Context {
+	return context.WithValue(ctx, contextKey{}, profile)
+}
+
+// ProfileFromContext returns the authenticated profile of a request, if any
+func ProfileFromContext(ctx context.Context) (*Profile, bool) {
+	profile, ok := ctx.Value(contextKey{}).(*Profile)
+	return profile, ok && profile != nil
+}
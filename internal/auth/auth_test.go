@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testStore(t *testing.T) *Store {
+	t.Helper()
+
+	store, err := NewStore([]ProfileConfig{
+		{Name: "alice", Token: "alice-token", APIKey: "ALICE-KEY"},
+		{Name: "bob", Token: "bob-token", APIKey: "BOB-KEY"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	return store
+}
+
+func TestNewStore_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs []ProfileConfig
+	}{
+		{
+			name:    "Missing token",
+			configs: []ProfileConfig{{Name: "alice", APIKey: "KEY"}},
+		},
+		{
+			name: "Duplicate name",
+			configs: []ProfileConfig{
+				{Name: "alice", Token: "a", APIKey: "KEY"},
+				{Name: "alice", Token: "b", APIKey: "KEY"},
+			},
+		},
+		{
+			name: "Duplicate token",
+			configs: []ProfileConfig{
+				{Name: "alice", Token: "same", APIKey: "KEY"},
+				{Name: "bob", Token: "same", APIKey: "KEY"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewStore(tt.configs); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
+
+func TestLoadStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	content := `{"profiles": [{"name": "alice", "token": "alice-token", "api_key": "ALICE-KEY"}]}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write profiles file: %v", err)
+	}
+
+	store, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("LoadStore failed: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", http.NoBody)
+	req.Header.Set("Authorization", "Bearer alice-token")
+
+	profile, err := store.Authenticate(req)
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if profile.Name != "alice" || profile.APIKey != "ALICE-KEY" {
+		t.Errorf("Unexpected profile %+v", profile)
+	}
+}
+
+func TestStore_Authenticate(t *testing.T) {
+	store := testStore(t)
+
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+		wantErr  error
+	}{
+		{name: "Valid token", header: "Bearer bob-token", expected: "bob"},
+		{name: "Missing header", header: "", wantErr: ErrMissingToken},
+		{name: "Wrong scheme", header: "Basic bob-token", wantErr: ErrMissingToken},
+		{name: "Unknown token", header: "Bearer eve-token", wantErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/mcp", http.NoBody)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			profile, err := store.Authenticate(req)
+			if err != tt.wantErr {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && profile.Name != tt.expected {
+				t.Errorf("Authenticate() profile = %q, want %q", profile.Name, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStore_Middleware(t *testing.T) {
+	store := testStore(t)
+
+	var seen string
+	handler := store.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		profile, ok := ProfileFromContext(r.Context())
+		if !ok {
+			t.Error("Expected profile in request context")
+			return
+		}
+		seen = profile.Name
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(token, sessionID string) int {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", http.NoBody)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if sessionID != "" {
+			req.Header.Set(sessionIDHeader, sessionID)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := send("", ""); code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without token, got %d", code)
+	}
+
+	// Sessions are only bound once the transport registered them
+	if code := send("bob-token", "session-1"); code != http.StatusOK || seen != "bob" {
+		t.Errorf("Expected bob to use the unbound session-1, got %d as %q", code, seen)
+	}
+
+	store.BindSession("session-1", &Profile{Name: "alice"})
+	if code := send("alice-token", "session-1"); code != http.StatusOK || seen != "alice" {
+		t.Errorf("Expected alice to use session-1, got %d as %q", code, seen)
+	}
+
+	// Bob cannot hijack a session bound to Alice
+	if code := send("bob-token", "session-1"); code != http.StatusForbidden {
+		t.Errorf("Expected 403 for foreign session, got %d", code)
+	}
+
+	if code := send("alice-token", "session-1"); code != http.StatusOK {
+		t.Errorf("Expected alice to keep using session-1, got %d", code)
+	}
+
+	// SSE clients carry the session ID in the query string
+	req := httptest.NewRequest(http.MethodPost, "/message?sessionId=session-1", http.NoBody)
+	req.Header.Set("Authorization", "Bearer bob-token")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for foreign SSE session, got %d", rec.Code)
+	}
+
+	// An ended session is forgotten, so its ID no longer belongs to anyone
+	store.EndSession("session-1")
+	if code := send("bob-token", "session-1"); code != http.StatusOK || seen != "bob" {
+		t.Errorf("Expected bob to use the ended session-1, got %d as %q", code, seen)
+	}
+}
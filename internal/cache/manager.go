@@ -4,6 +4,9 @@ package cache
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/patrickmn/go-cache"
@@ -11,7 +14,42 @@ import (
 
 // Manager handles caching for the GW2 MCP server
 type Manager struct {
-	cache *cache.Cache
+	cache       *cache.Cache
+	ttls        TTLs
+	persistPath string
+	stop        chan struct{}
+	closeOnce   sync.Once
+}
+
+// Option configures optional Manager settings
+type Option func(*Manager)
+
+// TTLs holds the cache durations used by the API and wiki clients
+type TTLs struct {
+	StaticData        time.Duration
+	WikiData          time.Duration
+	WikiRecentChanges time.Duration
+	WalletData        time.Duration
+	TradingPost       time.Duration
+	CleanupInterval   time.Duration
+}
+
+// DefaultTTLs returns the default cache durations
+func DefaultTTLs() TTLs {
+	return TTLs{
+		StaticData:        StaticDataTTL,
+		WikiData:          WikiDataTTL,
+		WikiRecentChanges: WikiRecentChangesTTL,
+		WalletData:        WalletDataTTL,
+		TradingPost:       TradingPostTTL,
+		CleanupInterval:   CleanupInterval,
+	}
+}
+
+// persistedItem is the on-disk representation of a cached string value
+type persistedItem struct {
+	Value      string `json:"value"`
+	Expiration int64  `json:"expiration"` // Unix nanoseconds, 0 when the item never expires
 }
 
 // Key represents different types of cache keys
@@ -19,16 +57,62 @@ type Key string
 
 const (
 	// CurrencyListKey is the cache key for the list of all currencies
-	CurrencyListKey Key = "currencies:list"
+	CurrencyListKey Key = "currencies:list:%s" // %s = language
 	// CurrencyDetailKey is the cache key template for individual currency details
-	CurrencyDetailKey Key = "currency:detail:%d"
+	CurrencyDetailKey Key = "currency:detail:%s:%d" // %s = language, %d = currency ID
+	// ItemDetailKey is the cache key template for individual items
+	ItemDetailKey Key = "item:detail:%s:%d" // %s = language, %d = item ID
+	// ItemIndexKey is the cache key template for the item name index
+	ItemIndexKey Key = "items:index:%s" // %s = language
+	// RecipeDetailKey is the cache key template for individual recipes
+	RecipeDetailKey Key = "recipe:detail:%d" // %d = recipe ID
+	// AchievementDetailKey is the cache key template for individual achievements
+	AchievementDetailKey Key = "achievement:detail:%s:%d" // %s = language, %d = achievement ID
+	// LegendaryArmoryKey is the cache key for the items of the legendary armory and their maximum counts
+	LegendaryArmoryKey Key = "legendaryarmory"
+	// UnlocksKey is the cache key template for every unlock of a kind, such as skins or dyes
+	UnlocksKey Key = "unlocks:%s:%s" // %s = unlock kind, %s = language
 	// WikiSearchKey is the cache key template for wiki search results
-	WikiSearchKey Key = "wiki:search:%s"
+	WikiSearchKey Key = "wiki:search:%s:%s:%s" // %s = language, %s = text format, %s = query
 	// WikiPageKey is the cache key template for wiki page content
-	WikiPageKey Key = "wiki:page:%s"
+	WikiPageKey Key = "wiki:page:%s:%s:%s" // %s = language, %s = text format, %s = title
+	// WikiLangLinksKey is the cache key template for wiki interlanguage links
+	WikiLangLinksKey Key = "wiki:langlinks:%s:%s" // %s = language, %s = title
+	// WikiResolveKey is the cache key template for wiki title resolutions
+	WikiResolveKey Key = "wiki:resolve:%s:%s" // %s = language, %s = lowercased title
+	// WikiPrefixKey is the cache key template for wiki title completions
+	WikiPrefixKey Key = "wiki:prefix:%s:%s" // %s = language, %s = lowercased prefix
+	// WikiCategoryKey is the cache key template for wiki category members
+	WikiCategoryKey Key = "wiki:category:%s:%s:%d" // %s = language, %s = category, %d = limit
+	// WikiRecentChangesKey is the cache key template for wiki recent changes (short TTL)
+	WikiRecentChangesKey Key = "wiki:recentchanges:%s:%d:%d" // %s = language, %d = namespace, %d = limit
 
+	// CharactersKey is the cache key template for account character names (short TTL)
+	CharactersKey Key = "characters:%s" // %s = hashed API key
+	// AccountKey is the cache key template for account details (short TTL)
+	AccountKey Key = "account:%s" // %s = hashed API key
+	// StorageKey is the cache key template for the items in an account storage: bank, materials,
+	// inventory, legendaryarmory or character:<name> (short TTL)
+	StorageKey Key = "storage:%s:%s" // %s = hashed API key, %s = storage
+	// AchievementsKey is the cache key template for the achievement progress of an account (short TTL)
+	AchievementsKey Key = "achievements:%s" // %s = hashed API key
+	// AccountUnlocksKey is the cache key template for the unlocks of a kind owned by an account (short TTL)
+	AccountUnlocksKey Key = "account:unlocks:%s:%s" // %s = hashed API key, %s = unlock kind
 	// WalletKey is the cache key template for wallet data (short TTL)
-	WalletKey Key = "wallet:%s" // %s = hashed API key
+	WalletKey Key = "wallet:%s:%s" // %s = hashed API key, %s = language
+	// TransactionsKey is the cache key template for trading post transactions (short TTL)
+	TransactionsKey Key = "tp:transactions:%s:%s:%s" // %s = hashed API key, %s = current or history, %s = buys or sells
+	// DeliveryKey is the cache key template for the trading post delivery box (short TTL)
+	DeliveryKey Key = "tp:delivery:%s" // %s = hashed API key
+	// PriceKey is the cache key template for the trading post prices of an item (short TTL)
+	PriceKey Key = "tp:price:%d" // %d = item ID
+	// ListingsKey is the cache key template for the trading post order book of an item (short TTL)
+	ListingsKey Key = "tp:listings:%d" // %d = item ID
+	// TradedItemsKey is the cache key for the IDs of all items traded on the trading post (short TTL)
+	TradedItemsKey Key = "tp:items"
+
+	// GemExchangeKey is the cache key template for gem exchange quotes (short TTL)
+	GemExchangeKey Key = "exchange:%s:%d" // %s = currency given, %d = quantity
 )
 
 // Cache durations
@@ -38,19 +122,152 @@ const (
 	WikiDataTTL   = 24 * time.Hour       // 1 day for wiki content
 
 	// Dynamic data - shorter cache periods
-	WalletDataTTL = 5 * time.Minute // 5 minutes for wallet data
+	WalletDataTTL        = 5 * time.Minute // 5 minutes for wallet data
+	WikiRecentChangesTTL = 5 * time.Minute // 5 minutes for wiki recent changes
+	TradingPostTTL       = 2 * time.Minute // 2 minutes for trading post and gem exchange data
 
 	// Default cleanup interval
 	CleanupInterval = 10 * time.Minute
 )
 
+// WithTTLs overrides the default cache durations
+func WithTTLs(ttls TTLs) Option {
+	return func(m *Manager) {
+		m.ttls = ttls
+	}
+}
+
+// WithPersistence enables restoring the cache from path with Load and saving it back on Close
+func WithPersistence(path string) Option {
+	return func(m *Manager) {
+		m.persistPath = path
+	}
+}
+
 // NewManager creates a new cache manager
-func NewManager() *Manager {
-	return &Manager{
-		cache: cache.New(StaticDataTTL, CleanupInterval),
+func NewManager(opts ...Option) *Manager {
+	m := &Manager{
+		ttls: DefaultTTLs(),
+		stop: make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	// Expired items are removed by our own janitor so that it can be stopped on Close
+	m.cache = cache.New(m.ttls.StaticData, 0)
+
+	go m.janitor()
+
+	return m
+}
+
+// Load restores persisted items, skipping those that expired in the meantime.
+// A missing file is not an error.
+func (m *Manager) Load() error {
+	if m.persistPath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(m.persistPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read cache file: %w", err)
+	}
+
+	var items map[string]persistedItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("failed to parse cache file: %w", err)
+	}
+
+	now := time.Now().UnixNano()
+	for key, item := range items {
+		switch {
+		case item.Expiration == 0:
+			m.cache.Set(key, item.Value, cache.NoExpiration)
+		case item.Expiration > now:
+			m.cache.Set(key, item.Value, time.Duration(item.Expiration-now))
+		}
+	}
+
+	return nil
+}
+
+// Save writes all unexpired string items to the persistence file
+func (m *Manager) Save() error {
+	if m.persistPath == "" {
+		return nil
+	}
+
+	items := make(map[string]persistedItem)
+	for key, item := range m.cache.Items() {
+		// Only JSON and string values are persisted, which covers everything the clients cache
+		if value, ok := item.Object.(string); ok {
+			items[key] = persistedItem{Value: value, Expiration: item.Expiration}
+		}
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+
+	// Write to a temporary file first so that a crash never leaves a truncated cache behind
+	tmp, err := os.CreateTemp(filepath.Dir(m.persistPath), ".gw2-mcp-cache-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), m.persistPath); err != nil {
+		return fmt.Errorf("failed to replace cache file: %w", err)
+	}
+
+	return nil
+}
+
+// Close stops the expiration janitor and persists the cache when configured.
+// It is safe to call Close more than once.
+func (m *Manager) Close() error {
+	var err error
+	m.closeOnce.Do(func() {
+		close(m.stop)
+		err = m.Save()
+	})
+	return err
+}
+
+// janitor periodically removes expired items until the manager is closed
+func (m *Manager) janitor() {
+	ticker := time.NewTicker(m.ttls.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.cache.DeleteExpired()
+		case <-m.stop:
+			return
+		}
 	}
 }
 
+// TTLs returns the cache durations clients should use when storing data
+func (m *Manager) TTLs() TTLs {
+	return m.ttls
+}
+
 // Set stores a value in the cache with the specified TTL
 func (m *Manager) Set(key string, value interface{}, ttl time.Duration) {
 	m.cache.Set(key, value, ttl)
@@ -108,27 +325,137 @@ func (m *Manager) ItemCount() int {
 	return m.cache.ItemCount()
 }
 
-// GetCurrencyListKey returns the cache key for currency list
-func (m *Manager) GetCurrencyListKey() string {
-	return string(CurrencyListKey)
+// GetCurrencyListKey returns the cache key for currency list in a given language
+func (m *Manager) GetCurrencyListKey(lang string) string {
+	return fmt.Sprintf(string(CurrencyListKey), lang)
+}
+
+// GetCurrencyDetailKey returns the cache key for a specific currency in a given language
+func (m *Manager) GetCurrencyDetailKey(lang string, id int) string {
+	return fmt.Sprintf(string(CurrencyDetailKey), lang, id)
+}
+
+// GetItemDetailKey returns the cache key for a specific item in a given language
+func (m *Manager) GetItemDetailKey(lang string, id int) string {
+	return fmt.Sprintf(string(ItemDetailKey), lang, id)
+}
+
+// GetItemIndexKey returns the cache key for the item name index in a given language
+func (m *Manager) GetItemIndexKey(lang string) string {
+	return fmt.Sprintf(string(ItemIndexKey), lang)
+}
+
+// GetRecipeDetailKey returns the cache key for a specific recipe
+func (m *Manager) GetRecipeDetailKey(id int) string {
+	return fmt.Sprintf(string(RecipeDetailKey), id)
+}
+
+// GetWikiSearchKey returns the cache key for wiki search results in a given language and text format
+func (m *Manager) GetWikiSearchKey(lang, textFormat, query string) string {
+	return fmt.Sprintf(string(WikiSearchKey), lang, textFormat, query)
+}
+
+// GetWikiPageKey returns the cache key for a wiki page in a given language and text format
+func (m *Manager) GetWikiPageKey(lang, textFormat, title string) string {
+	return fmt.Sprintf(string(WikiPageKey), lang, textFormat, title)
+}
+
+// GetWikiLangLinksKey returns the cache key for the interlanguage links of a wiki page
+func (m *Manager) GetWikiLangLinksKey(lang, title string) string {
+	return fmt.Sprintf(string(WikiLangLinksKey), lang, title)
+}
+
+// GetWikiResolveKey returns the cache key for the resolution of a wiki title
+func (m *Manager) GetWikiResolveKey(lang, title string) string {
+	return fmt.Sprintf(string(WikiResolveKey), lang, title)
+}
+
+// GetWikiPrefixKey returns the cache key for wiki title completions of a prefix in a given language
+func (m *Manager) GetWikiPrefixKey(lang, prefix string) string {
+	return fmt.Sprintf(string(WikiPrefixKey), lang, prefix)
+}
+
+// GetWikiCategoryKey returns the cache key for the members of a wiki category
+func (m *Manager) GetWikiCategoryKey(lang, category string, limit int) string {
+	return fmt.Sprintf(string(WikiCategoryKey), lang, category, limit)
+}
+
+// GetWikiRecentChangesKey returns the cache key for the recent changes of a wiki namespace
+func (m *Manager) GetWikiRecentChangesKey(lang string, namespace, limit int) string {
+	return fmt.Sprintf(string(WikiRecentChangesKey), lang, namespace, limit)
+}
+
+// GetAchievementDetailKey returns the cache key for a specific achievement in a given language
+func (m *Manager) GetAchievementDetailKey(lang string, id int) string {
+	return fmt.Sprintf(string(AchievementDetailKey), lang, id)
+}
+
+// GetLegendaryArmoryKey returns the cache key for the items of the legendary armory
+func (m *Manager) GetLegendaryArmoryKey() string {
+	return string(LegendaryArmoryKey)
+}
+
+// GetUnlocksKey returns the cache key for every unlock of a kind in a given language
+func (m *Manager) GetUnlocksKey(kind, lang string) string {
+	return fmt.Sprintf(string(UnlocksKey), kind, lang)
+}
+
+// GetCharactersKey returns the cache key for the character names of an account
+func (m *Manager) GetCharactersKey(apiKeyHash string) string {
+	return fmt.Sprintf(string(CharactersKey), apiKeyHash)
+}
+
+// GetAccountKey returns the cache key for the details of an account
+func (m *Manager) GetAccountKey(apiKeyHash string) string {
+	return fmt.Sprintf(string(AccountKey), apiKeyHash)
+}
+
+// GetStorageKey returns the cache key for the items in a storage of an account
+func (m *Manager) GetStorageKey(apiKeyHash, storage string) string {
+	return fmt.Sprintf(string(StorageKey), apiKeyHash, storage)
+}
+
+// GetAchievementsKey returns the cache key for the achievement progress of an account
+func (m *Manager) GetAchievementsKey(apiKeyHash string) string {
+	return fmt.Sprintf(string(AchievementsKey), apiKeyHash)
+}
+
+// GetAccountUnlocksKey returns the cache key for the unlocks of a kind owned by an account
+func (m *Manager) GetAccountUnlocksKey(apiKeyHash, kind string) string {
+	return fmt.Sprintf(string(AccountUnlocksKey), apiKeyHash, kind)
+}
+
+// GetWalletKey returns the cache key for wallet data with currency metadata in a given language
+func (m *Manager) GetWalletKey(apiKeyHash, lang string) string {
+	return fmt.Sprintf(string(WalletKey), apiKeyHash, lang)
+}
+
+// GetTransactionsKey returns the cache key for the current or past trading post buys or sells of an account
+func (m *Manager) GetTransactionsKey(apiKeyHash, state, kind string) string {
+	return fmt.Sprintf(string(TransactionsKey), apiKeyHash, state, kind)
+}
+
+// GetDeliveryKey returns the cache key for the trading post delivery box of an account
+func (m *Manager) GetDeliveryKey(apiKeyHash string) string {
+	return fmt.Sprintf(string(DeliveryKey), apiKeyHash)
 }
 
-// GetCurrencyDetailKey returns the cache key for a specific currency
-func (m *Manager) GetCurrencyDetailKey(id int) string {
-	return fmt.Sprintf(string(CurrencyDetailKey), id)
+// GetPriceKey returns the cache key for the trading post prices of an item
+func (m *Manager) GetPriceKey(id int) string {
+	return fmt.Sprintf(string(PriceKey), id)
 }
 
-// GetWikiSearchKey returns the cache key for wiki search results
-func (m *Manager) GetWikiSearchKey(query string) string {
-	return fmt.Sprintf(string(WikiSearchKey), query)
+// GetListingsKey returns the cache key for the trading post order book of an item
+func (m *Manager) GetListingsKey(id int) string {
+	return fmt.Sprintf(string(ListingsKey), id)
 }
 
-// GetWikiPageKey returns the cache key for a wiki page
-func (m *Manager) GetWikiPageKey(title string) string {
-	return fmt.Sprintf(string(WikiPageKey), title)
+// GetTradedItemsKey returns the cache key for the IDs of all items traded on the trading post
+func (m *Manager) GetTradedItemsKey() string {
+	return string(TradedItemsKey)
 }
 
-// GetWalletKey returns the cache key for wallet data
-func (m *Manager) GetWalletKey(apiKeyHash string) string {
-	return fmt.Sprintf(string(WalletKey), apiKeyHash)
+// GetGemExchangeKey returns the cache key for a gem exchange quote of a quantity of coins or gems
+func (m *Manager) GetGemExchangeKey(from string, quantity int) string {
+	return fmt.Sprintf(string(GemExchangeKey), from, quantity)
 }
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -124,40 +125,185 @@ func TestManager_CacheKeys(t *testing.T) {
 	m := NewManager()
 
 	// Test currency list key
-	key := m.GetCurrencyListKey()
-	expected := string(CurrencyListKey)
+	key := m.GetCurrencyListKey("en")
+	expected := "currencies:list:en"
 	if key != expected {
 		t.Errorf("Expected %s, got %s", expected, key)
 	}
 
 	// Test currency detail key
 	currencyID := 1
-	key = m.GetCurrencyDetailKey(currencyID)
-	expected = "currency:detail:1"
+	key = m.GetCurrencyDetailKey("de", currencyID)
+	expected = "currency:detail:de:1"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	// Test item detail key
+	key = m.GetItemDetailKey("fr", 19721)
+	expected = "item:detail:fr:19721"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	// Test item index key
+	key = m.GetItemIndexKey("en")
+	expected = "items:index:en"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	// Test recipe detail key
+	key = m.GetRecipeDetailKey(7319)
+	expected = "recipe:detail:7319"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	// Test achievement and legendary armory keys
+	key = m.GetAchievementDetailKey("en", 1)
+	expected = "achievement:detail:en:1"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	key = m.GetLegendaryArmoryKey()
+	expected = "legendaryarmory"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	key = m.GetUnlocksKey("mounts/skins", "de")
+	expected = "unlocks:mounts/skins:de"
 	if key != expected {
 		t.Errorf("Expected %s, got %s", expected, key)
 	}
 
 	// Test wiki search key
 	query := "test query"
-	key = m.GetWikiSearchKey(query)
-	expected = "wiki:search:test query"
+	key = m.GetWikiSearchKey("de", "text", query)
+	expected = "wiki:search:de:text:test query"
 	if key != expected {
 		t.Errorf("Expected %s, got %s", expected, key)
 	}
 
 	// Test wiki page key
 	pageTitle := "Test Page"
-	key = m.GetWikiPageKey(pageTitle)
-	expected = "wiki:page:Test Page"
+	key = m.GetWikiPageKey("fr", "markdown", pageTitle)
+	expected = "wiki:page:fr:markdown:Test Page"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	// Test wiki language links key
+	key = m.GetWikiLangLinksKey("en", pageTitle)
+	expected = "wiki:langlinks:en:Test Page"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	// Test wiki resolve key
+	key = m.GetWikiResolveKey("en", "mystic coin")
+	expected = "wiki:resolve:en:mystic coin"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	// Test wiki prefix key
+	key = m.GetWikiPrefixKey("de", "myst")
+	expected = "wiki:prefix:de:myst"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	// Test wiki category key
+	key = m.GetWikiCategoryKey("en", "Category:Legendary weapons", 100)
+	expected = "wiki:category:en:Category:Legendary weapons:100"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	// Test wiki recent changes key
+	key = m.GetWikiRecentChangesKey("es", 0, 25)
+	expected = "wiki:recentchanges:es:0:25"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	// Test characters key
+	key = m.GetCharactersKey("abcd1234")
+	expected = "characters:abcd1234"
 	if key != expected {
 		t.Errorf("Expected %s, got %s", expected, key)
 	}
 
 	// Test wallet key
 	apiKeyHash := "abcd1234"
-	key = m.GetWalletKey(apiKeyHash)
-	expected = "wallet:abcd1234"
+	key = m.GetWalletKey(apiKeyHash, "fr")
+	expected = "wallet:abcd1234:fr"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	// Test account storage keys
+	key = m.GetAccountKey(apiKeyHash)
+	expected = "account:abcd1234"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	key = m.GetStorageKey(apiKeyHash, "character:Mock Warrior")
+	expected = "storage:abcd1234:character:Mock Warrior"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	key = m.GetAchievementsKey(apiKeyHash)
+	expected = "achievements:abcd1234"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	key = m.GetAccountUnlocksKey(apiKeyHash, "skins")
+	expected = "account:unlocks:abcd1234:skins"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	// Test trading post keys
+	key = m.GetTransactionsKey(apiKeyHash, "history", "sells")
+	expected = "tp:transactions:abcd1234:history:sells"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	key = m.GetDeliveryKey(apiKeyHash)
+	expected = "tp:delivery:abcd1234"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	key = m.GetPriceKey(19721)
+	expected = "tp:price:19721"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	key = m.GetListingsKey(19721)
+	expected = "tp:listings:19721"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	key = m.GetTradedItemsKey()
+	expected = "tp:items"
+	if key != expected {
+		t.Errorf("Expected %s, got %s", expected, key)
+	}
+
+	// Test gem exchange key
+	key = m.GetGemExchangeKey("coins", 1000000)
+	expected = "exchange:coins:1000000"
 	if key != expected {
 		t.Errorf("Expected %s, got %s", expected, key)
 	}
@@ -187,3 +333,45 @@ func TestManager_TTLExpiration(t *testing.T) {
 		t.Error("Expected value to be expired")
 	}
 }
+
+func TestManager_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	m := NewManager(WithPersistence(path))
+	if err := m.Load(); err != nil {
+		t.Fatalf("Load on missing file should succeed: %v", err)
+	}
+
+	m.Set("kept", "value", time.Hour)
+	m.Set("expiring", "value", 50*time.Millisecond)
+	m.Set("not_a_string", 42, time.Hour)
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Closing twice is harmless
+	if err := m.Close(); err != nil {
+		t.Fatalf("Second Close failed: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	restored := NewManager(WithPersistence(path))
+	defer func() { _ = restored.Close() }()
+	if err := restored.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if value, found := restored.GetString("kept"); !found || value != "value" {
+		t.Errorf("Expected kept item to be restored, got %q, %v", value, found)
+	}
+
+	if _, found := restored.Get("expiring"); found {
+		t.Error("Expected expired item not to be restored")
+	}
+
+	if _, found := restored.Get("not_a_string"); found {
+		t.Error("Expected non-string item not to be persisted")
+	}
+}
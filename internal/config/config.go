@@ -0,0 +1,498 @@
+// Package config loads the GW2 MCP server configuration.
+// Settings are layered, each source overriding the previous one: built-in defaults,
+// an optional YAML file, GW2MCP_* environment variables and command-line flags. The
+// configuration only holds plain values; the command maps it onto server options.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"gopkg.in/yaml.v3"
+
+	"github.com/AlyxPink/gw2-mcp/internal/cache"
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+	"github.com/AlyxPink/gw2-mcp/internal/httpfixture"
+	"github.com/AlyxPink/gw2-mcp/internal/wiki"
+)
+
+// configFileEnv is the environment variable holding the path of the YAML configuration file
+const configFileEnv = "GW2MCP_CONFIG"
+
+// Defaults of the server settings, the same as those of the server package
+const (
+	defaultTransport           = "stdio"
+	defaultHTTPAddr            = "localhost:8080"
+	defaultShutdownTimeout     = 15 * time.Second
+	defaultMaxOutputSize       = 20000
+	defaultPriceSampleInterval = 15 * time.Minute
+)
+
+// transports are the names of the supported transports, "streamable-http" being an alias of "http"
+var transports = []string{"stdio", "sse", "http", "streamable-http"}
+
+// Config holds every tunable setting of the server
+type Config struct {
+	LogLevel string         `yaml:"log_level"`
+	Language string         `yaml:"language"`
+	Server   ServerConfig   `yaml:"server"`
+	Cache    CacheConfig    `yaml:"cache"`
+	GW2API   GW2APIConfig   `yaml:"gw2api"`
+	Wiki     WikiConfig     `yaml:"wiki"`
+	Fixtures FixturesConfig `yaml:"fixtures"`
+	Storage  StorageConfig  `yaml:"storage"`
+}
+
+// ServerConfig holds transport and lifecycle settings
+type ServerConfig struct {
+	Transport       string        `yaml:"transport"`
+	Addr            string        `yaml:"addr"`
+	Profiles        string        `yaml:"profiles"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	MaxOutputSize   int           `yaml:"max_output_size"`
+}
+
+// FixturesConfig holds settings for recording and replaying upstream HTTP responses
+type FixturesConfig struct {
+	Dir     string `yaml:"dir"`
+	Offline bool   `yaml:"offline"`
+	Record  bool   `yaml:"record"`
+}
+
+// StorageConfig holds the local database and price recording settings
+type StorageConfig struct {
+	Database            string        `yaml:"database"`        // SQLite file, empty disables price history and snapshots
+	PriceWatchlist      []int         `yaml:"price_watchlist"` // item IDs whose prices are recorded
+	PriceSampleInterval time.Duration `yaml:"price_sample_interval"`
+}
+
+// CacheConfig holds cache durations and persistence settings
+type CacheConfig struct {
+	File                 string        `yaml:"file"`
+	StaticTTL            time.Duration `yaml:"static_ttl"`
+	WikiTTL              time.Duration `yaml:"wiki_ttl"`
+	WikiRecentChangesTTL time.Duration `yaml:"wiki_recent_changes_ttl"`
+	WalletTTL            time.Duration `yaml:"wallet_ttl"`
+	TradingPostTTL       time.Duration `yaml:"trading_post_ttl"`
+	CleanupInterval      time.Duration `yaml:"cleanup_interval"`
+}
+
+// GW2APIConfig holds GW2 API client settings
+type GW2APIConfig struct {
+	BaseURL    string        `yaml:"base_url"`
+	Timeout    time.Duration `yaml:"timeout"`
+	IndexItems bool          `yaml:"index_items"`
+}
+
+// WikiConfig holds wiki client settings
+type WikiConfig struct {
+	BaseURLs map[string]string `yaml:"base_urls"` // keyed by language code
+	Timeout  time.Duration     `yaml:"timeout"`
+}
+
+// setting describes a value that can be set from both an environment variable and a flag
+type setting struct {
+	flag   string
+	env    string
+	usage  string
+	set    func(cfg *Config, value string) error
+	isBool bool // boolean flags may be given without a value
+}
+
+// Default returns the built-in configuration
+func Default() *Config {
+	ttls := cache.DefaultTTLs()
+
+	return &Config{
+		LogLevel: "debug",
+		Language: string(gw2api.DefaultLanguage),
+		Server: ServerConfig{
+			Transport:       defaultTransport,
+			Addr:            defaultHTTPAddr,
+			ShutdownTimeout: defaultShutdownTimeout,
+			MaxOutputSize:   defaultMaxOutputSize,
+		},
+		Cache: CacheConfig{
+			StaticTTL:            ttls.StaticData,
+			WikiTTL:              ttls.WikiData,
+			WikiRecentChangesTTL: ttls.WikiRecentChanges,
+			WalletTTL:            ttls.WalletData,
+			TradingPostTTL:       ttls.TradingPost,
+			CleanupInterval:      ttls.CleanupInterval,
+		},
+		GW2API: GW2APIConfig{
+			BaseURL: gw2api.DefaultBaseURL,
+			Timeout: gw2api.DefaultTimeout,
+		},
+		Wiki: WikiConfig{
+			BaseURLs: map[string]string{},
+			Timeout:  wiki.DefaultTimeout,
+		},
+		Fixtures: FixturesConfig{
+			Dir: httpfixture.DefaultDir,
+		},
+		Storage: StorageConfig{
+			PriceSampleInterval: defaultPriceSampleInterval,
+		},
+	}
+}
+
+// Load builds the configuration from defaults, the YAML file, the environment and args (without the program name)
+func Load(args []string) (*Config, error) {
+	return load(args, os.LookupEnv)
+}
+
+// load is Load with an injectable environment lookup for tests
+func load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	settings := allSettings()
+
+	// Flags are parsed first to find the config file, but applied last so they win
+	fs := flag.NewFlagSet("gw2-mcp", flag.ContinueOnError)
+	configPath := fs.String("config", "", "Path to a YAML configuration file (env: "+configFileEnv+")")
+
+	flagValues := make(map[string]string)
+	for _, st := range settings {
+		name := st.flag
+		usage := fmt.Sprintf("%s (env: %s)", st.usage, st.env)
+		if st.isBool {
+			fs.BoolFunc(name, usage, func(value string) error {
+				flagValues[name] = value
+				return nil
+			})
+			continue
+		}
+		fs.Func(name, usage, func(value string) error {
+			flagValues[name] = value
+			return nil
+		})
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+
+	if *configPath == "" {
+		*configPath, _ = lookupEnv(configFileEnv)
+	}
+	if *configPath != "" {
+		if err := cfg.loadFile(*configPath); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, st := range settings {
+		if value, ok := lookupEnv(st.env); ok {
+			if err := st.set(cfg, value); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", st.env, err)
+			}
+		}
+	}
+
+	for _, st := range settings {
+		if value, ok := flagValues[st.flag]; ok {
+			if err := st.set(cfg, value); err != nil {
+				return nil, fmt.Errorf("invalid -%s: %w", st.flag, err)
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// loadFile merges a YAML configuration file into the configuration. TOML files are
+// rejected by their extension rather than with a confusing YAML syntax error.
+func (c *Config) loadFile(path string) error {
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		return fmt.Errorf("config file %s: TOML is not supported, use a YAML file", path)
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- path is provided by the operator
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Validate checks that every setting holds a usable value
+func (c *Config) Validate() error {
+	var errs []error
+
+	if _, err := log.ParseLevel(c.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("log_level: %w", err))
+	}
+
+	if _, err := gw2api.ParseLanguage(c.Language, gw2api.DefaultLanguage); err != nil {
+		errs = append(errs, fmt.Errorf("language: %w", err))
+	}
+
+	transport := strings.ToLower(strings.TrimSpace(c.Server.Transport))
+	if transport != "" && !slices.Contains(transports, transport) {
+		errs = append(errs, fmt.Errorf("server.transport: unsupported transport %q (supported: stdio, sse, http)",
+			c.Server.Transport))
+	}
+	if transport != "" && transport != "stdio" && c.Server.Addr == "" {
+		errs = append(errs, errors.New("server.addr: required for HTTP transports"))
+	}
+
+	if c.Server.MaxOutputSize < 0 {
+		errs = append(errs, errors.New("server.max_output_size: must not be negative"))
+	}
+
+	durations := map[string]time.Duration{
+		"server.shutdown_timeout":       c.Server.ShutdownTimeout,
+		"cache.static_ttl":              c.Cache.StaticTTL,
+		"cache.wiki_ttl":                c.Cache.WikiTTL,
+		"cache.wiki_recent_changes_ttl": c.Cache.WikiRecentChangesTTL,
+		"cache.wallet_ttl":              c.Cache.WalletTTL,
+		"cache.trading_post_ttl":        c.Cache.TradingPostTTL,
+		"cache.cleanup_interval":        c.Cache.CleanupInterval,
+		"gw2api.timeout":                c.GW2API.Timeout,
+		"wiki.timeout":                  c.Wiki.Timeout,
+		"storage.price_sample_interval": c.Storage.PriceSampleInterval,
+	}
+	for name, duration := range durations {
+		if duration <= 0 {
+			errs = append(errs, fmt.Errorf("%s: must be a positive duration", name))
+		}
+	}
+
+	if c.Fixtures.Offline && c.Fixtures.Record {
+		errs = append(errs, errors.New("fixtures: offline and record are mutually exclusive"))
+	}
+	if (c.Fixtures.Offline || c.Fixtures.Record) && c.Fixtures.Dir == "" {
+		errs = append(errs, errors.New("fixtures.dir: required to replay or record fixtures"))
+	}
+
+	if len(c.Storage.PriceWatchlist) > 0 && c.Storage.Database == "" {
+		errs = append(errs, errors.New("storage.database: required to record the prices of the watchlist"))
+	}
+	for _, id := range c.Storage.PriceWatchlist {
+		if id <= 0 {
+			errs = append(errs, fmt.Errorf("storage.price_watchlist: invalid item ID %d", id))
+		}
+	}
+
+	if err := validateURL(c.GW2API.BaseURL); err != nil {
+		errs = append(errs, fmt.Errorf("gw2api.base_url: %w", err))
+	}
+
+	for code, baseURL := range c.Wiki.BaseURLs {
+		if _, err := wiki.ParseLanguage(code); err != nil {
+			errs = append(errs, fmt.Errorf("wiki.base_urls: %w", err))
+		}
+		if err := validateURL(baseURL); err != nil {
+			errs = append(errs, fmt.Errorf("wiki.base_urls.%s: %w", code, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Level returns the parsed log level
+func (c *Config) Level() log.Level {
+	level, err := log.ParseLevel(c.LogLevel)
+	if err != nil {
+		return log.InfoLevel
+	}
+	return level
+}
+
+// allSettings lists every setting available as an environment variable and a flag
+func allSettings() []setting {
+	settings := []setting{
+		stringSetting("log-level", "GW2MCP_LOG_LEVEL", "Log level: debug, info, warn or error",
+			func(c *Config) *string { return &c.LogLevel }),
+		stringSetting("lang", "GW2MCP_LANG", "Default GW2 API language: en, de, fr, es or zh",
+			func(c *Config) *string { return &c.Language }),
+		stringSetting("transport", "GW2MCP_TRANSPORT", "MCP transport: stdio, sse or http",
+			func(c *Config) *string { return &c.Server.Transport }),
+		stringSetting("addr", "GW2MCP_ADDR", "Listen address for the sse and http transports",
+			func(c *Config) *string { return &c.Server.Addr }),
+		stringSetting("profiles", "GW2MCP_PROFILES", "JSON file mapping bearer tokens to GW2 API key profiles",
+			func(c *Config) *string { return &c.Server.Profiles }),
+		durationSetting("shutdown-timeout", "GW2MCP_SHUTDOWN_TIMEOUT", "Maximum time to wait for in-flight tool calls",
+			func(c *Config) *time.Duration { return &c.Server.ShutdownTimeout }),
+		intSetting("max-output-size", "GW2MCP_MAX_OUTPUT_SIZE",
+			"Maximum size in bytes of a tool result's text before it is truncated (0 disables the limit)",
+			func(c *Config) *int { return &c.Server.MaxOutputSize }),
+		stringSetting("cache-file", "GW2MCP_CACHE_FILE", "File to persist the cache to across restarts",
+			func(c *Config) *string { return &c.Cache.File }),
+		durationSetting("cache-static-ttl", "GW2MCP_CACHE_STATIC_TTL", "Cache duration of static game data",
+			func(c *Config) *time.Duration { return &c.Cache.StaticTTL }),
+		durationSetting("cache-wiki-ttl", "GW2MCP_CACHE_WIKI_TTL", "Cache duration of wiki content",
+			func(c *Config) *time.Duration { return &c.Cache.WikiTTL }),
+		durationSetting("cache-wiki-recent-changes-ttl", "GW2MCP_CACHE_WIKI_RECENT_CHANGES_TTL",
+			"Cache duration of wiki recent changes",
+			func(c *Config) *time.Duration { return &c.Cache.WikiRecentChangesTTL }),
+		durationSetting("cache-wallet-ttl", "GW2MCP_CACHE_WALLET_TTL", "Cache duration of wallet data",
+			func(c *Config) *time.Duration { return &c.Cache.WalletTTL }),
+		durationSetting("cache-trading-post-ttl", "GW2MCP_CACHE_TRADING_POST_TTL",
+			"Cache duration of trading post and gem exchange data",
+			func(c *Config) *time.Duration { return &c.Cache.TradingPostTTL }),
+		durationSetting("cache-cleanup-interval", "GW2MCP_CACHE_CLEANUP_INTERVAL", "Interval between expired item sweeps",
+			func(c *Config) *time.Duration { return &c.Cache.CleanupInterval }),
+		stringSetting("api-base-url", "GW2MCP_API_BASE_URL", "GW2 API base URL",
+			func(c *Config) *string { return &c.GW2API.BaseURL }),
+		durationSetting("api-timeout", "GW2MCP_API_TIMEOUT", "GW2 API request timeout",
+			func(c *Config) *time.Duration { return &c.GW2API.Timeout }),
+		boolSetting("index-items", "GW2MCP_INDEX_ITEMS",
+			"Index all item names on start for argument completion (about 350 API requests when not cached)",
+			func(c *Config) *bool { return &c.GW2API.IndexItems }),
+		durationSetting("wiki-timeout", "GW2MCP_WIKI_TIMEOUT", "Wiki API request timeout",
+			func(c *Config) *time.Duration { return &c.Wiki.Timeout }),
+		stringSetting("fixtures-dir", "GW2MCP_FIXTURES_DIR", "Directory of recorded GW2 API and wiki responses",
+			func(c *Config) *string { return &c.Fixtures.Dir }),
+		boolSetting("offline", "GW2MCP_OFFLINE", "Answer GW2 API and wiki requests from recorded fixtures only",
+			func(c *Config) *bool { return &c.Fixtures.Offline }),
+		boolSetting("record", "GW2MCP_RECORD", "Record GW2 API and wiki responses to the fixtures directory",
+			func(c *Config) *bool { return &c.Fixtures.Record }),
+		stringSetting("database", "GW2MCP_DATABASE",
+			"SQLite file storing price history and account snapshots (empty disables them)",
+			func(c *Config) *string { return &c.Storage.Database }),
+		intListSetting("price-watchlist", "GW2MCP_PRICE_WATCHLIST",
+			"Comma-separated item IDs whose trading post prices are recorded",
+			func(c *Config) *[]int { return &c.Storage.PriceWatchlist }),
+		durationSetting("price-sample-interval", "GW2MCP_PRICE_SAMPLE_INTERVAL",
+			"Interval between recordings of the watchlist prices",
+			func(c *Config) *time.Duration { return &c.Storage.PriceSampleInterval }),
+	}
+
+	for _, lang := range wiki.Languages() {
+		code := string(lang)
+		settings = append(settings, setting{
+			flag:  "wiki-base-url-" + code,
+			env:   "GW2MCP_WIKI_BASE_URL_" + strings.ToUpper(code),
+			usage: fmt.Sprintf("Base URL of the %s wiki", code),
+			set: func(c *Config, value string) error {
+				c.Wiki.BaseURLs[code] = value
+				return nil
+			},
+		})
+	}
+
+	return settings
+}
+
+// stringSetting creates a setting storing its raw value in a string field
+func stringSetting(flagName, env, usage string, field func(*Config) *string) setting {
+	return setting{
+		flag:  flagName,
+		env:   env,
+		usage: usage,
+		set: func(c *Config, value string) error {
+			*field(c) = value
+			return nil
+		},
+	}
+}
+
+// durationSetting creates a setting parsing its value into a duration field
+func durationSetting(flagName, env, usage string, field func(*Config) *time.Duration) setting {
+	return setting{
+		flag:  flagName,
+		env:   env,
+		usage: usage + " (e.g. 30s, 5m, 24h)",
+		set: func(c *Config, value string) error {
+			duration, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+			*field(c) = duration
+			return nil
+		},
+	}
+}
+
+// intSetting creates a setting parsing its value into an integer field
+func intSetting(flagName, env, usage string, field func(*Config) *int) setting {
+	return setting{
+		flag:  flagName,
+		env:   env,
+		usage: usage,
+		set: func(c *Config, value string) error {
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			*field(c) = number
+			return nil
+		},
+	}
+}
+
+// intListSetting creates a setting parsing a comma-separated value into an integer list field
+func intListSetting(flagName, env, usage string, field func(*Config) *[]int) setting {
+	return setting{
+		flag:  flagName,
+		env:   env,
+		usage: usage,
+		set: func(c *Config, value string) error {
+			var numbers []int
+			for _, part := range strings.Split(value, ",") {
+				part = strings.TrimSpace(part)
+				if part == "" {
+					continue
+				}
+				number, err := strconv.Atoi(part)
+				if err != nil {
+					return err
+				}
+				numbers = append(numbers, number)
+			}
+			*field(c) = numbers
+			return nil
+		},
+	}
+}
+
+// boolSetting creates a setting parsing its value into a boolean field
+func boolSetting(flagName, env, usage string, field func(*Config) *bool) setting {
+	return setting{
+		flag:   flagName,
+		env:    env,
+		usage:  usage,
+		isBool: true,
+		set: func(c *Config, value string) error {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+			*field(c) = enabled
+			return nil
+		},
+	}
+}
+
+// validateURL checks that a value is an absolute http(s) URL
+func validateURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%q is not an absolute http(s) URL", value)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlyxPink/gw2-mcp/internal/cache"
+)
+
+// envMap returns an environment lookup backed by a map
+func envMap(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := load(nil, envMap(nil))
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	if cfg.Language != "en" {
+		t.Errorf("Expected default language en, got %q", cfg.Language)
+	}
+	if cfg.Server.Transport != "stdio" {
+		t.Errorf("Expected default transport stdio, got %q", cfg.Server.Transport)
+	}
+	if cfg.Cache.WalletTTL != cache.WalletDataTTL {
+		t.Errorf("Expected default wallet TTL %v, got %v", cache.WalletDataTTL, cfg.Cache.WalletTTL)
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeConfigFile(t, `
+language: de
+server:
+  transport: sse
+  addr: ":9000"
+cache:
+  wallet_ttl: 1m
+  wiki_ttl: 2h
+wiki:
+  base_urls:
+    fr: http://localhost:8081
+storage:
+  database: gw2-mcp.db
+`)
+
+	env := envMap(map[string]string{
+		"GW2MCP_CONFIG":           path,
+		"GW2MCP_LANG":             "fr",
+		"GW2MCP_CACHE_WALLET_TTL": "2m",
+		"GW2MCP_TRANSPORT":        "http",
+		"GW2MCP_MAX_OUTPUT_SIZE":  "4096",
+		"GW2MCP_PRICE_WATCHLIST":  "19721, 19976",
+	})
+
+	cfg, err := load([]string{"-transport", "stdio", "-api-timeout=5s", "-index-items"}, env)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		got      any
+		expected any
+	}{
+		{"file only", cfg.Server.Addr, ":9000"},
+		{"file only duration", cfg.Cache.WikiTTL, 2 * time.Hour},
+		{"file map", cfg.Wiki.BaseURLs["fr"], "http://localhost:8081"},
+		{"env over file", cfg.Language, "fr"},
+		{"env over file duration", cfg.Cache.WalletTTL, 2 * time.Minute},
+		{"flag over env and file", cfg.Server.Transport, "stdio"},
+		{"flag over default", cfg.GW2API.Timeout, 5 * time.Second},
+		{"bool flag without value", cfg.GW2API.IndexItems, true},
+		{"env integer", cfg.Server.MaxOutputSize, 4096},
+		{"default kept", cfg.Cache.StaticTTL, cache.StaticDataTTL},
+		{"file nested string", cfg.Storage.Database, "gw2-mcp.db"},
+		{"default sample interval", cfg.Storage.PriceSampleInterval, 15 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, tt.got)
+			}
+		})
+	}
+
+	if want := []int{19721, 19976}; !slices.Equal(cfg.Storage.PriceWatchlist, want) {
+		t.Errorf("Expected price watchlist %v from env, got %v", want, cfg.Storage.PriceWatchlist)
+	}
+}
+
+func TestLoad_ConfigFlagOverridesEnv(t *testing.T) {
+	fromFlag := writeConfigFile(t, "language: es\n")
+
+	cfg, err := load([]string{"-config", fromFlag}, envMap(map[string]string{
+		"GW2MCP_CONFIG": filepath.Join(t.TempDir(), "missing.yaml"),
+	}))
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	if cfg.Language != "es" {
+		t.Errorf("Expected language from -config file, got %q", cfg.Language)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		file    string
+		wantErr string
+	}{
+		{
+			name:    "invalid language",
+			args:    []string{"-lang", "jp"},
+			wantErr: "language",
+		},
+		{
+			name:    "invalid transport",
+			env:     map[string]string{"GW2MCP_TRANSPORT": "websocket"},
+			wantErr: "server.transport",
+		},
+		{
+			name:    "unparsable duration",
+			env:     map[string]string{"GW2MCP_CACHE_WIKI_TTL": "forever"},
+			wantErr: "GW2MCP_CACHE_WIKI_TTL",
+		},
+		{
+			name:    "non-positive duration",
+			args:    []string{"-api-timeout", "0s"},
+			wantErr: "gw2api.timeout",
+		},
+		{
+			name:    "relative base URL",
+			args:    []string{"-api-base-url", "/v2"},
+			wantErr: "gw2api.base_url",
+		},
+		{
+			name:    "invalid boolean",
+			env:     map[string]string{"GW2MCP_INDEX_ITEMS": "maybe"},
+			wantErr: "GW2MCP_INDEX_ITEMS",
+		},
+		{
+			name:    "unparsable integer",
+			args:    []string{"-max-output-size", "20kb"},
+			wantErr: "max-output-size",
+		},
+		{
+			name:    "negative output size",
+			env:     map[string]string{"GW2MCP_MAX_OUTPUT_SIZE": "-1"},
+			wantErr: "server.max_output_size",
+		},
+		{
+			name:    "offline while recording",
+			args:    []string{"-offline", "-record"},
+			wantErr: "mutually exclusive",
+		},
+		{
+			name:    "offline without fixtures",
+			args:    []string{"-offline", "-fixtures-dir", ""},
+			wantErr: "fixtures.dir",
+		},
+		{
+			name:    "unparsable watchlist",
+			env:     map[string]string{"GW2MCP_PRICE_WATCHLIST": "19721,ecto"},
+			wantErr: "GW2MCP_PRICE_WATCHLIST",
+		},
+		{
+			name:    "watchlist without database",
+			args:    []string{"-price-watchlist", "19721"},
+			wantErr: "storage.database",
+		},
+		{
+			name:    "invalid watchlist item",
+			args:    []string{"-database", "gw2-mcp.db", "-price-watchlist", "19721,-5"},
+			wantErr: "storage.price_watchlist",
+		},
+		{
+			name:    "invalid log level",
+			args:    []string{"-log-level", "loud"},
+			wantErr: "log_level",
+		},
+		{
+			name:    "unknown wiki language in file",
+			file:    "wiki:\n  base_urls:\n    it: https://example.com\n",
+			wantErr: "wiki.base_urls",
+		},
+		{
+			name:    "unknown file key",
+			file:    "cache:\n  ttl: 1h\n",
+			wantErr: "failed to parse config file",
+		},
+		{
+			name:    "missing file",
+			args:    []string{"-config", "does-not-exist.yaml"},
+			wantErr: "failed to read config file",
+		},
+		{
+			name:    "TOML file",
+			args:    []string{"-config", "gw2-mcp.toml"},
+			wantErr: "TOML is not supported, use a YAML file",
+		},
+		{
+			name:    "unknown flag",
+			args:    []string{"-verbose"},
+			wantErr: "flag provided but not defined",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{}
+			for k, v := range tt.env {
+				env[k] = v
+			}
+			if tt.file != "" {
+				env["GW2MCP_CONFIG"] = writeConfigFile(t, tt.file)
+			}
+
+			_, err := load(tt.args, envMap(env))
+			if err == nil {
+				t.Fatalf("Expected error containing %q", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
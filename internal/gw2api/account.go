@@ -0,0 +1,121 @@
+package gw2api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Account represents account details from /v2/account
+type Account struct {
+	ID   string `json:"id"`
+	Name string `json:"name"` // e.g. Name.1234, stable across API keys
+}
+
+// ItemStack is a stack of items in a storage of the account
+type ItemStack struct {
+	ID    int `json:"id"`
+	Count int `json:"count"`
+}
+
+// characterInventory represents /v2/characters/{name}/inventory; empty bags and slots are null
+type characterInventory struct {
+	Bags []*struct {
+		Inventory []*ItemStack `json:"inventory"`
+	} `json:"bags"`
+}
+
+// GetAccount retrieves the details of the account owning the API key
+func (c *Client) GetAccount(ctx context.Context, apiKey string) (*Account, error) {
+	apiKeyHash := HashAPIKey(apiKey)
+	cacheKey := c.cache.GetAccountKey(apiKeyHash)
+
+	// Try cache first
+	var account Account
+	if c.cache.GetJSON(cacheKey, &account) {
+		c.logger.Debug("Account cache hit", "api_key_hash", apiKeyHash)
+		return &account, nil
+	}
+
+	c.logger.Debug("Account cache miss, fetching from API", "api_key_hash", apiKeyHash)
+
+	if err := c.getJSON(ctx, "/account", apiKey, &account); err != nil {
+		return nil, fmt.Errorf("failed to fetch account: %w", err)
+	}
+
+	// Cache the result
+	if err := c.cache.SetJSON(cacheKey, account, c.cache.TTLs().WalletData); err != nil {
+		c.logger.Warn("Failed to cache account", "error", err)
+	}
+
+	return &account, nil
+}
+
+// GetBank retrieves the item stacks in the bank of the account, which needs the inventories scope
+func (c *Client) GetBank(ctx context.Context, apiKey string) ([]ItemStack, error) {
+	return c.getStorage(ctx, apiKey, "bank", "/account/bank")
+}
+
+// GetMaterials retrieves the item stacks in the material storage of the account, which
+// needs the inventories scope
+func (c *Client) GetMaterials(ctx context.Context, apiKey string) ([]ItemStack, error) {
+	return c.getStorage(ctx, apiKey, "materials", "/account/materials")
+}
+
+// GetSharedInventory retrieves the item stacks in the shared inventory slots of the
+// account, which needs the inventories scope
+func (c *Client) GetSharedInventory(ctx context.Context, apiKey string) ([]ItemStack, error) {
+	return c.getStorage(ctx, apiKey, "inventory", "/account/inventory")
+}
+
+// GetCharacterInventory retrieves the item stacks in the bags of a character, which needs
+// the characters and inventories scopes
+func (c *Client) GetCharacterInventory(ctx context.Context, apiKey, name string) ([]ItemStack, error) {
+	return c.getStorage(ctx, apiKey, "character:"+name, "/characters/"+url.PathEscape(name)+"/inventory")
+}
+
+// getStorage retrieves the non-empty item stacks of an account storage, caching them for
+// the account data TTL. Character inventories are flattened across bags.
+func (c *Client) getStorage(ctx context.Context, apiKey, storage, path string) ([]ItemStack, error) {
+	apiKeyHash := HashAPIKey(apiKey)
+	cacheKey := c.cache.GetStorageKey(apiKeyHash, storage)
+
+	// Try cache first
+	var stacks []ItemStack
+	if c.cache.GetJSON(cacheKey, &stacks) {
+		c.logger.Debug("Storage cache hit", "api_key_hash", apiKeyHash, "storage", storage)
+		return stacks, nil
+	}
+
+	c.logger.Debug("Storage cache miss, fetching from API", "api_key_hash", apiKeyHash, "storage", storage)
+
+	var slots []*ItemStack
+	if strings.HasPrefix(storage, "character:") {
+		var inventory characterInventory
+		if err := c.getJSON(ctx, path, apiKey, &inventory); err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", storage, err)
+		}
+		for _, bag := range inventory.Bags {
+			if bag != nil {
+				slots = append(slots, bag.Inventory...)
+			}
+		}
+	} else if err := c.getJSON(ctx, path, apiKey, &slots); err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", storage, err)
+	}
+
+	stacks = []ItemStack{}
+	for _, slot := range slots {
+		if slot != nil && slot.Count > 0 {
+			stacks = append(stacks, *slot)
+		}
+	}
+
+	// Cache the result
+	if err := c.cache.SetJSON(cacheKey, stacks, c.cache.TTLs().WalletData); err != nil {
+		c.logger.Warn("Failed to cache storage", "storage", storage, "error", err)
+	}
+
+	return stacks, nil
+}
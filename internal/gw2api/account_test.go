@@ -0,0 +1,93 @@
+package gw2api
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AlyxPink/gw2-mcp/internal/cache"
+	"github.com/AlyxPink/gw2-mcp/internal/gw2mock"
+)
+
+func TestClient_GetAccount(t *testing.T) {
+	mock := httptest.NewServer(gw2mock.New())
+	defer mock.Close()
+
+	client := NewClient(cache.NewManager(), nil, WithBaseURL(mock.URL+"/v2"))
+	account, err := client.GetAccount(context.Background(), gw2mock.KeyAccount)
+	if err != nil {
+		t.Fatalf("GetAccount failed: %v", err)
+	}
+	if account.Name != "Mock Account.1234" {
+		t.Errorf("Expected Mock Account.1234, got %q", account.Name)
+	}
+}
+
+func TestClient_GetStorage(t *testing.T) {
+	mock := gw2mock.New()
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	client := NewClient(cache.NewManager(), nil, WithBaseURL(server.URL+"/v2"))
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		fetch func() ([]ItemStack, error)
+		want  []ItemStack
+	}{
+		{
+			name:  "bank",
+			fetch: func() ([]ItemStack, error) { return client.GetBank(ctx, gw2mock.KeyFull) },
+			want:  []ItemStack{{ID: 19721, Count: 50}, {ID: 19976, Count: 40}, {ID: 30689, Count: 1}},
+		},
+		{
+			name:  "materials without empty stacks",
+			fetch: func() ([]ItemStack, error) { return client.GetMaterials(ctx, gw2mock.KeyFull) },
+			want:  []ItemStack{{ID: 19721, Count: 120}, {ID: 24277, Count: 800}, {ID: 68063, Count: 12}},
+		},
+		{
+			name:  "shared inventory",
+			fetch: func() ([]ItemStack, error) { return client.GetSharedInventory(ctx, gw2mock.KeyFull) },
+			want:  []ItemStack{{ID: 68063, Count: 2}},
+		},
+		{
+			name: "character bags",
+			fetch: func() ([]ItemStack, error) {
+				return client.GetCharacterInventory(ctx, gw2mock.KeyFull, "Mock Warrior")
+			},
+			want: []ItemStack{{ID: 19721, Count: 10}, {ID: 19675, Count: 5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fetch()
+			if err != nil {
+				t.Fatalf("Fetch failed: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	// Storages are cached per API key
+	requests := mock.Requests()
+	if _, err := client.GetBank(ctx, gw2mock.KeyFull); err != nil {
+		t.Fatalf("GetBank failed: %v", err)
+	}
+	if mock.Requests() != requests {
+		t.Error("Expected the bank to be cached")
+	}
+
+	if _, err := client.GetBank(ctx, gw2mock.KeyWallet); err == nil ||
+		!strings.Contains(err.Error(), "requires scope inventories") {
+		t.Errorf("Expected a missing scope error, got %v", err)
+	}
+	if _, err := client.GetCharacterInventory(ctx, gw2mock.KeyFull, "Nobody"); err == nil {
+		t.Error("Expected an error for an unknown character")
+	}
+}
@@ -0,0 +1,105 @@
+package gw2api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// AchievementTier is one tier of an achievement
+type AchievementTier struct {
+	Count  int `json:"count"`
+	Points int `json:"points"`
+}
+
+// Achievement represents achievement metadata from /v2/achievements, without bits and rewards
+type Achievement struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Requirement string            `json:"requirement"`
+	Type        string            `json:"type"`
+	Flags       []string          `json:"flags"`
+	Tiers       []AchievementTier `json:"tiers"`
+	ID          int               `json:"id"`
+}
+
+// AchievementProgress is the progress of the account on an achievement, from /v2/account/achievements
+type AchievementProgress struct {
+	ID      int  `json:"id"`
+	Current int  `json:"current"`
+	Max     int  `json:"max"`
+	Done    bool `json:"done"`
+}
+
+// GetAchievements retrieves the metadata of several achievements in the given language,
+// keyed by ID. Achievements missing from the cache are fetched in batches of 200; unknown
+// IDs are left out.
+func (c *Client) GetAchievements(ctx context.Context, ids []int, lang Language) (map[int]Achievement, error) {
+	achievements := make(map[int]Achievement, len(ids))
+	var missingIDs []int
+
+	// Check cache for each achievement
+	for _, id := range ids {
+		var achievement Achievement
+		if c.cache.GetJSON(c.cache.GetAchievementDetailKey(string(lang), id), &achievement) {
+			achievements[id] = achievement
+		} else {
+			missingIDs = append(missingIDs, id)
+		}
+	}
+
+	for start := 0; start < len(missingIDs); start += itemBatchSize {
+		batch := missingIDs[start:min(start+itemBatchSize, len(missingIDs))]
+
+		var fetched []Achievement
+		path := "/achievements?ids=" + joinIDs(batch) + "&lang=" + string(lang)
+		if err := c.getJSON(ctx, path, "", &fetched); err != nil {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+				continue // none of the batch exists
+			}
+			return nil, fmt.Errorf("failed to fetch achievements: %w", err)
+		}
+
+		// Add fetched achievements to result and cache
+		for _, achievement := range fetched {
+			achievements[achievement.ID] = achievement
+			cacheKey := c.cache.GetAchievementDetailKey(string(lang), achievement.ID)
+			if err := c.cache.SetJSON(cacheKey, achievement, c.cache.TTLs().StaticData); err != nil {
+				c.logger.Warn("Failed to cache achievement", "id", achievement.ID, "error", err)
+			}
+		}
+	}
+
+	return achievements, nil
+}
+
+// GetAccountAchievements retrieves the achievement progress of the account owning the API
+// key, keyed by achievement ID, which needs the progression scope. Achievements the
+// account has not started are left out.
+func (c *Client) GetAccountAchievements(ctx context.Context, apiKey string) (map[int]AchievementProgress, error) {
+	apiKeyHash := HashAPIKey(apiKey)
+	cacheKey := c.cache.GetAchievementsKey(apiKeyHash)
+
+	// Try cache first
+	var entries []AchievementProgress
+	if !c.cache.GetJSON(cacheKey, &entries) {
+		c.logger.Debug("Achievements cache miss, fetching from API", "api_key_hash", apiKeyHash)
+
+		if err := c.getJSON(ctx, "/account/achievements", apiKey, &entries); err != nil {
+			return nil, fmt.Errorf("failed to fetch achievement progress: %w", err)
+		}
+
+		// Cache the result
+		if err := c.cache.SetJSON(cacheKey, entries, c.cache.TTLs().WalletData); err != nil {
+			c.logger.Warn("Failed to cache achievement progress", "error", err)
+		}
+	}
+
+	byID := make(map[int]AchievementProgress, len(entries))
+	for _, entry := range entries {
+		byID[entry.ID] = entry
+	}
+	return byID, nil
+}
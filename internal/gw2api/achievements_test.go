@@ -0,0 +1,58 @@
+package gw2api
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlyxPink/gw2-mcp/internal/cache"
+	"github.com/AlyxPink/gw2-mcp/internal/gw2mock"
+)
+
+func TestClient_GetAchievements(t *testing.T) {
+	mock := gw2mock.New()
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	client := NewClient(cache.NewManager(), nil, WithBaseURL(server.URL+"/v2"))
+	ctx := context.Background()
+
+	achievements, err := client.GetAchievements(ctx, []int{1, 2, 9999}, LanguageEnglish)
+	if err != nil {
+		t.Fatalf("GetAchievements failed: %v", err)
+	}
+	if len(achievements) != 2 || achievements[1].Name != "Centaur Slayer" || len(achievements[2].Tiers) != 2 {
+		t.Errorf("Expected Centaur Slayer and Ghost Slayer, got %+v", achievements)
+	}
+
+	// Achievements are cached individually
+	requests := mock.Requests()
+	if _, err := client.GetAchievements(ctx, []int{2, 1}, LanguageEnglish); err != nil {
+		t.Fatalf("GetAchievements failed: %v", err)
+	}
+	if mock.Requests() != requests {
+		t.Errorf("Expected cached achievements, got %d more requests", mock.Requests()-requests)
+	}
+}
+
+func TestClient_GetAccountAchievements(t *testing.T) {
+	mock := httptest.NewServer(gw2mock.New())
+	defer mock.Close()
+
+	client := NewClient(cache.NewManager(), nil, WithBaseURL(mock.URL+"/v2"))
+	ctx := context.Background()
+
+	progress, err := client.GetAccountAchievements(ctx, gw2mock.KeyFull)
+	if err != nil {
+		t.Fatalf("GetAccountAchievements failed: %v", err)
+	}
+	if !progress[1].Done || progress[2].Current != 120 || progress[2].Max != 500 {
+		t.Errorf("Expected Centaur Slayer done and 120/500 Ghost Slayer, got %+v", progress)
+	}
+
+	if _, err := client.GetAccountAchievements(ctx, gw2mock.KeyWallet); err == nil ||
+		!strings.Contains(err.Error(), "requires scope progression") {
+		t.Errorf("Expected a missing scope error, got %v", err)
+	}
+}
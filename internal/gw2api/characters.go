@@ -0,0 +1,32 @@
+package gw2api
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetCharacterNames retrieves the character names of the account owning the API key
+func (c *Client) GetCharacterNames(ctx context.Context, apiKey string) ([]string, error) {
+	apiKeyHash := HashAPIKey(apiKey)
+	cacheKey := c.cache.GetCharactersKey(apiKeyHash)
+
+	// Try cache first
+	var names []string
+	if c.cache.GetJSON(cacheKey, &names) {
+		c.logger.Debug("Character names cache hit", "api_key_hash", apiKeyHash)
+		return names, nil
+	}
+
+	c.logger.Debug("Character names cache miss, fetching from API", "api_key_hash", apiKeyHash)
+
+	if err := c.getJSON(ctx, "/characters", apiKey, &names); err != nil {
+		return nil, fmt.Errorf("failed to fetch characters: %w", err)
+	}
+
+	// Characters change rarely, but a new one should show up quickly, so use the account data TTL
+	if err := c.cache.SetJSON(cacheKey, names, c.cache.TTLs().WalletData); err != nil {
+		c.logger.Warn("Failed to cache character names", "error", err)
+	}
+
+	return names, nil
+}
@@ -8,19 +8,23 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
 
 	"github.com/AlyxPink/gw2-mcp/internal/cache"
+	"github.com/AlyxPink/gw2-mcp/internal/progress"
 )
 
 const (
-	baseURL        = "https://api.guildwars2.com/v2"
-	userAgent      = "github.com/AlyxPink/gw2-mcp"
-	requestTimeout = 30 * time.Second
+	// DefaultBaseURL is the base URL of the official GW2 API
+	DefaultBaseURL = "https://api.guildwars2.com/v2"
+	// DefaultTimeout is the default timeout of GW2 API requests
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultUserAgent identifies the server in upstream requests
+	DefaultUserAgent = "github.com/AlyxPink/gw2-mcp"
 )
 
 // Client handles GW2 API requests
@@ -28,6 +32,59 @@ type Client struct {
 	httpClient *http.Client
 	cache      *cache.Manager
 	logger     *log.Logger
+	userAgent  string
+	items      *itemIndex
+	baseURL    string
+
+	// HTTP client settings, applied to a copy of httpClient once all options are set
+	timeout   time.Duration
+	transport http.RoundTripper
+}
+
+// Option configures optional Client settings
+type Option func(*Client)
+
+// WithBaseURL overrides the GW2 API base URL, e.g. to use a mirror or a local mock
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithTransport sets the transport of GW2 API requests, e.g. to record or replay fixtures
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
+
+// WithHTTPClient sets the HTTP client of GW2 API requests, e.g. one going through a proxy.
+// The client is copied, so timeout and transport options never modify it.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header of GW2 API requests
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithLogger replaces the logger given to NewClient
+func WithLogger(logger *log.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithTimeout sets the timeout of GW2 API requests
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
 }
 
 // WalletEntry represents a single currency in the wallet
@@ -54,23 +111,48 @@ type WalletInfo struct {
 }
 
 // NewClient creates a new GW2 API client
-func NewClient(cacheManager *cache.Manager, logger *log.Logger) *Client {
-	return &Client{
-		httpClient: &http.Client{
-			Timeout: requestTimeout,
-		},
-		cache:  cacheManager,
-		logger: logger,
+func NewClient(cacheManager *cache.Manager, logger *log.Logger, opts ...Option) *Client {
+	c := &Client{
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		cache:      cacheManager,
+		logger:     logger,
+		userAgent:  DefaultUserAgent,
+		items:      newItemIndex(),
+		baseURL:    DefaultBaseURL,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	httpClient := *c.httpClient
+	if c.timeout > 0 {
+		httpClient.Timeout = c.timeout
 	}
+	if c.transport != nil {
+		httpClient.Transport = c.transport
+	}
+	c.httpClient = &httpClient
+
+	if c.logger == nil {
+		c.logger = log.New(io.Discard)
+	}
+
+	return c
 }
 
-// GetWallet retrieves wallet information for the given API key
-func (c *Client) GetWallet(ctx context.Context, apiKey string) (*WalletInfo, error) {
-	// Create a hash of the API key for caching (security)
+// HashAPIKey returns a short hash of an API key, used to scope cached account data without storing the key
+func HashAPIKey(apiKey string) string {
 	hash := sha256.Sum256([]byte(apiKey))
-	apiKeyHash := fmt.Sprintf("%x", hash[:8]) // Use first 8 bytes of hash
+	return fmt.Sprintf("%x", hash[:8]) // Use first 8 bytes of hash
+}
 
-	cacheKey := c.cache.GetWalletKey(apiKeyHash)
+// GetWallet retrieves wallet information for the given API key, with currency metadata in the given language
+func (c *Client) GetWallet(ctx context.Context, apiKey string, lang Language) (*WalletInfo, error) {
+	// Create a hash of the API key for caching (security)
+	apiKeyHash := HashAPIKey(apiKey)
+
+	cacheKey := c.cache.GetWalletKey(apiKeyHash, string(lang))
 
 	// Try to get from cache first
 	var walletInfo WalletInfo
@@ -86,6 +168,7 @@ func (c *Client) GetWallet(ctx context.Context, apiKey string) (*WalletInfo, err
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
 	}
+	progress.Report(ctx, 1, 2, "fetched wallet")
 
 	// Get currency metadata for all currencies in wallet
 	currencyIDs := make([]int, len(walletEntries))
@@ -93,12 +176,17 @@ func (c *Client) GetWallet(ctx context.Context, apiKey string) (*WalletInfo, err
 		currencyIDs[i] = entry.ID
 	}
 
-	currencies, err := c.GetCurrencies(ctx, currencyIDs)
+	currencies, err := c.GetCurrencies(ctx, currencyIDs, lang)
 	if err != nil {
+		// A cancelled call must not be cached as a wallet without metadata
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		c.logger.Warn("Failed to get currency metadata", "error", err)
 		// Continue without metadata
 		currencies = make(map[int]Currency)
 	}
+	progress.Report(ctx, 2, 2, "fetched currency metadata")
 
 	// Create wallet info
 	walletInfo = WalletInfo{
@@ -109,18 +197,18 @@ func (c *Client) GetWallet(ctx context.Context, apiKey string) (*WalletInfo, err
 	}
 
 	// Cache the result
-	if err := c.cache.SetJSON(cacheKey, walletInfo, cache.WalletDataTTL); err != nil {
+	if err := c.cache.SetJSON(cacheKey, walletInfo, c.cache.TTLs().WalletData); err != nil {
 		c.logger.Warn("Failed to cache wallet data", "error", err)
 	}
 
 	return &walletInfo, nil
 }
 
-// GetCurrencies retrieves currency metadata
-func (c *Client) GetCurrencies(ctx context.Context, ids []int) (map[int]Currency, error) {
+// GetCurrencies retrieves currency metadata in the given language
+func (c *Client) GetCurrencies(ctx context.Context, ids []int, lang Language) (map[int]Currency, error) {
 	// If no specific IDs requested, get all currencies
 	if len(ids) == 0 {
-		return c.getAllCurrencies(ctx)
+		return c.getAllCurrencies(ctx, lang)
 	}
 
 	// Get specific currencies
@@ -129,7 +217,7 @@ func (c *Client) GetCurrencies(ctx context.Context, ids []int) (map[int]Currency
 
 	// Check cache for each currency
 	for _, id := range ids {
-		cacheKey := c.cache.GetCurrencyDetailKey(id)
+		cacheKey := c.cache.GetCurrencyDetailKey(string(lang), id)
 		var currency Currency
 		if c.cache.GetJSON(cacheKey, &currency) {
 			currencies[id] = currency
@@ -140,7 +228,7 @@ func (c *Client) GetCurrencies(ctx context.Context, ids []int) (map[int]Currency
 
 	// Fetch missing currencies from API
 	if len(missingIDs) > 0 {
-		fetchedCurrencies, err := c.fetchCurrencies(ctx, missingIDs)
+		fetchedCurrencies, err := c.fetchCurrencies(ctx, missingIDs, lang)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch currencies: %w", err)
 		}
@@ -148,8 +236,8 @@ func (c *Client) GetCurrencies(ctx context.Context, ids []int) (map[int]Currency
 		// Add fetched currencies to result and cache
 		for _, currency := range fetchedCurrencies {
 			currencies[currency.ID] = currency
-			cacheKey := c.cache.GetCurrencyDetailKey(currency.ID)
-			if err := c.cache.SetJSON(cacheKey, currency, cache.StaticDataTTL); err != nil {
+			cacheKey := c.cache.GetCurrencyDetailKey(string(lang), currency.ID)
+			if err := c.cache.SetJSON(cacheKey, currency, c.cache.TTLs().StaticData); err != nil {
 				c.logger.Warn("Failed to cache currency", "id", currency.ID, "error", err)
 			}
 		}
@@ -159,17 +247,17 @@ func (c *Client) GetCurrencies(ctx context.Context, ids []int) (map[int]Currency
 }
 
 // getAllCurrencies retrieves all available currencies
-func (c *Client) getAllCurrencies(ctx context.Context) (map[int]Currency, error) {
-	cacheKey := c.cache.GetCurrencyListKey()
+func (c *Client) getAllCurrencies(ctx context.Context, lang Language) (map[int]Currency, error) {
+	cacheKey := c.cache.GetCurrencyListKey(string(lang))
 
 	// Try cache first
 	var currencies map[int]Currency
 	if c.cache.GetJSON(cacheKey, &currencies) {
-		c.logger.Debug("Currency list cache hit")
+		c.logger.Debug("Currency list cache hit", "lang", lang)
 		return currencies, nil
 	}
 
-	c.logger.Debug("Currency list cache miss, fetching from API")
+	c.logger.Debug("Currency list cache miss, fetching from API", "lang", lang)
 
 	// Fetch all currency IDs first
 	currencyIDs, err := c.fetchCurrencyIDs(ctx)
@@ -178,7 +266,7 @@ func (c *Client) getAllCurrencies(ctx context.Context) (map[int]Currency, error)
 	}
 
 	// Fetch all currency details
-	currencyList, err := c.fetchCurrencies(ctx, currencyIDs)
+	currencyList, err := c.fetchCurrencies(ctx, currencyIDs, lang)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch currency details: %w", err)
 	}
@@ -190,22 +278,43 @@ func (c *Client) getAllCurrencies(ctx context.Context) (map[int]Currency, error)
 	}
 
 	// Cache the result
-	if err := c.cache.SetJSON(cacheKey, currencies, cache.StaticDataTTL); err != nil {
+	if err := c.cache.SetJSON(cacheKey, currencies, c.cache.TTLs().StaticData); err != nil {
 		c.logger.Warn("Failed to cache currency list", "error", err)
 	}
 
 	return currencies, nil
 }
 
-// fetchWallet makes the actual API call to get wallet data
-func (c *Client) fetchWallet(ctx context.Context, apiKey string) ([]WalletEntry, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/account/wallet", http.NoBody)
+// APIError is returned when the GW2 API answers with a non-200 status
+type APIError struct {
+	Text       string
+	StatusCode int
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Text)
+}
+
+// getJSON performs a GET request against the GW2 API and decodes the JSON response into dest.
+// The API key is only sent when not empty.
+func (c *Client) getJSON(ctx context.Context, path, apiKey string, dest any) error {
+	_, err := c.getJSONWithHeader(ctx, path, apiKey, dest)
+	return err
+}
+
+// getJSONWithHeader is getJSON also returning the response headers, e.g. the X-Page-Total
+// of paginated endpoints. Bulk requests where only some IDs exist succeed with 206.
+func (c *Client) getJSONWithHeader(ctx context.Context, path, apiKey string, dest any) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, http.NoBody)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("User-Agent", userAgent)
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -217,95 +326,51 @@ func (c *Client) fetchWallet(ctx context.Context, apiKey string) ([]WalletEntry,
 		}
 	}()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
 		body, readErr := io.ReadAll(resp.Body)
 		if readErr != nil {
 			return nil, fmt.Errorf("API request failed with status %d and failed to read body: %w", resp.StatusCode, readErr)
 		}
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, &APIError{StatusCode: resp.StatusCode, Text: apiErrorText(body)}
 	}
 
+	return resp.Header, json.NewDecoder(resp.Body).Decode(dest)
+}
+
+// apiErrorText extracts the message of a GW2 API error body such as {"text":"no such id"}
+func apiErrorText(body []byte) string {
+	var apiErr struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Text != "" {
+		return apiErr.Text
+	}
+	return string(body)
+}
+
+// fetchWallet makes the actual API call to get wallet data
+func (c *Client) fetchWallet(ctx context.Context, apiKey string) ([]WalletEntry, error) {
 	var wallet []WalletEntry
-	if err := json.NewDecoder(resp.Body).Decode(&wallet); err != nil {
+	if err := c.getJSON(ctx, "/account/wallet", apiKey, &wallet); err != nil {
 		return nil, err
 	}
-
 	return wallet, nil
 }
 
 // fetchCurrencyIDs fetches all available currency IDs
 func (c *Client) fetchCurrencyIDs(ctx context.Context) ([]int, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/currencies", http.NoBody)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("User-Agent", userAgent)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			c.logger.Warn("Failed to close response body", "error", closeErr)
-		}
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		body, readErr := io.ReadAll(resp.Body)
-		if readErr != nil {
-			return nil, fmt.Errorf("API request failed with status %d and failed to read body: %w", resp.StatusCode, readErr)
-		}
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
 	var ids []int
-	if err := json.NewDecoder(resp.Body).Decode(&ids); err != nil {
+	if err := c.getJSON(ctx, "/currencies", "", &ids); err != nil {
 		return nil, err
 	}
-
 	return ids, nil
 }
 
 // fetchCurrencies fetches currency details for specific IDs
-func (c *Client) fetchCurrencies(ctx context.Context, ids []int) ([]Currency, error) {
-	// Convert IDs to comma-separated string
-	idStrs := make([]string, len(ids))
-	for i, id := range ids {
-		idStrs[i] = strconv.Itoa(id)
-	}
-	idsParam := strings.Join(idStrs, ",")
-
-	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/currencies?ids="+idsParam, http.NoBody)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("User-Agent", userAgent)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			c.logger.Warn("Failed to close response body", "error", closeErr)
-		}
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		body, readErr := io.ReadAll(resp.Body)
-		if readErr != nil {
-			return nil, fmt.Errorf("API request failed with status %d and failed to read body: %w", resp.StatusCode, readErr)
-		}
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
+func (c *Client) fetchCurrencies(ctx context.Context, ids []int, lang Language) ([]Currency, error) {
 	var currencies []Currency
-	if err := json.NewDecoder(resp.Body).Decode(&currencies); err != nil {
+	if err := c.getJSON(ctx, "/currencies?ids="+joinIDs(ids)+"&lang="+string(lang), "", &currencies); err != nil {
 		return nil, err
 	}
-
 	return currencies, nil
 }
@@ -0,0 +1,430 @@
+package gw2api
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/AlyxPink/gw2-mcp/internal/cache"
+	"github.com/AlyxPink/gw2-mcp/internal/httpfixture"
+)
+
+// recordFixtures re-records the fixtures from the live GW2 API, with the API key
+// taken from GW2_API_KEY: go test ./internal/gw2api -run Replay -record
+var recordFixtures = flag.Bool("record", false, "record fixtures from the live GW2 API")
+
+// fixturesDir holds the fixtures shared by the package tests and the -offline server mode
+var fixturesDir = filepath.Join("..", "..", httpfixture.DefaultDir)
+
+// fixtureTransport returns the transport replaying, or with -record recording, the fixtures
+func fixtureTransport(t *testing.T) (http.RoundTripper, string) {
+	t.Helper()
+
+	if !*recordFixtures {
+		return httpfixture.NewReplayer(fixturesDir), "ANY-KEY"
+	}
+
+	apiKey := os.Getenv("GW2_API_KEY")
+	if apiKey == "" {
+		t.Skip("GW2_API_KEY is required to record fixtures")
+	}
+	return httpfixture.NewRecorder(fixturesDir, nil), apiKey
+}
+
+func TestClient_Replay(t *testing.T) {
+	transport, apiKey := fixtureTransport(t)
+	client := NewClient(cache.NewManager(), log.New(io.Discard), WithTransport(transport))
+	ctx := context.Background()
+
+	t.Run("wallet", func(t *testing.T) {
+		wallet, err := client.GetWallet(ctx, apiKey, LanguageEnglish)
+		if err != nil {
+			t.Fatalf("GetWallet failed: %v", err)
+		}
+		if len(wallet.Entries) == 0 || wallet.Total != len(wallet.Entries) {
+			t.Fatalf("Expected wallet entries and a matching total, got %+v", wallet)
+		}
+		for _, entry := range wallet.Entries {
+			if wallet.Currencies[entry.ID].Name == "" {
+				t.Errorf("Expected metadata for currency %d", entry.ID)
+			}
+		}
+	})
+
+	t.Run("currencies", func(t *testing.T) {
+		currencies, err := client.GetCurrencies(ctx, []int{1, 4}, LanguageEnglish)
+		if err != nil {
+			t.Fatalf("GetCurrencies failed: %v", err)
+		}
+		if currencies[1].Name != "Coin" || currencies[4].Name != "Gem" {
+			t.Errorf("Expected Coin and Gem, got %+v", currencies)
+		}
+		if currencies[1].Icon == "" || currencies[1].Order == 0 {
+			t.Errorf("Expected icon and order to be decoded, got %+v", currencies[1])
+		}
+	})
+
+	t.Run("item", func(t *testing.T) {
+		item, err := client.GetItem(ctx, 19976, LanguageEnglish)
+		if err != nil {
+			t.Fatalf("GetItem failed: %v", err)
+		}
+		if item.Name != "Mystic Coin" || item.Rarity != "Rare" {
+			t.Errorf("Expected the rare Mystic Coin, got %+v", item)
+		}
+	})
+
+	t.Run("unknown item", func(t *testing.T) {
+		_, err := client.GetItem(ctx, 1, LanguageEnglish)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Text != "no such id" {
+			t.Errorf("Expected a 404 APIError with the API message, got %v", err)
+		}
+	})
+}
+
+// apiStub is a test GW2 API recording the requests it receives
+type apiStub struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+// newAPIStub starts a test GW2 API answering with handler
+func newAPIStub(t *testing.T, handler http.HandlerFunc) *apiStub {
+	t.Helper()
+
+	stub := &apiStub{}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stub.mu.Lock()
+		stub.requests = append(stub.requests, r.Clone(context.Background()))
+		stub.mu.Unlock()
+		handler(w, r)
+	}))
+	t.Cleanup(stub.Close)
+
+	return stub
+}
+
+// client returns a client using the stub, with a fresh cache
+func (s *apiStub) client(opts ...Option) *Client {
+	opts = append([]Option{WithBaseURL(s.URL)}, opts...)
+	return NewClient(cache.NewManager(), log.New(io.Discard), opts...)
+}
+
+// requestURIs returns the path and query of every request received so far
+func (s *apiStub) requestURIs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uris := make([]string, len(s.requests))
+	for i, r := range s.requests {
+		uris[i] = r.URL.RequestURI()
+	}
+	return uris
+}
+
+// writeJSON writes a JSON response body with a status
+func writeJSON(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(body))
+}
+
+const testCurrencies = `[{"id":1,"name":"Coin","description":"Gold","order":101,"icon":"coin.png"},` +
+	`{"id":4,"name":"Gem","description":"Purchased","order":103,"icon":"gem.png"}]`
+
+func TestNewClient_Options(t *testing.T) {
+	stub := newAPIStub(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("ids") {
+			writeJSON(w, http.StatusOK, testCurrencies)
+			return
+		}
+		writeJSON(w, http.StatusOK, `[1,4]`)
+	})
+
+	httpClient := &http.Client{Timeout: time.Minute}
+	client := NewClient(cache.NewManager(), nil,
+		WithBaseURL(stub.URL+"/"),
+		WithTimeout(5*time.Second),
+		WithHTTPClient(httpClient),
+		WithUserAgent("gw2-mcp-test/1.0"),
+	)
+
+	if client.httpClient == httpClient || httpClient.Timeout != time.Minute {
+		t.Error("Expected the given HTTP client to be copied, not modified")
+	}
+	if client.httpClient.Timeout != 5*time.Second {
+		t.Errorf("Expected the timeout option to apply regardless of order, got %v", client.httpClient.Timeout)
+	}
+	if client.logger == nil {
+		t.Error("Expected a nil logger to be replaced")
+	}
+
+	if _, err := client.GetCurrencies(context.Background(), nil, LanguageEnglish); err != nil {
+		t.Fatalf("GetCurrencies failed: %v", err)
+	}
+
+	request := stub.requests[0]
+	if got := request.Header.Get("User-Agent"); got != "gw2-mcp-test/1.0" {
+		t.Errorf("Expected the configured user agent, got %q", got)
+	}
+	if request.URL.Path != "/currencies" {
+		t.Errorf("Expected the trailing slash of the base URL to be trimmed, got %q", request.URL.Path)
+	}
+
+	logger := log.New(io.Discard)
+	if NewClient(nil, nil, WithLogger(logger)).logger != logger {
+		t.Error("Expected WithLogger to set the logger")
+	}
+}
+
+func TestClient_GetWallet(t *testing.T) {
+	tests := []struct {
+		name           string
+		wallet         func(w http.ResponseWriter)
+		currencies     func(w http.ResponseWriter)
+		wantErr        string
+		wantStatus     int
+		wantEntries    int
+		wantCurrencies int
+	}{
+		{
+			name:           "merges currency metadata",
+			wallet:         func(w http.ResponseWriter) { writeJSON(w, http.StatusOK, `[{"id":1,"value":100},{"id":4,"value":5}]`) },
+			currencies:     func(w http.ResponseWriter) { writeJSON(w, http.StatusOK, testCurrencies) },
+			wantEntries:    2,
+			wantCurrencies: 2,
+		},
+		{
+			name:           "keeps balances when metadata fails",
+			wallet:         func(w http.ResponseWriter) { writeJSON(w, http.StatusOK, `[{"id":1,"value":100},{"id":4,"value":5}]`) },
+			currencies:     func(w http.ResponseWriter) { writeJSON(w, http.StatusInternalServerError, `{"text":"down"}`) },
+			wantEntries:    2,
+			wantCurrencies: 0,
+		},
+		{
+			name:       "invalid key",
+			wallet:     func(w http.ResponseWriter) { writeJSON(w, http.StatusUnauthorized, `{"text":"Invalid access token"}`) },
+			wantErr:    "status 401",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "missing scope",
+			wallet:     func(w http.ResponseWriter) { writeJSON(w, http.StatusForbidden, `{"text":"requires scope wallet"}`) },
+			wantErr:    "requires scope wallet",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:    "malformed response",
+			wallet:  func(w http.ResponseWriter) { writeJSON(w, http.StatusOK, `{"id":`) },
+			wantErr: "failed to fetch wallet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newAPIStub(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/account/wallet":
+					tt.wallet(w)
+				case "/currencies":
+					tt.currencies(w)
+				default:
+					writeJSON(w, http.StatusNotFound, `{"text":"not found"}`)
+				}
+			})
+			client := stub.client()
+
+			wallet, err := client.GetWallet(context.Background(), "KEY", LanguageGerman)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				var apiErr *APIError
+				if tt.wantStatus != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus) {
+					t.Errorf("Expected an APIError with status %d, got %v", tt.wantStatus, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetWallet failed: %v", err)
+			}
+
+			if len(wallet.Entries) != tt.wantEntries || wallet.Total != tt.wantEntries {
+				t.Errorf("Expected %d entries, got %d (total %d)", tt.wantEntries, len(wallet.Entries), wallet.Total)
+			}
+			if len(wallet.Currencies) != tt.wantCurrencies {
+				t.Errorf("Expected %d currencies, got %d", tt.wantCurrencies, len(wallet.Currencies))
+			}
+			if wallet.UpdatedAt.IsZero() {
+				t.Error("Expected the update time to be set")
+			}
+
+			walletRequest := stub.requests[0]
+			if got := walletRequest.Header.Get("Authorization"); got != "Bearer KEY" {
+				t.Errorf("Expected the API key as bearer token, got %q", got)
+			}
+			if got := stub.requests[1].URL.Query().Get("lang"); got != "de" {
+				t.Errorf("Expected currency metadata in the requested language, got %q", got)
+			}
+		})
+	}
+}
+
+func TestClient_GetWallet_Cache(t *testing.T) {
+	stub := newAPIStub(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/account/wallet" {
+			writeJSON(w, http.StatusOK, `[{"id":1,"value":100}]`)
+			return
+		}
+		writeJSON(w, http.StatusOK, testCurrencies)
+	})
+	client := stub.client()
+	ctx := context.Background()
+
+	for range 2 {
+		if _, err := client.GetWallet(ctx, "KEY-1", LanguageEnglish); err != nil {
+			t.Fatalf("GetWallet failed: %v", err)
+		}
+	}
+	if got := len(stub.requestURIs()); got != 2 {
+		t.Fatalf("Expected the second call to be served from cache, got %d requests", got)
+	}
+
+	// Wallets are cached per API key; currency metadata is shared
+	if _, err := client.GetWallet(ctx, "KEY-2", LanguageEnglish); err != nil {
+		t.Fatalf("GetWallet failed: %v", err)
+	}
+	uris := stub.requestURIs()
+	if len(uris) != 3 || uris[2] != "/account/wallet" {
+		t.Errorf("Expected only the wallet of the other key to be fetched, got %v", uris)
+	}
+}
+
+func TestClient_GetCurrencies(t *testing.T) {
+	tests := []struct {
+		name      string
+		cached    []int
+		ids       []int
+		status    int
+		wantErr   string
+		wantIDs   []int
+		wantURIs  []string
+		lang      Language
+		secondRun bool
+	}{
+		{
+			name:     "specific IDs",
+			ids:      []int{1, 4},
+			status:   http.StatusOK,
+			wantIDs:  []int{1, 4},
+			wantURIs: []string{"/currencies?ids=1,4&lang=en"},
+			lang:     LanguageEnglish,
+		},
+		{
+			name:     "only missing IDs are fetched",
+			cached:   []int{1},
+			ids:      []int{1, 4},
+			status:   http.StatusOK,
+			wantIDs:  []int{1, 4},
+			wantURIs: []string{"/currencies?ids=4&lang=fr"},
+			lang:     LanguageFrench,
+		},
+		{
+			name:      "all currencies are listed then cached",
+			status:    http.StatusOK,
+			wantIDs:   []int{1, 4},
+			wantURIs:  []string{"/currencies", "/currencies?ids=1,4&lang=en"},
+			lang:      LanguageEnglish,
+			secondRun: true,
+		},
+		{
+			name:    "API error",
+			ids:     []int{9999},
+			status:  http.StatusNotFound,
+			wantErr: "all ids provided are invalid",
+			lang:    LanguageEnglish,
+		},
+		{
+			name:    "listing currencies fails",
+			status:  http.StatusServiceUnavailable,
+			wantErr: "status 503",
+			lang:    LanguageEnglish,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newAPIStub(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case tt.status != http.StatusOK:
+					writeJSON(w, tt.status, `{"text":"all ids provided are invalid"}`)
+				case r.URL.Query().Get("ids") == "":
+					writeJSON(w, http.StatusOK, `[1,4]`)
+				case r.URL.Query().Get("ids") == "4":
+					writeJSON(w, http.StatusOK, `[{"id":4,"name":"Gemme","order":103}]`)
+				default:
+					writeJSON(w, http.StatusOK, testCurrencies)
+				}
+			})
+			client := stub.client()
+
+			for _, id := range tt.cached {
+				key := client.cache.GetCurrencyDetailKey(string(tt.lang), id)
+				if err := client.cache.SetJSON(key, Currency{ID: id, Name: "Cached"}, time.Hour); err != nil {
+					t.Fatalf("Failed to seed cache: %v", err)
+				}
+			}
+
+			runs := 1
+			if tt.secondRun {
+				runs = 2
+			}
+			var currencies map[int]Currency
+			var err error
+			for range runs {
+				currencies, err = client.GetCurrencies(context.Background(), tt.ids, tt.lang)
+			}
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+					t.Errorf("Expected an APIError with status %d, got %v", tt.status, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetCurrencies failed: %v", err)
+			}
+
+			if len(currencies) != len(tt.wantIDs) {
+				t.Errorf("Expected %d currencies, got %v", len(tt.wantIDs), currencies)
+			}
+			for _, id := range tt.wantIDs {
+				if currencies[id].ID != id {
+					t.Errorf("Expected currency %d, got %+v", id, currencies[id])
+				}
+			}
+
+			if uris := stub.requestURIs(); !slices.Equal(uris, tt.wantURIs) {
+				t.Errorf("Expected requests %v, got %v", tt.wantURIs, uris)
+			}
+		})
+	}
+}
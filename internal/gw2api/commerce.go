@@ -0,0 +1,548 @@
+package gw2api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlyxPink/gw2-mcp/internal/progress"
+)
+
+// Trading post fees, in percent of the sell price: a listing fee paid when posting a sell
+// order and an exchange fee taken when it sells. Each is at least 1 copper per item.
+const (
+	ListingFeePercent  = 5
+	ExchangeFeePercent = 10
+)
+
+// transactionPageSize is the maximum page size of /v2/commerce/transactions
+const transactionPageSize = 200
+
+// TransactionState selects open trading post orders or past ones
+type TransactionState string
+
+const (
+	// TransactionsCurrent lists open buy and sell orders
+	TransactionsCurrent TransactionState = "current"
+	// TransactionsHistory lists orders fulfilled in the last 90 days
+	TransactionsHistory TransactionState = "history"
+)
+
+// ParseTransactionState validates a transaction state, defaulting to the history
+func ParseTransactionState(s string) (TransactionState, error) {
+	switch state := TransactionState(strings.ToLower(strings.TrimSpace(s))); state {
+	case "":
+		return TransactionsHistory, nil
+	case TransactionsCurrent, TransactionsHistory:
+		return state, nil
+	default:
+		return "", fmt.Errorf("unsupported transaction state %q, expected current or history", s)
+	}
+}
+
+// TransactionType selects buy or sell orders
+type TransactionType string
+
+const (
+	// TransactionBuys are buy orders
+	TransactionBuys TransactionType = "buys"
+	// TransactionSells are sell orders
+	TransactionSells TransactionType = "sells"
+)
+
+// ParseTransactionTypes validates a transaction type: buys, sells, or both when empty
+func ParseTransactionTypes(s string) ([]TransactionType, error) {
+	switch kind := TransactionType(strings.ToLower(strings.TrimSpace(s))); kind {
+	case "", "both":
+		return []TransactionType{TransactionBuys, TransactionSells}, nil
+	case TransactionBuys, TransactionSells:
+		return []TransactionType{kind}, nil
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %q, expected buys, sells or both", s)
+	}
+}
+
+// Transaction represents a trading post order from /v2/commerce/transactions
+type Transaction struct {
+	Created   time.Time  `json:"created"`
+	Purchased *time.Time `json:"purchased,omitempty"` // only set in the history
+	ID        int64      `json:"id"`
+	ItemID    int        `json:"item_id"`
+	Price     int        `json:"price"` // copper per item
+	Quantity  int        `json:"quantity"`
+}
+
+// Time returns when a past order was fulfilled, or when an open order was created
+func (t Transaction) Time() time.Time {
+	if t.Purchased != nil {
+		return *t.Purchased
+	}
+	return t.Created
+}
+
+// SellFees returns the listing and exchange fees of selling quantity items at price each
+func SellFees(price, quantity int) int {
+	return ListingFee(price, quantity) + ExchangeFee(price, quantity)
+}
+
+// ListingFee returns the fee paid upfront when listing quantity items at price each
+func ListingFee(price, quantity int) int {
+	return max((price*ListingFeePercent+50)/100, 1) * quantity
+}
+
+// ExchangeFee returns the fee taken when quantity items listed at price each sell
+func ExchangeFee(price, quantity int) int {
+	return max((price*ExchangeFeePercent+50)/100, 1) * quantity
+}
+
+// TransactionQuery selects the transactions of a report
+type TransactionQuery struct {
+	Since    time.Time // inclusive, zero for no lower bound
+	Until    time.Time // exclusive, zero for no upper bound
+	State    TransactionState
+	Lang     Language
+	Types    []TransactionType
+	Page     int // page of transactions, starting at 1
+	PageSize int
+}
+
+// TransactionEntry is a transaction with its type and item name
+type TransactionEntry struct {
+	Time     time.Time       `json:"time"` // fulfilled, or created for open orders
+	ItemName string          `json:"item_name"`
+	Type     TransactionType `json:"type"`
+	ID       int64           `json:"id"`
+	ItemID   int             `json:"item_id"`
+	Price    int             `json:"price"`
+	Quantity int             `json:"quantity"`
+	Total    int             `json:"total"` // price times quantity, before fees
+}
+
+// TransactionTotals sums transactions of one type
+type TransactionTotals struct {
+	Count    int `json:"count"`
+	Quantity int `json:"quantity"`
+	Coins    int `json:"coins"` // before fees
+}
+
+// ItemProfit sums the transactions of one item
+type ItemProfit struct {
+	ItemName string `json:"item_name"`
+	ItemID   int    `json:"item_id"`
+	Bought   int    `json:"bought"`
+	Spent    int    `json:"spent"`
+	Sold     int    `json:"sold"`
+	Proceeds int    `json:"proceeds"` // after fees
+	Profit   int    `json:"profit"`
+}
+
+// TransactionSummary sums the transactions of a report. Profit is the sell proceeds after
+// fees minus the coins spent on buys, whether or not the same items were bought and sold.
+// Open orders have not been filled yet, so their summary only has the buy and sell totals,
+// i.e. the coins committed to buy orders and the value of listings before fees, and leaves
+// fees, proceeds and profit unset rather than zero.
+type TransactionSummary struct {
+	ProfitFormatted string            `json:"profit_formatted,omitempty"`
+	Items           []ItemProfit      `json:"items,omitempty"` // highest profit first
+	Fees            *int              `json:"fees,omitempty"`
+	Proceeds        *int              `json:"proceeds,omitempty"`
+	Profit          *int              `json:"profit,omitempty"`
+	Buys            TransactionTotals `json:"buys"`
+	Sells           TransactionTotals `json:"sells"`
+}
+
+// TransactionReport holds one page of transactions and the summary of all selected ones
+type TransactionReport struct {
+	Since             *time.Time         `json:"since,omitempty"`
+	Until             *time.Time         `json:"until,omitempty"`
+	State             TransactionState   `json:"state"`
+	Summary           TransactionSummary `json:"summary"`
+	Transactions      []TransactionEntry `json:"transactions"` // newest first
+	Page              int                `json:"page"`
+	PageSize          int                `json:"page_size"`
+	TotalPages        int                `json:"total_pages"`
+	TotalTransactions int                `json:"total_transactions"`
+}
+
+// DeliveryItem is an item waiting in the trading post delivery box
+type DeliveryItem struct {
+	Name  string `json:"name,omitempty"`
+	ID    int    `json:"id"`
+	Count int    `json:"count"`
+}
+
+// Delivery represents the trading post delivery box from /v2/commerce/delivery
+type Delivery struct {
+	CoinsFormatted string         `json:"coins_formatted,omitempty"`
+	Items          []DeliveryItem `json:"items"`
+	Coins          int            `json:"coins"`
+}
+
+// PriceSide holds the best order on one side of the order book of an item
+type PriceSide struct {
+	Quantity  int `json:"quantity"`   // items on offer or requested at all prices
+	UnitPrice int `json:"unit_price"` // highest buy order or lowest sell listing, 0 when there is none
+}
+
+// Price represents the trading post prices of an item from /v2/commerce/prices
+type Price struct {
+	Buys        PriceSide `json:"buys"`
+	Sells       PriceSide `json:"sells"`
+	ID          int       `json:"id"`
+	Whitelisted bool      `json:"whitelisted"`
+}
+
+// ListingTier is the quantity of an item ordered or listed at one price
+type ListingTier struct {
+	Listings  int `json:"listings"` // number of orders or listings
+	UnitPrice int `json:"unit_price"`
+	Quantity  int `json:"quantity"`
+}
+
+// Listing represents the trading post order book of an item from /v2/commerce/listings
+type Listing struct {
+	Buys  []ListingTier `json:"buys"`  // highest price first
+	Sells []ListingTier `json:"sells"` // lowest price first
+	ID    int           `json:"id"`
+}
+
+// GetTransactions retrieves every current or past buy or sell order of the account owning
+// the API key, which needs the tradingpost scope. The API is paged through 200 at a time.
+func (c *Client) GetTransactions(ctx context.Context, apiKey string, state TransactionState,
+	kind TransactionType,
+) ([]Transaction, error) {
+	apiKeyHash := HashAPIKey(apiKey)
+	cacheKey := c.cache.GetTransactionsKey(apiKeyHash, string(state), string(kind))
+
+	// Try cache first
+	var transactions []Transaction
+	if c.cache.GetJSON(cacheKey, &transactions) {
+		c.logger.Debug("Transactions cache hit", "api_key_hash", apiKeyHash, "state", state, "type", kind)
+		return transactions, nil
+	}
+
+	c.logger.Debug("Transactions cache miss, fetching from API", "api_key_hash", apiKeyHash,
+		"state", state, "type", kind)
+
+	transactions = []Transaction{}
+	for page := 0; ; page++ {
+		var batch []Transaction
+		path := fmt.Sprintf("/commerce/transactions/%s/%s?page=%d&page_size=%d", state, kind, page, transactionPageSize)
+		header, err := c.getJSONWithHeader(ctx, path, apiKey, &batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s %s: %w", state, kind, err)
+		}
+		transactions = append(transactions, batch...)
+
+		pages, _ := strconv.Atoi(header.Get("X-Page-Total"))
+		progress.Report(ctx, page+1, pages, fmt.Sprintf("fetched page %d/%d of %s %s", page+1, pages, state, kind))
+		if page+1 >= pages {
+			break
+		}
+	}
+
+	// Cache the result
+	if err := c.cache.SetJSON(cacheKey, transactions, c.cache.TTLs().TradingPost); err != nil {
+		c.logger.Warn("Failed to cache transactions", "error", err)
+	}
+
+	return transactions, nil
+}
+
+// GetTransactionReport retrieves the transactions selected by query with item names, and
+// sums them per type and per item. Only the requested page of transactions is returned.
+func (c *Client) GetTransactionReport(ctx context.Context, apiKey string,
+	query TransactionQuery,
+) (*TransactionReport, error) {
+	if query.PageSize <= 0 || query.Page < 1 {
+		return nil, fmt.Errorf("invalid page %d of size %d", query.Page, query.PageSize)
+	}
+
+	var entries []TransactionEntry
+	for i, kind := range query.Types {
+		transactions, err := c.GetTransactions(progress.Sub(ctx, i+1, len(query.Types)), apiKey, query.State, kind)
+		if err != nil {
+			return nil, err
+		}
+		progress.Report(ctx, i+1, len(query.Types), fmt.Sprintf("fetched %s %s", query.State, kind))
+		for _, transaction := range transactions {
+			at := transaction.Time()
+			if (!query.Since.IsZero() && at.Before(query.Since)) || (!query.Until.IsZero() && !at.Before(query.Until)) {
+				continue
+			}
+			entries = append(entries, TransactionEntry{
+				Time:     at,
+				Type:     kind,
+				ID:       transaction.ID,
+				ItemID:   transaction.ItemID,
+				Price:    transaction.Price,
+				Quantity: transaction.Quantity,
+				Total:    transaction.Price * transaction.Quantity,
+			})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].Time.Equal(entries[j].Time) {
+			return entries[i].Time.After(entries[j].Time)
+		}
+		return entries[i].ID > entries[j].ID
+	})
+
+	c.addItemNames(ctx, entries, query.Lang)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	report := TransactionReport{
+		State:             query.State,
+		Summary:           summarizeTransactions(entries, query.State),
+		Page:              query.Page,
+		PageSize:          query.PageSize,
+		TotalTransactions: len(entries),
+	}
+	if !query.Since.IsZero() {
+		report.Since = &query.Since
+	}
+	if !query.Until.IsZero() {
+		report.Until = &query.Until
+	}
+
+	report.TotalPages = (len(entries) + query.PageSize - 1) / query.PageSize
+	if query.Page > max(report.TotalPages, 1) {
+		return nil, fmt.Errorf("page %d out of range, %d pages available", query.Page, report.TotalPages)
+	}
+	start := (query.Page - 1) * query.PageSize
+	report.Transactions = entries[start:min(start+query.PageSize, len(entries))]
+
+	return &report, nil
+}
+
+// addItemNames sets the item names of transactions, leaving them empty when the names
+// cannot be fetched
+func (c *Client) addItemNames(ctx context.Context, entries []TransactionEntry, lang Language) {
+	ids := make([]int, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ItemID
+	}
+
+	names, err := c.GetItemNames(ctx, ids, lang)
+	if err != nil {
+		c.logger.Warn("Failed to get item names", "error", err)
+		return
+	}
+	for i := range entries {
+		entries[i].ItemName = names[entries[i].ItemID]
+	}
+}
+
+// summarizeTransactions sums transactions per type, and for the history the fees, proceeds
+// and profit overall and per item
+func summarizeTransactions(entries []TransactionEntry, state TransactionState) TransactionSummary {
+	var summary TransactionSummary
+	if state == TransactionsCurrent {
+		for _, entry := range entries {
+			switch entry.Type {
+			case TransactionBuys:
+				summary.Buys.add(entry)
+			case TransactionSells:
+				summary.Sells.add(entry)
+			}
+		}
+		return summary
+	}
+
+	items := make(map[int]*ItemProfit)
+	fees := 0
+	for _, entry := range entries {
+		item, ok := items[entry.ItemID]
+		if !ok {
+			item = &ItemProfit{ItemID: entry.ItemID, ItemName: entry.ItemName}
+			items[entry.ItemID] = item
+		}
+
+		switch entry.Type {
+		case TransactionBuys:
+			summary.Buys.add(entry)
+			item.Bought += entry.Quantity
+			item.Spent += entry.Total
+		case TransactionSells:
+			summary.Sells.add(entry)
+			sellFees := SellFees(entry.Price, entry.Quantity)
+			fees += sellFees
+			item.Sold += entry.Quantity
+			item.Proceeds += entry.Total - sellFees
+		}
+	}
+
+	proceeds := summary.Sells.Coins - fees
+	profit := proceeds - summary.Buys.Coins
+	summary.Fees, summary.Proceeds, summary.Profit = &fees, &proceeds, &profit
+	summary.ProfitFormatted = FormatCoins(profit)
+
+	summary.Items = make([]ItemProfit, 0, len(items))
+	for _, item := range items {
+		item.Profit = item.Proceeds - item.Spent
+		summary.Items = append(summary.Items, *item)
+	}
+	sort.Slice(summary.Items, func(i, j int) bool {
+		if summary.Items[i].Profit != summary.Items[j].Profit {
+			return summary.Items[i].Profit > summary.Items[j].Profit
+		}
+		return summary.Items[i].ItemID < summary.Items[j].ItemID
+	})
+
+	return summary
+}
+
+// add counts a transaction in the totals
+func (t *TransactionTotals) add(entry TransactionEntry) {
+	t.Count++
+	t.Quantity += entry.Quantity
+	t.Coins += entry.Total
+}
+
+// GetDelivery retrieves the coins and items waiting in the trading post delivery box of the
+// account owning the API key, which needs the tradingpost scope, with item names
+func (c *Client) GetDelivery(ctx context.Context, apiKey string, lang Language) (*Delivery, error) {
+	apiKeyHash := HashAPIKey(apiKey)
+	cacheKey := c.cache.GetDeliveryKey(apiKeyHash)
+
+	// Try cache first
+	var delivery Delivery
+	if c.cache.GetJSON(cacheKey, &delivery) {
+		c.logger.Debug("Delivery cache hit", "api_key_hash", apiKeyHash)
+	} else {
+		c.logger.Debug("Delivery cache miss, fetching from API", "api_key_hash", apiKeyHash)
+
+		if err := c.getJSON(ctx, "/commerce/delivery", apiKey, &delivery); err != nil {
+			return nil, fmt.Errorf("failed to fetch delivery box: %w", err)
+		}
+
+		// Cache the result, without names since they depend on the language
+		if err := c.cache.SetJSON(cacheKey, delivery, c.cache.TTLs().TradingPost); err != nil {
+			c.logger.Warn("Failed to cache delivery box", "error", err)
+		}
+	}
+
+	delivery.CoinsFormatted = FormatCoins(delivery.Coins)
+	if delivery.Items == nil {
+		delivery.Items = []DeliveryItem{}
+	}
+
+	ids := make([]int, len(delivery.Items))
+	for i, item := range delivery.Items {
+		ids[i] = item.ID
+	}
+	names, err := c.GetItemNames(ctx, ids, lang)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		c.logger.Warn("Failed to get item names", "error", err)
+	}
+	for i := range delivery.Items {
+		delivery.Items[i].Name = names[delivery.Items[i].ID]
+	}
+
+	return &delivery, nil
+}
+
+// GetPrices retrieves the current trading post prices of several items, keyed by ID.
+// Prices missing from the cache are fetched in batches of 200; items that cannot be
+// traded are left out.
+func (c *Client) GetPrices(ctx context.Context, ids []int) (map[int]Price, error) {
+	return getTradingPostRecords(ctx, c, ids, "prices", c.cache.GetPriceKey, func(price Price) int { return price.ID })
+}
+
+// GetListings retrieves the trading post order books of several items, keyed by ID.
+// Order books missing from the cache are fetched in batches of 200; items that cannot be
+// traded are left out.
+func (c *Client) GetListings(ctx context.Context, ids []int) (map[int]Listing, error) {
+	return getTradingPostRecords(ctx, c, ids, "listings", c.cache.GetListingsKey,
+		func(listing Listing) int { return listing.ID })
+}
+
+// GetTradedItemIDs retrieves the IDs of every item traded on the trading post
+func (c *Client) GetTradedItemIDs(ctx context.Context) ([]int, error) {
+	cacheKey := c.cache.GetTradedItemsKey()
+
+	// Try cache first
+	var ids []int
+	if c.cache.GetJSON(cacheKey, &ids) {
+		c.logger.Debug("Traded items cache hit", "items", len(ids))
+		return ids, nil
+	}
+
+	c.logger.Debug("Traded items cache miss, fetching from API")
+
+	if err := c.getJSON(ctx, "/commerce/prices", "", &ids); err != nil {
+		return nil, fmt.Errorf("failed to fetch traded item IDs: %w", err)
+	}
+
+	// Cache the result
+	if err := c.cache.SetJSON(cacheKey, ids, c.cache.TTLs().TradingPost); err != nil {
+		c.logger.Warn("Failed to cache traded item IDs", "error", err)
+	}
+
+	return ids, nil
+}
+
+// getTradingPostRecords retrieves records of a bulk /commerce endpoint keyed by item ID,
+// caching each record for the trading post TTL
+func getTradingPostRecords[T any](ctx context.Context, c *Client, ids []int, endpoint string,
+	cacheKey func(id int) string, idOf func(T) int,
+) (map[int]T, error) {
+	records := make(map[int]T, len(ids))
+	seen := make(map[int]bool, len(ids))
+	var missingIDs []int
+
+	// Check cache for each item
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		var record T
+		if c.cache.GetJSON(cacheKey(id), &record) {
+			records[id] = record
+		} else {
+			missingIDs = append(missingIDs, id)
+		}
+	}
+
+	c.logger.Debug("Fetching "+endpoint, "cached", len(records), "missing", len(missingIDs))
+
+	for start := 0; start < len(missingIDs); start += itemBatchSize {
+		batch := missingIDs[start:min(start+itemBatchSize, len(missingIDs))]
+
+		var fetched []T
+		if err := c.getJSON(ctx, "/commerce/"+endpoint+"?ids="+joinIDs(batch), "", &fetched); err != nil {
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+				return nil, fmt.Errorf("failed to fetch %s: %w", endpoint, err)
+			}
+			// none of the batch is traded
+		}
+
+		// Add fetched records to result and cache
+		for _, record := range fetched {
+			id := idOf(record)
+			records[id] = record
+			if err := c.cache.SetJSON(cacheKey(id), record, c.cache.TTLs().TradingPost); err != nil {
+				c.logger.Warn("Failed to cache "+endpoint, "id", id, "error", err)
+			}
+		}
+
+		done := start + len(batch)
+		progress.Report(ctx, done, len(missingIDs), fmt.Sprintf("fetched %s of %d/%d items", endpoint, done,
+			len(missingIDs)))
+	}
+
+	return records, nil
+}
@@ -0,0 +1,340 @@
+package gw2api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlyxPink/gw2-mcp/internal/cache"
+	"github.com/AlyxPink/gw2-mcp/internal/gw2mock"
+)
+
+func TestParseTransactionTypes(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []TransactionType
+		wantErr bool
+	}{
+		{input: "", want: []TransactionType{TransactionBuys, TransactionSells}},
+		{input: "both", want: []TransactionType{TransactionBuys, TransactionSells}},
+		{input: "Sells", want: []TransactionType{TransactionSells}},
+		{input: "orders", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTransactionTypes(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSellFees(t *testing.T) {
+	tests := []struct {
+		name     string
+		price    int
+		quantity int
+		want     int
+	}{
+		{name: "rounded percentages", price: 2600, quantity: 1, want: 390},
+		{name: "per item", price: 5400, quantity: 3, want: 2430},
+		{name: "rounded half up", price: 1210, quantity: 1, want: 182},
+		{name: "at least 1 copper each", price: 3, quantity: 10, want: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SellFees(tt.price, tt.quantity); got != tt.want {
+				t.Errorf("SellFees(%d, %d) = %d, want %d", tt.price, tt.quantity, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_GetTransactions(t *testing.T) {
+	stub := newAPIStub(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Page-Total", "2")
+		if r.URL.Query().Get("page") == "0" {
+			writeJSON(w, http.StatusOK, `[{"id":2,"item_id":19721,"price":2600,"quantity":1,`+
+				`"created":"2026-09-04T19:30:00+00:00","purchased":"2026-09-05T14:00:00+00:00"}]`)
+			return
+		}
+		writeJSON(w, http.StatusOK, `[{"id":1,"item_id":19976,"price":10300,"quantity":2,`+
+			`"created":"2026-09-01T10:00:00+00:00","purchased":"2026-09-01T10:05:00+00:00"}]`)
+	})
+	client := stub.client()
+
+	for range 2 {
+		transactions, err := client.GetTransactions(context.Background(), "KEY", TransactionsHistory, TransactionSells)
+		if err != nil {
+			t.Fatalf("GetTransactions failed: %v", err)
+		}
+		if len(transactions) != 2 || transactions[0].ID != 2 || transactions[1].ID != 1 {
+			t.Fatalf("Expected both pages in order, got %+v", transactions)
+		}
+		if want := time.Date(2026, 9, 5, 14, 0, 0, 0, time.UTC); !transactions[0].Time().Equal(want) {
+			t.Errorf("Expected purchase time %v, got %v", want, transactions[0].Time())
+		}
+	}
+
+	// The second call is served from the cache
+	want := []string{
+		"/commerce/transactions/history/sells?page=0&page_size=200",
+		"/commerce/transactions/history/sells?page=1&page_size=200",
+	}
+	if uris := stub.requestURIs(); !slices.Equal(uris, want) {
+		t.Errorf("Expected requests %v, got %v", want, uris)
+	}
+	if auth := stub.requests[0].Header.Get("Authorization"); auth != "Bearer KEY" {
+		t.Errorf("Expected the API key as bearer token, got %q", auth)
+	}
+}
+
+func TestClient_GetTransactionReport(t *testing.T) {
+	mock := httptest.NewServer(gw2mock.New())
+	defer mock.Close()
+
+	tests := []struct {
+		name         string
+		key          string
+		query        TransactionQuery
+		wantErr      string
+		wantIDs      []int64
+		wantSummary  TransactionSummary
+		wantItems    []int
+		wantPages    int
+		wantTotal    int
+		wantFirstRow string
+	}{
+		{
+			name:    "history",
+			key:     gw2mock.KeyTradingPost,
+			query:   TransactionQuery{State: TransactionsHistory, Page: 1, PageSize: 50},
+			wantIDs: []int64{6003, 5003, 6002, 5002, 6001, 5001},
+			wantSummary: TransactionSummary{
+				Buys:     TransactionTotals{Count: 3, Quantity: 510, Coins: 978000},
+				Sells:    TransactionTotals{Count: 3, Quantity: 353, Coins: 787200},
+				Fees:     intPtr(118130),
+				Proceeds: intPtr(669070),
+				Profit:   intPtr(-308930),
+			},
+			wantItems:    []int{68063, 19721, 19976, 24277},
+			wantPages:    1,
+			wantTotal:    6,
+			wantFirstRow: "Amalgamated Gemstone",
+		},
+		{
+			name: "date range",
+			key:  gw2mock.KeyTradingPost,
+			query: TransactionQuery{
+				State:    TransactionsHistory,
+				Since:    time.Date(2026, 9, 5, 0, 0, 0, 0, time.UTC),
+				Until:    time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+				Page:     1,
+				PageSize: 50,
+			},
+			wantIDs: []int64{6002, 5002, 6001},
+			wantSummary: TransactionSummary{
+				Buys:     TransactionTotals{Count: 1, Quantity: 250, Coins: 275000},
+				Sells:    TransactionTotals{Count: 2, Quantity: 350, Coins: 771000},
+				Fees:     intPtr(115700),
+				Proceeds: intPtr(655300),
+				Profit:   intPtr(380300),
+			},
+			wantItems:    []int{19721, 24277},
+			wantPages:    1,
+			wantTotal:    3,
+			wantFirstRow: "Pile of Crystalline Dust",
+		},
+		{
+			name: "second page of sells",
+			key:  gw2mock.KeyFull,
+			query: TransactionQuery{
+				State:    TransactionsHistory,
+				Types:    []TransactionType{TransactionSells},
+				Page:     2,
+				PageSize: 2,
+			},
+			wantIDs: []int64{6001},
+			wantSummary: TransactionSummary{
+				Sells:    TransactionTotals{Count: 3, Quantity: 353, Coins: 787200},
+				Fees:     intPtr(118130),
+				Proceeds: intPtr(669070),
+				Profit:   intPtr(669070),
+			},
+			wantItems:    []int{19721, 24277, 68063},
+			wantPages:    2,
+			wantTotal:    3,
+			wantFirstRow: "Glob of Ectoplasm",
+		},
+		{
+			name:    "open orders",
+			key:     gw2mock.KeyTradingPost,
+			query:   TransactionQuery{State: TransactionsCurrent, Page: 1, PageSize: 50},
+			wantIDs: []int64{7002, 7001},
+			// Nothing was filled yet, so there are no fees, proceeds or profit
+			wantSummary: TransactionSummary{
+				Buys:  TransactionTotals{Count: 1, Quantity: 25, Coins: 256250},
+				Sells: TransactionTotals{Count: 1, Quantity: 100, Coins: 265000},
+			},
+			wantPages:    1,
+			wantTotal:    2,
+			wantFirstRow: "Glob of Ectoplasm",
+		},
+		{
+			name:    "page out of range",
+			key:     gw2mock.KeyTradingPost,
+			query:   TransactionQuery{State: TransactionsCurrent, Page: 3, PageSize: 50},
+			wantErr: "page 3 out of range",
+		},
+		{
+			name:    "missing scope",
+			key:     gw2mock.KeyWallet,
+			query:   TransactionQuery{State: TransactionsCurrent, Page: 1, PageSize: 50},
+			wantErr: "requires scope tradingpost",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.query.Types == nil {
+				tt.query.Types = []TransactionType{TransactionBuys, TransactionSells}
+			}
+			tt.query.Lang = LanguageEnglish
+
+			client := NewClient(cache.NewManager(), nil, WithBaseURL(mock.URL+"/v2"))
+			report, err := client.GetTransactionReport(context.Background(), tt.key, tt.query)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetTransactionReport failed: %v", err)
+			}
+
+			ids := make([]int64, len(report.Transactions))
+			for i, entry := range report.Transactions {
+				ids[i] = entry.ID
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("Expected transactions %v, got %v", tt.wantIDs, ids)
+			}
+			if report.Transactions[0].ItemName != tt.wantFirstRow {
+				t.Errorf("Expected first item name %q, got %q", tt.wantFirstRow, report.Transactions[0].ItemName)
+			}
+			if report.TotalPages != tt.wantPages || report.TotalTransactions != tt.wantTotal {
+				t.Errorf("Expected %d transactions on %d pages, got %d on %d",
+					tt.wantTotal, tt.wantPages, report.TotalTransactions, report.TotalPages)
+			}
+
+			summary := report.Summary
+			items := make([]int, len(summary.Items))
+			for i, item := range summary.Items {
+				items[i] = item.ItemID
+			}
+			if !slices.Equal(items, tt.wantItems) {
+				t.Errorf("Expected items by profit %v, got %v", tt.wantItems, items)
+			}
+			if tt.query.State == TransactionsCurrent && summary.ProfitFormatted != "" {
+				t.Errorf("Expected no profit for open orders, got %q", summary.ProfitFormatted)
+			}
+			summary.Items, summary.ProfitFormatted = nil, ""
+			if !reflect.DeepEqual(summary, tt.wantSummary) {
+				want, _ := json.Marshal(tt.wantSummary)
+				got, _ := json.Marshal(summary)
+				t.Errorf("Expected summary %s, got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestSummarizeTransactions_BreakEven(t *testing.T) {
+	// 100 copper of sells lose 15 to fees, which is what the buy cost
+	entries := []TransactionEntry{
+		{Type: TransactionBuys, ID: 1, ItemID: 19721, Price: 85, Quantity: 1, Total: 85},
+		{Type: TransactionSells, ID: 2, ItemID: 19721, Price: 100, Quantity: 1, Total: 100},
+	}
+
+	data, err := json.Marshal(summarizeTransactions(entries, TransactionsHistory))
+	if err != nil {
+		t.Fatalf("Failed to encode summary: %v", err)
+	}
+	for _, want := range []string{`"fees":15`, `"proceeds":85`, `"profit":0`, `"profit_formatted":"0c"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected summary to contain %s, got %s", want, data)
+		}
+	}
+}
+
+// intPtr returns a pointer to n
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestClient_GetDelivery(t *testing.T) {
+	mock := httptest.NewServer(gw2mock.New())
+	defer mock.Close()
+
+	client := NewClient(cache.NewManager(), nil, WithBaseURL(mock.URL+"/v2"))
+	delivery, err := client.GetDelivery(context.Background(), gw2mock.KeyTradingPost, LanguageEnglish)
+	if err != nil {
+		t.Fatalf("GetDelivery failed: %v", err)
+	}
+
+	if delivery.Coins != 1234567 || delivery.CoinsFormatted != "123g 45s 67c" {
+		t.Errorf("Expected 123g 45s 67c, got %d (%s)", delivery.Coins, delivery.CoinsFormatted)
+	}
+	want := []DeliveryItem{{ID: 19721, Name: "Glob of Ectoplasm", Count: 250}, {ID: 68063, Name: "Amalgamated Gemstone", Count: 1}}
+	if !slices.Equal(delivery.Items, want) {
+		t.Errorf("Expected items %+v, got %+v", want, delivery.Items)
+	}
+
+	if _, err := client.GetDelivery(context.Background(), gw2mock.KeyAccount, LanguageEnglish); err == nil ||
+		!strings.Contains(err.Error(), "requires scope tradingpost") {
+		t.Errorf("Expected a missing scope error, got %v", err)
+	}
+}
+
+func TestClient_GetPrices(t *testing.T) {
+	mock := gw2mock.New()
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	client := NewClient(cache.NewManager(), nil, WithBaseURL(server.URL+"/v2"))
+
+	// Mystic Clover is account bound and has no prices
+	for range 2 {
+		prices, err := client.GetPrices(context.Background(), []int{19721, 19675, 19721})
+		if err != nil {
+			t.Fatalf("GetPrices failed: %v", err)
+		}
+		if len(prices) != 1 {
+			t.Fatalf("Expected the price of 1 item, got %+v", prices)
+		}
+		if price := prices[19721]; price.Buys.UnitPrice != 2412 || price.Sells.UnitPrice != 2577 {
+			t.Errorf("Expected 2412/2577, got %+v", price)
+		}
+	}
+
+	// The second call only asks for the item without prices again
+	if requests := mock.Requests(); requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+
+	if prices, err := client.GetPrices(context.Background(), []int{19675}); err != nil || len(prices) != 0 {
+		t.Errorf("Expected no prices for an untradeable item, got %+v (%v)", prices, err)
+	}
+}
@@ -0,0 +1,202 @@
+package gw2api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlyxPink/gw2-mcp/internal/progress"
+)
+
+// Coin denominations in copper, the unit the API uses for every coin amount
+const (
+	CopperPerSilver = 100
+	CopperPerGold   = 100 * CopperPerSilver
+)
+
+// ExchangeFrom is the currency given to the gem exchange
+type ExchangeFrom string
+
+const (
+	// ExchangeCoins buys gems with coins
+	ExchangeCoins ExchangeFrom = "coins"
+	// ExchangeGems sells gems for coins
+	ExchangeGems ExchangeFrom = "gems"
+)
+
+// ParseExchangeFrom validates the currency given to the gem exchange
+func ParseExchangeFrom(s string) (ExchangeFrom, error) {
+	switch from := ExchangeFrom(strings.ToLower(strings.TrimSpace(s))); from {
+	case ExchangeCoins, ExchangeGems:
+		return from, nil
+	default:
+		return "", fmt.Errorf("unsupported currency %q, expected coins or gems", s)
+	}
+}
+
+// ExchangeRate is a quote of /v2/commerce/exchange/coins or /v2/commerce/exchange/gems
+type ExchangeRate struct {
+	CoinsPerGem int `json:"coins_per_gem"`
+	Quantity    int `json:"quantity"` // gems received for coins, or copper received for gems
+}
+
+// GemExchangeQuote is the outcome of exchanging one quantity of coins or gems
+type GemExchangeQuote struct {
+	Error           string  `json:"error,omitempty"`
+	CoinsFormatted  string  `json:"coins_formatted"`
+	Coins           int     `json:"coins"` // copper given or received
+	Gems            int     `json:"gems"`  // gems received or given
+	CoinsPerGem     int     `json:"coins_per_gem"`
+	SlippagePercent float64 `json:"slippage_percent"` // rate lost compared to the best quote
+}
+
+// GemExchange holds quotes of the gem exchange for several quantities
+type GemExchange struct {
+	UpdatedAt time.Time          `json:"updated_at"`
+	From      ExchangeFrom       `json:"from"`
+	Quotes    []GemExchangeQuote `json:"quotes"`
+}
+
+// GetExchangeRate quotes exchanging quantity copper for gems, or quantity gems for copper
+func (c *Client) GetExchangeRate(ctx context.Context, from ExchangeFrom, quantity int) (*ExchangeRate, error) {
+	cacheKey := c.cache.GetGemExchangeKey(string(from), quantity)
+
+	// Try cache first
+	var rate ExchangeRate
+	if c.cache.GetJSON(cacheKey, &rate) {
+		c.logger.Debug("Gem exchange cache hit", "from", from, "quantity", quantity)
+		return &rate, nil
+	}
+
+	c.logger.Debug("Gem exchange cache miss, fetching from API", "from", from, "quantity", quantity)
+
+	path := "/commerce/exchange/" + string(from) + "?quantity=" + strconv.Itoa(quantity)
+	if err := c.getJSON(ctx, path, "", &rate); err != nil {
+		return nil, fmt.Errorf("failed to fetch gem exchange rate for %d %s: %w", quantity, from, err)
+	}
+
+	// Cache the result
+	if err := c.cache.SetJSON(cacheKey, rate, c.cache.TTLs().TradingPost); err != nil {
+		c.logger.Warn("Failed to cache gem exchange rate", "error", err)
+	}
+
+	return &rate, nil
+}
+
+// GetGemExchange quotes exchanging each quantity of coins (in copper) or gems, in
+// ascending order. Larger exchanges move the rate, so each quote reports its slippage
+// compared to the best rate. A quantity the exchange refuses, e.g. too few coins to buy
+// a single gem, is reported in its quote; an error is only returned when all fail.
+func (c *Client) GetGemExchange(ctx context.Context, from ExchangeFrom, quantities []int) (*GemExchange, error) {
+	if len(quantities) == 0 {
+		return nil, fmt.Errorf("no quantities to quote")
+	}
+
+	sorted := append([]int(nil), quantities...)
+	sort.Ints(sorted)
+
+	exchange := GemExchange{
+		From:      from,
+		Quotes:    make([]GemExchangeQuote, 0, len(sorted)),
+		UpdatedAt: time.Now(),
+	}
+
+	var firstErr error
+	succeeded := 0
+	for i, quantity := range sorted {
+		rate, err := c.GetExchangeRate(ctx, from, quantity)
+		switch {
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case err != nil:
+			if firstErr == nil {
+				firstErr = err
+			}
+			exchange.Quotes = append(exchange.Quotes, newFailedQuote(from, quantity, err))
+		default:
+			exchange.Quotes = append(exchange.Quotes, newGemExchangeQuote(from, quantity, rate))
+			succeeded++
+		}
+		progress.Report(ctx, i+1, len(sorted), fmt.Sprintf("quoted %d %s", quantity, from))
+	}
+
+	if succeeded == 0 {
+		return nil, firstErr
+	}
+
+	best := bestRate(from, exchange.Quotes)
+	for i := range exchange.Quotes {
+		quote := &exchange.Quotes[i]
+		if quote.Error == "" && best > 0 {
+			quote.SlippagePercent = math.Round(math.Abs(float64(quote.CoinsPerGem-best))/float64(best)*10000) / 100
+		}
+	}
+
+	return &exchange, nil
+}
+
+// newGemExchangeQuote builds the quote of an exchange rate
+func newGemExchangeQuote(from ExchangeFrom, quantity int, rate *ExchangeRate) GemExchangeQuote {
+	quote := GemExchangeQuote{CoinsPerGem: rate.CoinsPerGem}
+	if from == ExchangeCoins {
+		quote.Coins, quote.Gems = quantity, rate.Quantity
+	} else {
+		quote.Coins, quote.Gems = rate.Quantity, quantity
+	}
+	quote.CoinsFormatted = FormatCoins(quote.Coins)
+	return quote
+}
+
+// newFailedQuote builds the quote of a quantity the exchange refused
+func newFailedQuote(from ExchangeFrom, quantity int, err error) GemExchangeQuote {
+	quote := GemExchangeQuote{Error: err.Error()}
+	if from == ExchangeCoins {
+		quote.Coins = quantity
+		quote.CoinsFormatted = FormatCoins(quantity)
+	} else {
+		quote.Gems = quantity
+	}
+	return quote
+}
+
+// bestRate returns the best coins per gem among successful quotes: the lowest when
+// buying gems, the highest when selling them. It returns 0 without any usable rate.
+func bestRate(from ExchangeFrom, quotes []GemExchangeQuote) int {
+	best := 0
+	for _, quote := range quotes {
+		if quote.Error != "" || quote.CoinsPerGem <= 0 {
+			continue
+		}
+		if best == 0 || (from == ExchangeCoins && quote.CoinsPerGem < best) ||
+			(from == ExchangeGems && quote.CoinsPerGem > best) {
+			best = quote.CoinsPerGem
+		}
+	}
+	return best
+}
+
+// FormatCoins formats an amount of copper as gold, silver and copper, e.g. 12g 34s 56c
+func FormatCoins(copper int) string {
+	sign := ""
+	if copper < 0 {
+		sign, copper = "-", -copper
+	}
+
+	gold, silver, rest := copper/CopperPerGold, copper%CopperPerGold/CopperPerSilver, copper%CopperPerSilver
+
+	var parts []string
+	if gold > 0 {
+		parts = append(parts, strconv.Itoa(gold)+"g")
+	}
+	if silver > 0 {
+		parts = append(parts, strconv.Itoa(silver)+"s")
+	}
+	if rest > 0 || len(parts) == 0 {
+		parts = append(parts, strconv.Itoa(rest)+"c")
+	}
+	return sign + strings.Join(parts, " ")
+}
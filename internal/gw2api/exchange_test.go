@@ -0,0 +1,170 @@
+package gw2api
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseExchangeFrom(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ExchangeFrom
+		wantErr bool
+	}{
+		{input: "coins", want: ExchangeCoins},
+		{input: " Gems ", want: ExchangeGems},
+		{input: "gold", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseExchangeFrom(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClient_GetGemExchange(t *testing.T) {
+	// Rates worsen with the quantity, and 100 copper is too little to buy a gem
+	rates := map[string]string{
+		"/commerce/exchange/coins?quantity=100":      `{"text":"not enough coins"}`,
+		"/commerce/exchange/coins?quantity=100000":   `{"coins_per_gem":3400,"quantity":29}`,
+		"/commerce/exchange/coins?quantity=10000000": `{"coins_per_gem":3910,"quantity":2557}`,
+		"/commerce/exchange/gems?quantity=100":       `{"coins_per_gem":2600,"quantity":260000}`,
+		"/commerce/exchange/gems?quantity=2000":      `{"coins_per_gem":2405,"quantity":4810000}`,
+	}
+
+	tests := []struct {
+		name       string
+		from       ExchangeFrom
+		quantities []int
+		wantErr    string
+		want       []GemExchangeQuote
+	}{
+		{
+			name:       "buy gems",
+			from:       ExchangeCoins,
+			quantities: []int{10000000, 100000},
+			want: []GemExchangeQuote{
+				{Coins: 100000, CoinsFormatted: "10g", Gems: 29, CoinsPerGem: 3400},
+				{Coins: 10000000, CoinsFormatted: "1000g", Gems: 2557, CoinsPerGem: 3910, SlippagePercent: 15},
+			},
+		},
+		{
+			name:       "sell gems",
+			from:       ExchangeGems,
+			quantities: []int{100, 2000},
+			want: []GemExchangeQuote{
+				{Coins: 260000, CoinsFormatted: "26g", Gems: 100, CoinsPerGem: 2600},
+				{Coins: 4810000, CoinsFormatted: "481g", Gems: 2000, CoinsPerGem: 2405, SlippagePercent: 7.5},
+			},
+		},
+		{
+			name:       "refused quantity",
+			from:       ExchangeCoins,
+			quantities: []int{100, 100000},
+			want: []GemExchangeQuote{
+				{Coins: 100, CoinsFormatted: "1s", Error: "failed to fetch gem exchange rate for 100 coins: " +
+					"API request failed with status 400: not enough coins"},
+				{Coins: 100000, CoinsFormatted: "10g", Gems: 29, CoinsPerGem: 3400},
+			},
+		},
+		{
+			name:       "every quantity refused",
+			from:       ExchangeCoins,
+			quantities: []int{100},
+			wantErr:    "not enough coins",
+		},
+		{
+			name:    "no quantities",
+			from:    ExchangeGems,
+			wantErr: "no quantities",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newAPIStub(t, func(w http.ResponseWriter, r *http.Request) {
+				body, ok := rates[r.URL.RequestURI()]
+				switch {
+				case !ok:
+					writeJSON(w, http.StatusNotFound, `{"text":"not found"}`)
+				case strings.Contains(body, `"text"`):
+					writeJSON(w, http.StatusBadRequest, body)
+				default:
+					writeJSON(w, http.StatusOK, body)
+				}
+			})
+
+			exchange, err := stub.client().GetGemExchange(context.Background(), tt.from, tt.quantities)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetGemExchange failed: %v", err)
+			}
+
+			if exchange.From != tt.from {
+				t.Errorf("Expected from %q, got %q", tt.from, exchange.From)
+			}
+			if !slices.Equal(exchange.Quotes, tt.want) {
+				t.Errorf("Expected quotes %+v, got %+v", tt.want, exchange.Quotes)
+			}
+		})
+	}
+}
+
+func TestClient_GetExchangeRate_Cache(t *testing.T) {
+	stub := newAPIStub(t, func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, `{"coins_per_gem":2600,"quantity":260000}`)
+	})
+	client := stub.client()
+
+	for range 2 {
+		if _, err := client.GetExchangeRate(context.Background(), ExchangeGems, 100); err != nil {
+			t.Fatalf("GetExchangeRate failed: %v", err)
+		}
+	}
+	if _, err := client.GetExchangeRate(context.Background(), ExchangeGems, 400); err != nil {
+		t.Fatalf("GetExchangeRate failed: %v", err)
+	}
+
+	want := []string{"/commerce/exchange/gems?quantity=100", "/commerce/exchange/gems?quantity=400"}
+	if uris := stub.requestURIs(); !slices.Equal(uris, want) {
+		t.Errorf("Expected requests %v, got %v", want, uris)
+	}
+}
+
+func TestFormatCoins(t *testing.T) {
+	tests := []struct {
+		copper int
+		want   string
+	}{
+		{copper: 0, want: "0c"},
+		{copper: 5, want: "5c"},
+		{copper: 100, want: "1s"},
+		{copper: 123456, want: "12g 34s 56c"},
+		{copper: 10000005, want: "1000g 5c"},
+		{copper: -250, want: "-2s 50c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := FormatCoins(tt.copper); got != tt.want {
+				t.Errorf("FormatCoins(%d) = %q, want %q", tt.copper, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package gw2api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/AlyxPink/gw2-mcp/internal/progress"
+)
+
+// FlipSort orders trading post flips
+type FlipSort string
+
+const (
+	// FlipSortProfit ranks flips by profit after fees, highest first
+	FlipSortProfit FlipSort = "profit"
+	// FlipSortROI ranks flips by profit relative to the capital, highest first
+	FlipSortROI FlipSort = "roi"
+	// FlipSortVolume ranks flips by the lower of supply and demand, highest first
+	FlipSortVolume FlipSort = "volume"
+	// FlipSortCapital ranks flips by the capital required, lowest first
+	FlipSortCapital FlipSort = "capital"
+)
+
+// FlipSorts returns every supported flip order
+func FlipSorts() []FlipSort {
+	return []FlipSort{FlipSortProfit, FlipSortROI, FlipSortVolume, FlipSortCapital}
+}
+
+// ParseFlipSort validates a flip order, defaulting to profit
+func ParseFlipSort(s string) (FlipSort, error) {
+	sortBy := FlipSort(strings.ToLower(strings.TrimSpace(s)))
+	if sortBy == "" {
+		return FlipSortProfit, nil
+	}
+	for _, supported := range FlipSorts() {
+		if sortBy == supported {
+			return sortBy, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported sort %q, expected profit, roi, volume or capital", s)
+}
+
+// FlipQuery selects and ranks the flips of a scan
+type FlipQuery struct {
+	ItemIDs    []int // candidates, every traded item when empty
+	SortBy     FlipSort
+	Lang       Language
+	MinVolume  int     // minimum supply and demand
+	MinROI     float64 // minimum profit in percent of the capital
+	MaxCapital int     // maximum capital in copper, 0 for no limit
+	Quantity   int     // items flipped at once
+	Limit      int     // maximum number of flips returned
+}
+
+// Flip is a trading post flip: placing a buy order just above the highest one, then
+// listing the items just below the lowest sell listing
+type Flip struct {
+	ItemName         string  `json:"item_name"`
+	ProfitFormatted  string  `json:"profit_formatted"`
+	CapitalFormatted string  `json:"capital_formatted"`
+	ItemID           int     `json:"item_id"`
+	BuyPrice         int     `json:"buy_price"`  // 1 copper above the highest buy order
+	SellPrice        int     `json:"sell_price"` // 1 copper below the lowest sell listing
+	Fees             int     `json:"fees"`       // per item
+	ProfitPerItem    int     `json:"profit_per_item"`
+	ROIPercent       float64 `json:"roi_percent"`
+	Demand           int     `json:"demand"`     // items wanted by all buy orders
+	Supply           int     `json:"supply"`     // items offered by all sell listings
+	BuyDepth         int     `json:"buy_depth"`  // items wanted at the highest buy price, to outbid
+	SellDepth        int     `json:"sell_depth"` // items listed at the lowest sell price, to undercut
+	Quantity         int     `json:"quantity"`
+	Capital          int     `json:"capital"` // coins needed for the buy order and listing fee
+	Profit           int     `json:"profit"`  // for the whole quantity, after fees
+}
+
+// FlipReport ranks the flips found in a scan
+type FlipReport struct {
+	SortBy     FlipSort `json:"sort_by"`
+	Flips      []Flip   `json:"flips"`
+	Scanned    int      `json:"scanned"`    // candidates with trading post prices
+	Profitable int      `json:"profitable"` // flips matching the filters, before the limit
+	Quantity   int      `json:"quantity"`
+}
+
+// FindFlips scans the prices of candidate items for flips that are profitable after the
+// trading post fees and ranks them. Prices are fetched 200 items at a time and cached,
+// so repeated scans within the trading post TTL do not hit the API again. The order book
+// depth and names are only fetched for the returned flips.
+func (c *Client) FindFlips(ctx context.Context, query FlipQuery) (*FlipReport, error) {
+	const steps = 2
+
+	if query.Quantity <= 0 || query.Limit <= 0 {
+		return nil, fmt.Errorf("invalid quantity %d or limit %d", query.Quantity, query.Limit)
+	}
+
+	ids := query.ItemIDs
+	if len(ids) == 0 {
+		var err error
+		if ids, err = c.GetTradedItemIDs(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	prices, err := c.GetPrices(progress.Sub(ctx, 1, steps), ids)
+	if err != nil {
+		return nil, err
+	}
+	progress.Report(ctx, 1, steps, fmt.Sprintf("fetched prices of %d items", len(prices)))
+
+	report := FlipReport{SortBy: query.SortBy, Scanned: len(prices), Quantity: query.Quantity, Flips: []Flip{}}
+	for _, price := range prices {
+		flip, ok := newFlip(price, query.Quantity)
+		if !ok || min(flip.Supply, flip.Demand) < query.MinVolume || flip.ROIPercent < query.MinROI ||
+			(query.MaxCapital > 0 && flip.Capital > query.MaxCapital) {
+			continue
+		}
+		report.Flips = append(report.Flips, flip)
+	}
+	report.Profitable = len(report.Flips)
+
+	sortFlips(report.Flips, query.SortBy)
+	report.Flips = report.Flips[:min(query.Limit, len(report.Flips))]
+
+	c.addFlipDetails(progress.Sub(ctx, 2, steps), report.Flips, query.Lang)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	progress.Report(ctx, 2, steps, fmt.Sprintf("fetched order books of %d flips", len(report.Flips)))
+
+	return &report, nil
+}
+
+// newFlip computes the flip of an item for a quantity, reporting false when both sides of
+// the order book are not populated or the spread does not cover the fees
+func newFlip(price Price, quantity int) (Flip, bool) {
+	if price.Buys.UnitPrice <= 0 || price.Sells.UnitPrice <= 0 {
+		return Flip{}, false
+	}
+
+	buyPrice := price.Buys.UnitPrice + 1
+	sellPrice := price.Sells.UnitPrice - 1
+	fees := SellFees(sellPrice, 1)
+	profit := sellPrice - fees - buyPrice
+	if profit <= 0 {
+		return Flip{}, false
+	}
+	// The listing fee is paid upfront when relisting, so it is tied up with the buy order
+	capital := buyPrice + ListingFee(sellPrice, 1)
+
+	flip := Flip{
+		ItemID:        price.ID,
+		BuyPrice:      buyPrice,
+		SellPrice:     sellPrice,
+		Fees:          fees,
+		ProfitPerItem: profit,
+		ROIPercent:    math.Round(float64(profit)/float64(capital)*10000) / 100,
+		Demand:        price.Buys.Quantity,
+		Supply:        price.Sells.Quantity,
+		Quantity:      quantity,
+		Capital:       capital * quantity,
+		Profit:        profit * quantity,
+	}
+	flip.ProfitFormatted = FormatCoins(flip.Profit)
+	flip.CapitalFormatted = FormatCoins(flip.Capital)
+	return flip, true
+}
+
+// sortFlips orders flips by the given criterion, then by item ID
+func sortFlips(flips []Flip, sortBy FlipSort) {
+	key := func(flip Flip) float64 {
+		switch sortBy {
+		case FlipSortROI:
+			return flip.ROIPercent
+		case FlipSortVolume:
+			return float64(min(flip.Supply, flip.Demand))
+		case FlipSortCapital:
+			return -float64(flip.Capital)
+		default:
+			return float64(flip.Profit)
+		}
+	}
+
+	sort.Slice(flips, func(i, j int) bool {
+		if ki, kj := key(flips[i]), key(flips[j]); ki != kj {
+			return ki > kj
+		}
+		return flips[i].ItemID < flips[j].ItemID
+	})
+}
+
+// addFlipDetails sets the item names and order book depth of flips, leaving them empty
+// when they cannot be fetched
+func (c *Client) addFlipDetails(ctx context.Context, flips []Flip, lang Language) {
+	ids := make([]int, len(flips))
+	for i, flip := range flips {
+		ids[i] = flip.ItemID
+	}
+
+	names, err := c.GetItemNames(ctx, ids, lang)
+	if err != nil {
+		c.logger.Warn("Failed to get item names", "error", err)
+	}
+
+	listings, err := c.GetListings(ctx, ids)
+	if err != nil {
+		c.logger.Warn("Failed to get trading post listings", "error", err)
+	}
+
+	for i := range flips {
+		flips[i].ItemName = names[flips[i].ItemID]
+		listing := listings[flips[i].ItemID]
+		flips[i].BuyDepth = topDepth(listing.Buys, flips[i].BuyPrice-1)
+		flips[i].SellDepth = topDepth(listing.Sells, flips[i].SellPrice+1)
+	}
+}
+
+// topDepth returns the quantity ordered or listed at a price
+func topDepth(tiers []ListingTier, price int) int {
+	for _, tier := range tiers {
+		if tier.UnitPrice == price {
+			return tier.Quantity
+		}
+	}
+	return 0
+}
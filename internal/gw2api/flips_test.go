@@ -0,0 +1,156 @@
+package gw2api
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/AlyxPink/gw2-mcp/internal/cache"
+	"github.com/AlyxPink/gw2-mcp/internal/gw2mock"
+)
+
+func TestParseFlipSort(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    FlipSort
+		wantErr bool
+	}{
+		{input: "", want: FlipSortProfit},
+		{input: " ROI ", want: FlipSortROI},
+		{input: "capital", want: FlipSortCapital},
+		{input: "margin", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseFlipSort(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewFlip(t *testing.T) {
+	tests := []struct {
+		name   string
+		price  Price
+		want   Flip
+		wantOK bool
+	}{
+		{
+			name: "profitable",
+			price: Price{ID: 1, Buys: PriceSide{UnitPrice: 2000, Quantity: 150}, Sells: PriceSide{UnitPrice: 2700,
+				Quantity: 80}},
+			// Capital counts the 135 copper listing fee paid upfront on top of the buy order
+			want: Flip{ItemID: 1, BuyPrice: 2001, SellPrice: 2699, Fees: 405, ProfitPerItem: 293, ROIPercent: 13.72,
+				Demand: 150, Supply: 80, Quantity: 10, Capital: 21360, Profit: 2930, ProfitFormatted: "29s 30c",
+				CapitalFormatted: "2g 13s 60c"},
+			wantOK: true,
+		},
+		{
+			name:  "spread eaten by fees",
+			price: Price{ID: 2, Buys: PriceSide{UnitPrice: 2412}, Sells: PriceSide{UnitPrice: 2577}},
+		},
+		{
+			name:  "no buy orders",
+			price: Price{ID: 3, Sells: PriceSide{UnitPrice: 2577}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := newFlip(tt.price, 10)
+			if ok != tt.wantOK {
+				t.Fatalf("Expected ok %v, got %v", tt.wantOK, ok)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClient_FindFlips(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   FlipQuery
+		wantIDs []int
+	}{
+		{name: "by profit", query: FlipQuery{SortBy: FlipSortProfit}, wantIDs: []int{24295, 24351, 19700}},
+		{name: "by roi", query: FlipQuery{SortBy: FlipSortROI}, wantIDs: []int{24351, 24295, 19700}},
+		{name: "by volume", query: FlipQuery{SortBy: FlipSortVolume}, wantIDs: []int{19700, 24295, 24351}},
+		{name: "by capital", query: FlipQuery{SortBy: FlipSortCapital}, wantIDs: []int{19700, 24351, 24295}},
+		{name: "capital limit", query: FlipQuery{SortBy: FlipSortProfit, MaxCapital: 16060}, wantIDs: []int{24351, 19700}},
+		{name: "listing fee counts as capital", query: FlipQuery{SortBy: FlipSortProfit, MaxCapital: 16059},
+			wantIDs: []int{19700}},
+		{name: "minimum roi", query: FlipQuery{SortBy: FlipSortProfit, MinROI: 10}, wantIDs: []int{24295, 24351}},
+		{name: "thinly traded", query: FlipQuery{SortBy: FlipSortROI, MinVolume: -1}, wantIDs: []int{24358, 24351, 24295, 19700}},
+		{name: "limit", query: FlipQuery{SortBy: FlipSortProfit, Limit: 1}, wantIDs: []int{24295}},
+		{name: "candidates", query: FlipQuery{SortBy: FlipSortProfit, ItemIDs: []int{19721, 24351}}, wantIDs: []int{24351}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := httptest.NewServer(gw2mock.New())
+			defer mock.Close()
+
+			query := tt.query
+			query.Lang = LanguageEnglish
+			query.Quantity = 10
+			if query.MinVolume == 0 {
+				query.MinVolume = 1000
+			}
+			if query.Limit == 0 {
+				query.Limit = 20
+			}
+
+			client := NewClient(cache.NewManager(), nil, WithBaseURL(mock.URL+"/v2"))
+			report, err := client.FindFlips(context.Background(), query)
+			if err != nil {
+				t.Fatalf("FindFlips failed: %v", err)
+			}
+
+			ids := make([]int, len(report.Flips))
+			for i, flip := range report.Flips {
+				ids[i] = flip.ItemID
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("Expected flips %v, got %v", tt.wantIDs, ids)
+			}
+		})
+	}
+}
+
+func TestClient_FindFlips_Details(t *testing.T) {
+	mock := gw2mock.New()
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	client := NewClient(cache.NewManager(), nil, WithBaseURL(server.URL+"/v2"))
+	query := FlipQuery{SortBy: FlipSortProfit, Lang: LanguageEnglish, MinVolume: 1000, Quantity: 10, Limit: 2}
+
+	for range 2 {
+		report, err := client.FindFlips(context.Background(), query)
+		if err != nil {
+			t.Fatalf("FindFlips failed: %v", err)
+		}
+		if report.Scanned != 18 || report.Profitable != 3 {
+			t.Errorf("Expected 3 of 18 items profitable, got %d of %d", report.Profitable, report.Scanned)
+		}
+
+		flip := report.Flips[0]
+		if flip.ItemName != "Vial of Powerful Blood" || flip.BuyDepth != 1500 || flip.SellDepth != 40 {
+			t.Errorf("Expected the name and depth of Vial of Powerful Blood, got %+v", flip)
+		}
+	}
+
+	// The item IDs, prices, names and listings are fetched once, then served from the cache
+	if requests := mock.Requests(); requests != 4 {
+		t.Errorf("Expected 4 requests, got %d", requests)
+	}
+}
@@ -0,0 +1,141 @@
+package gw2api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/AlyxPink/gw2-mcp/internal/progress"
+)
+
+// itemBatchSize is the maximum number of IDs the GW2 API accepts per request
+const itemBatchSize = 200
+
+// ItemName pairs an item ID with its name
+type ItemName struct {
+	Name string `json:"name"`
+	ID   int    `json:"id"`
+}
+
+// itemIndex maps item IDs to names per language, so names can be completed without API requests
+type itemIndex struct {
+	names map[Language]map[int]string
+	mu    sync.RWMutex
+}
+
+// newItemIndex creates an empty item name index
+func newItemIndex() *itemIndex {
+	return &itemIndex{names: make(map[Language]map[int]string)}
+}
+
+// add records item names for a language
+func (idx *itemIndex) add(lang Language, items ...ItemName) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	names, ok := idx.names[lang]
+	if !ok {
+		names = make(map[int]string)
+		idx.names[lang] = names
+	}
+	for _, item := range items {
+		if item.Name != "" {
+			names[item.ID] = item.Name
+		}
+	}
+}
+
+// search returns items whose name contains query, ignoring case. Names starting with
+// the query come first, then shorter names, then alphabetical order.
+func (idx *itemIndex) search(lang Language, query string) []ItemName {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	idx.mu.RLock()
+	var matches []ItemName
+	for id, name := range idx.names[lang] {
+		if strings.Contains(strings.ToLower(name), query) {
+			matches = append(matches, ItemName{ID: id, Name: name})
+		}
+	}
+	idx.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		iPrefix := strings.HasPrefix(strings.ToLower(matches[i].Name), query)
+		jPrefix := strings.HasPrefix(strings.ToLower(matches[j].Name), query)
+		if iPrefix != jPrefix {
+			return iPrefix
+		}
+		if len(matches[i].Name) != len(matches[j].Name) {
+			return len(matches[i].Name) < len(matches[j].Name)
+		}
+		if matches[i].Name != matches[j].Name {
+			return matches[i].Name < matches[j].Name
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	return matches
+}
+
+// len returns the number of indexed items for a language
+func (idx *itemIndex) len(lang Language) int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.names[lang])
+}
+
+// SearchItemNames searches the local item name index. It never calls the API: the index
+// holds items fetched so far, and every item once BuildItemIndex has run.
+func (c *Client) SearchItemNames(query string, lang Language) []ItemName {
+	return c.items.search(lang, query)
+}
+
+// BuildItemIndex loads the names of all items in a language into the local index.
+// The index is cached, so the API is only paged through when the cache is cold.
+func (c *Client) BuildItemIndex(ctx context.Context, lang Language) error {
+	cacheKey := c.cache.GetItemIndexKey(string(lang))
+
+	// Try cache first
+	var names []ItemName
+	if c.cache.GetJSON(cacheKey, &names) {
+		c.items.add(lang, names...)
+		c.logger.Debug("Item index cache hit", "lang", lang, "items", len(names))
+		return nil
+	}
+
+	c.logger.Info("Building item name index", "lang", lang)
+
+	var ids []int
+	if err := c.getJSON(ctx, "/items", "", &ids); err != nil {
+		return fmt.Errorf("failed to fetch item IDs: %w", err)
+	}
+
+	names = make([]ItemName, 0, len(ids))
+	for start := 0; start < len(ids); start += itemBatchSize {
+		batch := ids[start:min(start+itemBatchSize, len(ids))]
+
+		var items []ItemName
+		path := "/items?ids=" + joinIDs(batch) + "&lang=" + string(lang)
+		if err := c.getJSON(ctx, path, "", &items); err != nil {
+			return fmt.Errorf("failed to fetch item names: %w", err)
+		}
+
+		c.items.add(lang, items...)
+		names = append(names, items...)
+		progress.Report(ctx, len(names), len(ids), fmt.Sprintf("indexed %d/%d items", len(names), len(ids)))
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	// Cache the result
+	if err := c.cache.SetJSON(cacheKey, names, c.cache.TTLs().StaticData); err != nil {
+		c.logger.Warn("Failed to cache item index", "error", err)
+	}
+
+	c.logger.Info("Item name index built", "lang", lang, "items", c.items.len(lang))
+	return nil
+}
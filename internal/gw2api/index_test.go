@@ -0,0 +1,99 @@
+package gw2api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/AlyxPink/gw2-mcp/internal/cache"
+)
+
+func TestItemIndex_Search(t *testing.T) {
+	idx := newItemIndex()
+	idx.add(LanguageEnglish,
+		ItemName{ID: 19721, Name: "Glob of Ectoplasm"},
+		ItemName{ID: 19976, Name: "Mystic Coin"},
+		ItemName{ID: 19675, Name: "Mystic Clover"},
+		ItemName{ID: 68063, Name: "Amalgamated Gemstone"},
+		ItemName{ID: 1, Name: ""},
+	)
+	idx.add(LanguageFrench, ItemName{ID: 19976, Name: "Pièce mystique"})
+
+	tests := []struct {
+		query    string
+		lang     Language
+		expected []int
+	}{
+		{query: "mystic", lang: LanguageEnglish, expected: []int{19976, 19675}},
+		{query: "GEM", lang: LanguageEnglish, expected: []int{68063}},
+		{query: "o", lang: LanguageEnglish, expected: []int{19976, 19675, 19721, 68063}},
+		{query: "g", lang: LanguageEnglish, expected: []int{19721, 68063}},
+		{query: "mystique", lang: LanguageFrench, expected: []int{19976}},
+		{query: "mystic", lang: LanguageGerman, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var ids []int
+			for _, item := range idx.search(tt.lang, tt.query) {
+				ids = append(ids, item.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.expected) {
+				t.Errorf("search(%q) = %v, want %v", tt.query, ids, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClient_BuildItemIndex(t *testing.T) {
+	var requests atomic.Int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("ids") == "" {
+			_, _ = w.Write([]byte(`[19721,19976]`))
+			return
+		}
+		if r.URL.Query().Get("lang") != "de" || !strings.Contains(r.URL.Query().Get("ids"), "19976") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id":19721,"name":"Klumpen Ektoplasma"},{"id":19976,"name":"Mystische Münze"}]`))
+	}))
+	defer mockServer.Close()
+
+	cacheManager := cache.NewManager()
+	logger := log.New(io.Discard)
+	client := NewClient(cacheManager, logger, WithBaseURL(mockServer.URL))
+
+	if err := client.BuildItemIndex(context.Background(), LanguageGerman); err != nil {
+		t.Fatalf("BuildItemIndex failed: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected 2 requests, got %d", got)
+	}
+
+	names := client.SearchItemNames("münze", LanguageGerman)
+	if len(names) != 1 || names[0].ID != 19976 {
+		t.Errorf("Expected Mystische Münze, got %v", names)
+	}
+
+	// A second client sharing the cache restores the index without requests
+	restored := NewClient(cacheManager, logger, WithBaseURL(mockServer.URL))
+	if err := restored.BuildItemIndex(context.Background(), LanguageGerman); err != nil {
+		t.Fatalf("BuildItemIndex from cache failed: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected the cached index to be reused, got %d requests", got)
+	}
+	if len(restored.SearchItemNames("ektoplasma", LanguageGerman)) != 1 {
+		t.Error("Expected restored index to contain Klumpen Ektoplasma")
+	}
+}
@@ -0,0 +1,177 @@
+package gw2api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Item represents item metadata from /v2/items
+type Item struct {
+	Details      json.RawMessage `json:"details,omitempty"` // type specific, see the official API documentation
+	Name         string          `json:"name"`
+	Description  string          `json:"description,omitempty"`
+	Type         string          `json:"type"`
+	Rarity       string          `json:"rarity"`
+	Icon         string          `json:"icon,omitempty"`
+	ChatLink     string          `json:"chat_link"`
+	Flags        []string        `json:"flags"`
+	GameTypes    []string        `json:"game_types"`
+	Restrictions []string        `json:"restrictions"`
+	ID           int             `json:"id"`
+	Level        int             `json:"level"`
+	VendorValue  int             `json:"vendor_value"`
+}
+
+// RecipeIngredient represents one ingredient of a recipe
+type RecipeIngredient struct {
+	Type  string `json:"type,omitempty"` // Item, Currency or GuildUpgrade
+	ID    int    `json:"id,omitempty"`
+	Count int    `json:"count"`
+	// ItemID is only set by older recipes that predate typed ingredients
+	ItemID int `json:"item_id,omitempty"`
+}
+
+// Recipe represents a crafting recipe from /v2/recipes
+type Recipe struct {
+	Type            string             `json:"type"`
+	ChatLink        string             `json:"chat_link"`
+	Disciplines     []string           `json:"disciplines"`
+	Flags           []string           `json:"flags"`
+	Ingredients     []RecipeIngredient `json:"ingredients"`
+	ID              int                `json:"id"`
+	OutputItemID    int                `json:"output_item_id"`
+	OutputItemCount int                `json:"output_item_count"`
+	MinRating       int                `json:"min_rating"`
+	TimeToCraftMS   int                `json:"time_to_craft_ms"`
+}
+
+// GetItem retrieves item metadata in the given language
+func (c *Client) GetItem(ctx context.Context, id int, lang Language) (*Item, error) {
+	cacheKey := c.cache.GetItemDetailKey(string(lang), id)
+
+	// Try cache first
+	var item Item
+	if c.cache.GetJSON(cacheKey, &item) {
+		c.logger.Debug("Item cache hit", "id", id, "lang", lang)
+		c.items.add(lang, ItemName{ID: item.ID, Name: item.Name})
+		return &item, nil
+	}
+
+	c.logger.Debug("Item cache miss, fetching from API", "id", id, "lang", lang)
+
+	path := "/items/" + strconv.Itoa(id) + "?lang=" + string(lang)
+	if err := c.getJSON(ctx, path, "", &item); err != nil {
+		return nil, fmt.Errorf("failed to fetch item %d: %w", id, err)
+	}
+
+	c.items.add(lang, ItemName{ID: item.ID, Name: item.Name})
+
+	// Cache the result
+	if err := c.cache.SetJSON(cacheKey, item, c.cache.TTLs().StaticData); err != nil {
+		c.logger.Warn("Failed to cache item", "id", id, "error", err)
+	}
+
+	return &item, nil
+}
+
+// GetItems retrieves the metadata of several items in the given language, keyed by ID.
+// Items missing from the cache are fetched in batches of 200; unknown IDs are left out.
+func (c *Client) GetItems(ctx context.Context, ids []int, lang Language) (map[int]Item, error) {
+	items := make(map[int]Item, len(ids))
+	seen := make(map[int]bool, len(ids))
+	var missingIDs []int
+
+	// Check cache for each item
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		var item Item
+		if c.cache.GetJSON(c.cache.GetItemDetailKey(string(lang), id), &item) {
+			items[id] = item
+		} else {
+			missingIDs = append(missingIDs, id)
+		}
+	}
+
+	c.logger.Debug("Fetching items", "cached", len(items), "missing", len(missingIDs), "lang", lang)
+
+	for start := 0; start < len(missingIDs); start += itemBatchSize {
+		batch := missingIDs[start:min(start+itemBatchSize, len(missingIDs))]
+
+		var fetched []Item
+		path := "/items?ids=" + joinIDs(batch) + "&lang=" + string(lang)
+		if err := c.getJSON(ctx, path, "", &fetched); err != nil {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+				continue // none of the batch exists
+			}
+			return nil, fmt.Errorf("failed to fetch items: %w", err)
+		}
+
+		// Add fetched items to result and cache
+		for _, item := range fetched {
+			items[item.ID] = item
+			c.items.add(lang, ItemName{ID: item.ID, Name: item.Name})
+			if err := c.cache.SetJSON(c.cache.GetItemDetailKey(string(lang), item.ID), item, c.cache.TTLs().StaticData); err != nil {
+				c.logger.Warn("Failed to cache item", "id", item.ID, "error", err)
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// GetItemNames returns the names of several items in the given language, keyed by ID
+func (c *Client) GetItemNames(ctx context.Context, ids []int, lang Language) (map[int]string, error) {
+	items, err := c.GetItems(ctx, ids, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[int]string, len(items))
+	for id, item := range items {
+		names[id] = item.Name
+	}
+	return names, nil
+}
+
+// joinIDs formats IDs as the comma-separated ids parameter of bulk endpoints
+func joinIDs(ids []int) string {
+	idStrs := make([]string, len(ids))
+	for i, id := range ids {
+		idStrs[i] = strconv.Itoa(id)
+	}
+	return strings.Join(idStrs, ",")
+}
+
+// GetRecipe retrieves a crafting recipe. Recipes hold no localized text.
+func (c *Client) GetRecipe(ctx context.Context, id int) (*Recipe, error) {
+	cacheKey := c.cache.GetRecipeDetailKey(id)
+
+	// Try cache first
+	var recipe Recipe
+	if c.cache.GetJSON(cacheKey, &recipe) {
+		c.logger.Debug("Recipe cache hit", "id", id)
+		return &recipe, nil
+	}
+
+	c.logger.Debug("Recipe cache miss, fetching from API", "id", id)
+
+	if err := c.getJSON(ctx, "/recipes/"+strconv.Itoa(id), "", &recipe); err != nil {
+		return nil, fmt.Errorf("failed to fetch recipe %d: %w", id, err)
+	}
+
+	// Cache the result
+	if err := c.cache.SetJSON(cacheKey, recipe, c.cache.TTLs().StaticData); err != nil {
+		c.logger.Warn("Failed to cache recipe", "id", id, "error", err)
+	}
+
+	return &recipe, nil
+}
@@ -0,0 +1,42 @@
+package gw2api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Language identifies a localization supported by the GW2 API
+type Language string
+
+// Supported API languages
+const (
+	LanguageEnglish Language = "en"
+	LanguageGerman  Language = "de"
+	LanguageFrench  Language = "fr"
+	LanguageSpanish Language = "es"
+	LanguageChinese Language = "zh"
+
+	// DefaultLanguage is used when no language is specified
+	DefaultLanguage = LanguageEnglish
+)
+
+// Languages returns all supported API languages
+func Languages() []Language {
+	return []Language{LanguageEnglish, LanguageGerman, LanguageFrench, LanguageSpanish, LanguageChinese}
+}
+
+// ParseLanguage converts a language code into a Language, returning fallback when empty
+func ParseLanguage(code string, fallback Language) (Language, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return fallback, nil
+	}
+
+	for _, lang := range Languages() {
+		if Language(code) == lang {
+			return lang, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported API language %q (supported: en, de, fr, es, zh)", code)
+}
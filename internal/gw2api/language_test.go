@@ -0,0 +1,30 @@
+package gw2api
+
+import "testing"
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		fallback Language
+		expected Language
+		wantErr  bool
+	}{
+		{name: "Empty uses fallback", input: "", fallback: LanguageFrench, expected: LanguageFrench},
+		{name: "Chinese", input: "zh", fallback: LanguageEnglish, expected: LanguageChinese},
+		{name: "Case and whitespace insensitive", input: " DE ", fallback: LanguageEnglish, expected: LanguageGerman},
+		{name: "Unsupported language", input: "ko", fallback: LanguageEnglish, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lang, err := ParseLanguage(tt.input, tt.fallback)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLanguage(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if lang != tt.expected {
+				t.Errorf("ParseLanguage(%q) = %q, want %q", tt.input, lang, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,586 @@
+package gw2api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AlyxPink/gw2-mcp/internal/progress"
+)
+
+// ArmoryItem is an item of the legendary armory
+type ArmoryItem struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`              // e.g. Weapon, Armor, Back, Trinket or UpgradeComponent
+	Subtype  string `json:"subtype,omitempty"` // e.g. Greatsword, Coat, Ring or Sigil
+	ID       int    `json:"id"`
+	Count    int    `json:"count"`     // copies unlocked
+	MaxCount int    `json:"max_count"` // copies that can be unlocked, e.g. 2 for rings
+}
+
+// ArmoryQuery selects the items of a legendary armory report
+type ArmoryQuery struct {
+	Type           string // item type or subtype, every item when empty
+	Lang           Language
+	IncludeMissing bool // list the items not unlocked yet, not only count them
+}
+
+// LegendaryArmory is the legendary armory of an account
+type LegendaryArmory struct {
+	Type          string       `json:"type,omitempty"`
+	Unlocked      []ArmoryItem `json:"unlocked"`
+	Missing       []ArmoryItem `json:"missing,omitempty"`
+	UnlockedCount int          `json:"unlocked_count"`
+	MissingCount  int          `json:"missing_count"`
+	Total         int          `json:"total"` // items of the armory matching the type
+}
+
+// GetArmoryMaxCounts retrieves the items of the legendary armory with the number of
+// copies of each that can be unlocked, keyed by item ID
+func (c *Client) GetArmoryMaxCounts(ctx context.Context) (map[int]int, error) {
+	cacheKey := c.cache.GetLegendaryArmoryKey()
+
+	// Try cache first
+	var maxCounts map[int]int
+	if c.cache.GetJSON(cacheKey, &maxCounts) {
+		c.logger.Debug("Legendary armory cache hit")
+		return maxCounts, nil
+	}
+
+	c.logger.Debug("Legendary armory cache miss, fetching from API")
+
+	var entries []struct {
+		ID       int `json:"id"`
+		MaxCount int `json:"max_count"`
+	}
+	if err := c.getJSON(ctx, "/legendaryarmory?ids=all", "", &entries); err != nil {
+		return nil, fmt.Errorf("failed to fetch legendary armory: %w", err)
+	}
+
+	maxCounts = make(map[int]int, len(entries))
+	for _, entry := range entries {
+		maxCounts[entry.ID] = entry.MaxCount
+	}
+
+	// Cache the result
+	if err := c.cache.SetJSON(cacheKey, maxCounts, c.cache.TTLs().StaticData); err != nil {
+		c.logger.Warn("Failed to cache legendary armory", "error", err)
+	}
+
+	return maxCounts, nil
+}
+
+// GetAccountArmory retrieves the items unlocked in the legendary armory of the account,
+// which needs the inventories and unlocks scopes
+func (c *Client) GetAccountArmory(ctx context.Context, apiKey string) ([]ItemStack, error) {
+	return c.getStorage(ctx, apiKey, "legendaryarmory", "/account/legendaryarmory")
+}
+
+// GetLegendaryArmory reports the items the account unlocked in its legendary armory and
+// counts the ones it did not, sorted by type, subtype and name
+func (c *Client) GetLegendaryArmory(ctx context.Context, apiKey string, query ArmoryQuery) (*LegendaryArmory, error) {
+	maxCounts, err := c.GetArmoryMaxCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	unlocked, err := c.GetAccountArmory(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int]int, len(unlocked))
+	for _, stack := range unlocked {
+		counts[stack.ID] = stack.Count
+	}
+	ids := make([]int, 0, len(maxCounts))
+	for id := range maxCounts {
+		ids = append(ids, id)
+	}
+	items, err := c.GetItems(ctx, ids, query.Lang)
+	if err != nil {
+		return nil, err
+	}
+
+	armory := LegendaryArmory{Type: query.Type, Unlocked: []ArmoryItem{}}
+	if query.IncludeMissing {
+		armory.Missing = []ArmoryItem{}
+	}
+	for _, id := range ids {
+		item := items[id]
+		entry := ArmoryItem{
+			Name:     item.Name,
+			Type:     item.Type,
+			Subtype:  itemSubtype(item),
+			ID:       id,
+			Count:    counts[id],
+			MaxCount: maxCounts[id],
+		}
+		matches := strings.EqualFold(entry.Type, query.Type) || strings.EqualFold(entry.Subtype, query.Type)
+		if query.Type != "" && !matches {
+			continue
+		}
+
+		armory.Total++
+		switch {
+		case entry.Count > 0:
+			armory.Unlocked = append(armory.Unlocked, entry)
+		case query.IncludeMissing:
+			armory.Missing = append(armory.Missing, entry)
+		}
+	}
+	armory.UnlockedCount = len(armory.Unlocked)
+	armory.MissingCount = armory.Total - armory.UnlockedCount
+	sortArmoryItems(armory.Unlocked)
+	sortArmoryItems(armory.Missing)
+
+	return &armory, nil
+}
+
+// itemSubtype returns the type of the type specific details of an item, if any
+func itemSubtype(item Item) string {
+	var details struct {
+		Type string `json:"type"`
+	}
+	if len(item.Details) == 0 || json.Unmarshal(item.Details, &details) != nil {
+		return ""
+	}
+	return details.Type
+}
+
+// sortArmoryItems sorts armory items by type, subtype, name and ID
+func sortArmoryItems(items []ArmoryItem) {
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Subtype != b.Subtype {
+			return a.Subtype < b.Subtype
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+}
+
+// RequirementKind is what a legendary crafting requirement is fulfilled with
+type RequirementKind string
+
+const (
+	// RequirementItem is fulfilled by holding items
+	RequirementItem RequirementKind = "item"
+	// RequirementCurrency is fulfilled by a wallet balance
+	RequirementCurrency RequirementKind = "currency"
+	// RequirementAchievement is fulfilled by completing an achievement, such as a collection
+	RequirementAchievement RequirementKind = "achievement"
+)
+
+// Requirement is a component of a legendary, which is made from its own components when
+// the account does not hold enough of it
+type Requirement struct {
+	Kind       RequirementKind
+	Components []Requirement // needed to make one
+	ID         int
+	Count      int // items or currency needed; achievements only need to be done
+}
+
+// itemReq returns a requirement of count items made from components
+func itemReq(id, count int, components ...Requirement) Requirement {
+	return Requirement{Kind: RequirementItem, ID: id, Count: count, Components: components}
+}
+
+// currencyReq returns a requirement of a wallet balance
+func currencyReq(id, count int) Requirement {
+	return Requirement{Kind: RequirementCurrency, ID: id, Count: count}
+}
+
+// achievementReq returns a requirement of a completed achievement
+func achievementReq(id int) Requirement {
+	return Requirement{Kind: RequirementAchievement, ID: id}
+}
+
+// Gifts shared by the first generation of legendary weapons
+var (
+	giftOfMastery = itemReq(19674, 1,
+		itemReq(19677, 1),                       // Gift of Exploration
+		itemReq(19678, 1),                       // Gift of Battle
+		itemReq(20797, 1, currencyReq(23, 200)), // Bloodstone Shard from Spirit Shards
+		itemReq(19925, 250),                     // Obsidian Shard
+	)
+	giftOfFortune = itemReq(19626, 1,
+		itemReq(19675, 77),  // Mystic Clover
+		itemReq(19721, 250), // Glob of Ectoplasm
+		// Gift of Magic from blood, venom, totems and dust
+		itemReq(19673, 1, itemReq(24295, 250), itemReq(24283, 250), itemReq(24300, 250), itemReq(24277, 250)),
+		// Gift of Might from fangs, scales, claws and bones
+		itemReq(19672, 1, itemReq(24357, 250), itemReq(24289, 250), itemReq(24351, 250), itemReq(24358, 250)),
+	)
+)
+
+// legendaryRecipes holds the components of the legendaries whose progress can be tracked,
+// by item ID
+var legendaryRecipes = map[int][]Requirement{
+	// Twilight
+	30704: {
+		giftOfFortune,
+		giftOfMastery,
+		itemReq(19648, 1, // Gift of Twilight
+			// Gift of Metal from four kinds of ingots
+			itemReq(19621, 1, itemReq(19684, 250), itemReq(19685, 250), itemReq(19681, 250), itemReq(19686, 250)),
+			itemReq(24295, 100), // Vial of Powerful Blood
+			itemReq(24570, 1),   // Superior Sigil of Blood
+			itemReq(19676, 100), // Icy Runestone
+		),
+		itemReq(29185, 1), // Dusk
+	},
+	// Aurora, forged once its collections are done
+	81908: {
+		achievementReq(3213), // Aurora: Awakening
+		achievementReq(3292), // Aurora: Empowering
+		itemReq(81957, 1, // Gift of Aurora from the Living World Season 3 maps
+			itemReq(79280, 100),   // Blood Ruby
+			itemReq(79469, 100),   // Petrified Wood
+			itemReq(79899, 100),   // Fresh Winterberry
+			itemReq(80332, 100),   // Jade Shard
+			itemReq(81127, 100),   // Fire Orchid Blossom
+			itemReq(81706, 100),   // Orrian Pearl
+			currencyReq(32, 1000), // Unbound Magic
+		),
+		itemReq(19976, 100), // Mystic Coin
+	},
+}
+
+// LegendaryIDs returns the item IDs of the legendaries whose progress can be tracked
+func LegendaryIDs() []int {
+	ids := make([]int, 0, len(legendaryRecipes))
+	for id := range legendaryRecipes {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// FindLegendary resolves an item ID, or a name in the given language matched exactly or
+// else as a substring, to a legendary whose progress can be tracked
+func (c *Client) FindLegendary(ctx context.Context, query string, lang Language) (int, error) {
+	query = strings.TrimSpace(query)
+	if id, err := strconv.Atoi(query); err == nil {
+		if _, ok := legendaryRecipes[id]; !ok {
+			return 0, fmt.Errorf("no recipe known for item %d", id)
+		}
+		return id, nil
+	}
+
+	ids := LegendaryIDs()
+	names, err := c.GetItemNames(ctx, ids, lang)
+	if err != nil {
+		return 0, err
+	}
+
+	var matches []int
+	known := make([]string, len(ids))
+	for i, id := range ids {
+		known[i] = names[id]
+		if strings.EqualFold(names[id], query) {
+			return id, nil
+		}
+		if query != "" && strings.Contains(strings.ToLower(names[id]), strings.ToLower(query)) {
+			matches = append(matches, id)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return 0, fmt.Errorf("no recipe known for %q, known legendaries: %s", query, strings.Join(known, ", "))
+	case 1:
+		return matches[0], nil
+	default:
+		matched := make([]string, len(matches))
+		for i, id := range matches {
+			matched[i] = names[id]
+		}
+		return 0, fmt.Errorf("%q matches several legendaries: %s", query, strings.Join(matched, ", "))
+	}
+}
+
+// LegendaryComponent is the progress of the account on one component of a legendary
+type LegendaryComponent struct {
+	Name          string          `json:"name"`
+	Kind          RequirementKind `json:"kind"`
+	Parent        string          `json:"parent,omitempty"` // name of the component it is made into
+	CostFormatted string          `json:"cost_formatted,omitempty"`
+	ID            int             `json:"id"`
+	Depth         int             `json:"depth"` // 0 for direct components of the legendary
+	Required      int             `json:"required"`
+	Owned         int             `json:"owned"` // held and set aside for it, or achievement progress
+	Missing       int             `json:"missing"`
+	Cost          int             `json:"cost"` // in copper, to buy what is missing on the trading post
+	Done          bool            `json:"done"` // held, or made from components that are all done
+}
+
+// LegendaryProgress is the progress of an account toward crafting a legendary
+type LegendaryProgress struct {
+	Name                   string `json:"name"`
+	EstimatedCostFormatted string `json:"estimated_cost_formatted"`
+	// Components are listed depth first, each followed by the components it is made from
+	Components []LegendaryComponent `json:"components"`
+	// Unpriced lists missing components that cannot be bought on the trading post, such as
+	// account bound items, currencies and achievements, which the estimated cost leaves out
+	Unpriced        []string `json:"unpriced"`
+	ItemID          int      `json:"item_id"`
+	Unlocked        int      `json:"unlocked"`         // copies in the legendary armory
+	ComponentsDone  int      `json:"components_done"`  // direct components done
+	ComponentsTotal int      `json:"components_total"` // direct components
+	EstimatedCost   int      `json:"estimated_cost"`   // in copper, at the lowest sell listings
+}
+
+// legendaryWalk allocates the holdings of an account to the components of a legendary
+type legendaryWalk struct {
+	items        map[int]int // held items not set aside yet
+	currencies   map[int]int // wallet balances not set aside yet
+	achievements map[int]AchievementProgress
+	components   []LegendaryComponent
+	parents      []int // index of the parent of each component, -1 for direct ones
+}
+
+// add allocates holdings to required units of a requirement, then to the components of
+// what is still missing, and returns the index of the requirement in the components
+func (w *legendaryWalk) add(req Requirement, required, parent, depth int) int {
+	component := LegendaryComponent{Kind: req.Kind, ID: req.ID, Depth: depth, Required: required}
+	switch req.Kind {
+	case RequirementItem:
+		component.Owned = min(w.items[req.ID], required)
+		w.items[req.ID] -= component.Owned
+	case RequirementCurrency:
+		component.Owned = min(w.currencies[req.ID], required)
+		w.currencies[req.ID] -= component.Owned
+	case RequirementAchievement:
+		achievement := w.achievements[req.ID]
+		component.Required = max(achievement.Max, 1)
+		component.Owned = min(achievement.Current, component.Required)
+		if achievement.Done {
+			component.Owned = component.Required
+		}
+	}
+	component.Missing = component.Required - component.Owned
+
+	index := len(w.components)
+	w.components = append(w.components, component)
+	w.parents = append(w.parents, parent)
+
+	done := component.Missing == 0
+	if !done && len(req.Components) > 0 {
+		done = true
+		for _, sub := range req.Components {
+			child := w.add(sub, sub.Count*component.Missing, index, depth+1)
+			done = done && w.components[child].Done
+		}
+	}
+	w.components[index].Done = done
+	return index
+}
+
+// GetLegendaryProgress reports what the account owning the API key already holds toward a
+// legendary from its bank, material storage, inventories, wallet and achievements, which
+// needs the inventories, characters and unlocks scopes, plus wallet and progression when
+// the recipe uses currencies or achievements. Held items are set aside once, so a
+// material needed by two components only counts once. What is missing is priced at the
+// lowest sell listings.
+func (c *Client) GetLegendaryProgress(ctx context.Context, apiKey string, id int, lang Language,
+) (*LegendaryProgress, error) {
+	const steps = 5
+
+	recipe, ok := legendaryRecipes[id]
+	if !ok {
+		return nil, fmt.Errorf("no recipe known for item %d", id)
+	}
+
+	result := LegendaryProgress{ItemID: id, Components: []LegendaryComponent{}, Unpriced: []string{}}
+	unlocked, err := c.GetAccountArmory(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, stack := range unlocked {
+		if stack.ID == id {
+			result.Unlocked = stack.Count
+		}
+	}
+	progress.Report(ctx, 1, steps, "fetched legendary armory")
+
+	walk := legendaryWalk{items: make(map[int]int), currencies: make(map[int]int)}
+	stacks, err := c.getHeldItems(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, stack := range stacks {
+		walk.items[stack.ID] += stack.Count
+	}
+	progress.Report(ctx, 2, steps, "fetched bank, materials and inventories")
+
+	kinds := requirementKinds(recipe)
+	if kinds[RequirementCurrency] {
+		wallet, err := c.fetchWallet(ctx, apiKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+		}
+		for _, entry := range wallet {
+			walk.currencies[entry.ID] = entry.Value
+		}
+	}
+	if kinds[RequirementAchievement] {
+		if walk.achievements, err = c.GetAccountAchievements(ctx, apiKey); err != nil {
+			return nil, err
+		}
+	}
+	progress.Report(ctx, 3, steps, "fetched wallet and achievements")
+
+	for _, req := range recipe {
+		index := walk.add(req, req.Count, -1, 0)
+		result.ComponentsTotal++
+		if walk.components[index].Done {
+			result.ComponentsDone++
+		}
+	}
+	components := walk.components
+
+	var itemIDs, currencyIDs, achievementIDs, missingIDs []int
+	for i, component := range components {
+		switch component.Kind {
+		case RequirementItem:
+			itemIDs = append(itemIDs, component.ID)
+			if isMissingLeaf(components, i) {
+				missingIDs = append(missingIDs, component.ID)
+			}
+		case RequirementCurrency:
+			currencyIDs = append(currencyIDs, component.ID)
+		case RequirementAchievement:
+			achievementIDs = append(achievementIDs, component.ID)
+		}
+	}
+	prices, err := c.GetPrices(progress.Sub(ctx, 4, steps), missingIDs)
+	if err != nil {
+		return nil, err
+	}
+	progress.Report(ctx, 4, steps, fmt.Sprintf("fetched prices of %d missing items", len(missingIDs)))
+
+	// Components follow their parent, so walking backwards totals children first
+	unpriced := make([]bool, len(components))
+	for i := len(components) - 1; i >= 0; i-- {
+		component := &components[i]
+		if isMissingLeaf(components, i) {
+			if price := prices[component.ID].Sells.UnitPrice; component.Kind == RequirementItem && price > 0 {
+				component.Cost = price * component.Missing
+			} else {
+				unpriced[i] = true
+			}
+		}
+		if walk.parents[i] >= 0 {
+			components[walk.parents[i]].Cost += component.Cost
+		} else {
+			result.EstimatedCost += component.Cost
+		}
+	}
+
+	names, err := c.GetItemNames(ctx, append(itemIDs, id), lang)
+	if err != nil {
+		c.logger.Warn("Failed to get item names", "error", err)
+	}
+	currencies := map[int]Currency{}
+	if len(currencyIDs) > 0 {
+		if currencies, err = c.GetCurrencies(ctx, currencyIDs, lang); err != nil {
+			c.logger.Warn("Failed to get currency metadata", "error", err)
+		}
+	}
+	achievements := map[int]Achievement{}
+	if len(achievementIDs) > 0 {
+		if achievements, err = c.GetAchievements(ctx, achievementIDs, lang); err != nil {
+			c.logger.Warn("Failed to get achievement metadata", "error", err)
+		}
+	}
+	progress.Report(ctx, 5, steps, "fetched names")
+
+	for i := range components {
+		component := &components[i]
+		switch component.Kind {
+		case RequirementItem:
+			component.Name = names[component.ID]
+		case RequirementCurrency:
+			component.Name = currencies[component.ID].Name
+		case RequirementAchievement:
+			component.Name = achievements[component.ID].Name
+		}
+		if component.Cost > 0 {
+			component.CostFormatted = FormatCoins(component.Cost)
+		}
+	}
+	for i := range components {
+		if walk.parents[i] >= 0 {
+			components[i].Parent = components[walk.parents[i]].Name
+		}
+		if unpriced[i] {
+			result.Unpriced = append(result.Unpriced, components[i].Name)
+		}
+	}
+
+	result.Name = names[id]
+	result.Components = components
+	result.EstimatedCostFormatted = FormatCoins(result.EstimatedCost)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// getHeldItems retrieves the item stacks in the bank, material storage, shared inventory
+// and character bags of the account
+func (c *Client) getHeldItems(ctx context.Context, apiKey string) ([]ItemStack, error) {
+	var held []ItemStack
+	for _, get := range []func(context.Context, string) ([]ItemStack, error){
+		c.GetBank, c.GetMaterials, c.GetSharedInventory,
+	} {
+		stacks, err := get(ctx, apiKey)
+		if err != nil {
+			return nil, err
+		}
+		held = append(held, stacks...)
+	}
+
+	names, err := c.GetCharacterNames(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		stacks, err := c.GetCharacterInventory(ctx, apiKey, name)
+		if err != nil {
+			return nil, err
+		}
+		held = append(held, stacks...)
+	}
+	return held, nil
+}
+
+// requirementKinds reports the kinds of requirements used anywhere in a recipe
+func requirementKinds(recipe []Requirement) map[RequirementKind]bool {
+	kinds := make(map[RequirementKind]bool)
+	for _, req := range recipe {
+		kinds[req.Kind] = true
+		for kind := range requirementKinds(req.Components) {
+			kinds[kind] = true
+		}
+	}
+	return kinds
+}
+
+// isMissingLeaf reports whether a component is missing and not made from components,
+// which are listed right after it one level deeper
+func isMissingLeaf(components []LegendaryComponent, i int) bool {
+	if components[i].Missing == 0 {
+		return false
+	}
+	return i+1 == len(components) || components[i+1].Depth <= components[i].Depth
+}
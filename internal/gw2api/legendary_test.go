@@ -0,0 +1,283 @@
+package gw2api
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AlyxPink/gw2-mcp/internal/cache"
+	"github.com/AlyxPink/gw2-mcp/internal/gw2mock"
+)
+
+func TestClient_GetLegendaryArmory(t *testing.T) {
+	mock := httptest.NewServer(gw2mock.New())
+	defer mock.Close()
+
+	client := NewClient(cache.NewManager(), nil, WithBaseURL(mock.URL+"/v2"))
+
+	tests := []struct {
+		name         string
+		query        ArmoryQuery
+		wantUnlocked []string
+		wantMissing  []string
+		wantTotal    int
+	}{
+		{
+			name:         "every item",
+			query:        ArmoryQuery{Lang: LanguageEnglish},
+			wantUnlocked: []string{"Aurora", "Eternity"},
+			wantTotal:    4,
+		},
+		{
+			name:         "missing items",
+			query:        ArmoryQuery{Lang: LanguageEnglish, IncludeMissing: true},
+			wantUnlocked: []string{"Aurora", "Eternity"},
+			wantMissing:  []string{"Sunrise", "Twilight"},
+			wantTotal:    4,
+		},
+		{
+			name:         "subtype filter",
+			query:        ArmoryQuery{Type: "greatsword", Lang: LanguageEnglish, IncludeMissing: true},
+			wantUnlocked: []string{"Eternity"},
+			wantMissing:  []string{"Sunrise", "Twilight"},
+			wantTotal:    3,
+		},
+		{
+			name:         "type filter",
+			query:        ArmoryQuery{Type: "Trinket", Lang: LanguageEnglish},
+			wantUnlocked: []string{"Aurora"},
+			wantTotal:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			armory, err := client.GetLegendaryArmory(context.Background(), gw2mock.KeyFull, tt.query)
+			if err != nil {
+				t.Fatalf("GetLegendaryArmory failed: %v", err)
+			}
+			if got := armoryNames(armory.Unlocked); !slices.Equal(got, tt.wantUnlocked) {
+				t.Errorf("Expected unlocked %v, got %v", tt.wantUnlocked, got)
+			}
+			if got := armoryNames(armory.Missing); !slices.Equal(got, tt.wantMissing) {
+				t.Errorf("Expected missing %v, got %v", tt.wantMissing, got)
+			}
+			if armory.Total != tt.wantTotal || armory.UnlockedCount+armory.MissingCount != tt.wantTotal {
+				t.Errorf("Expected %d items, got %d (%d unlocked, %d missing)", tt.wantTotal, armory.Total,
+					armory.UnlockedCount, armory.MissingCount)
+			}
+		})
+	}
+
+	armory, err := client.GetLegendaryArmory(context.Background(), gw2mock.KeyFull, ArmoryQuery{Lang: LanguageEnglish})
+	if err != nil {
+		t.Fatalf("GetLegendaryArmory failed: %v", err)
+	}
+	want := ArmoryItem{Name: "Eternity", Type: "Weapon", Subtype: "Greatsword", ID: 30689, Count: 1, MaxCount: 2}
+	if armory.Unlocked[1] != want {
+		t.Errorf("Expected %+v, got %+v", want, armory.Unlocked[1])
+	}
+
+	if _, err := client.GetLegendaryArmory(context.Background(), gw2mock.KeyTradingPost,
+		ArmoryQuery{Lang: LanguageEnglish}); err == nil || !strings.Contains(err.Error(), "requires scope inventories") {
+		t.Errorf("Expected a missing scope error, got %v", err)
+	}
+}
+
+func TestClient_FindLegendary(t *testing.T) {
+	mock := httptest.NewServer(gw2mock.New())
+	defer mock.Close()
+
+	client := NewClient(cache.NewManager(), nil, WithBaseURL(mock.URL+"/v2"))
+
+	tests := []struct {
+		name    string
+		query   string
+		want    int
+		wantErr string
+	}{
+		{name: "ID", query: "30704", want: 30704},
+		{name: "name", query: "Twilight", want: 30704},
+		{name: "case insensitive substring", query: " twi ", want: 30704},
+		{name: "unknown ID", query: "30689", wantErr: "no recipe known for item 30689"},
+		{name: "trinket", query: "aurora", want: 81908},
+		{name: "unknown name", query: "Eternity", wantErr: `no recipe known for "Eternity", known legendaries: Twilight, Aurora`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := client.FindLegendary(context.Background(), tt.query, LanguageEnglish)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindLegendary failed: %v", err)
+			}
+			if id != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, id)
+			}
+		})
+	}
+}
+
+func TestClient_GetLegendaryProgress(t *testing.T) {
+	mock := httptest.NewServer(gw2mock.New())
+	defer mock.Close()
+
+	client := NewClient(cache.NewManager(), nil, WithBaseURL(mock.URL+"/v2"))
+	result, err := client.GetLegendaryProgress(context.Background(), gw2mock.KeyFull, 30704, LanguageEnglish)
+	if err != nil {
+		t.Fatalf("GetLegendaryProgress failed: %v", err)
+	}
+
+	if result.Name != "Twilight" || result.Unlocked != 0 {
+		t.Errorf("Expected Twilight not unlocked, got %q unlocked %d times", result.Name, result.Unlocked)
+	}
+	if result.ComponentsDone != 0 || result.ComponentsTotal != 4 {
+		t.Errorf("Expected 0 of 4 components done, got %d of %d", result.ComponentsDone, result.ComponentsTotal)
+	}
+
+	// Dusk at 1520g, then the missing materials of the gifts at their lowest sell listings
+	if result.EstimatedCost != 18840590 || result.EstimatedCostFormatted != "1884g 5s 90c" {
+		t.Errorf("Expected 1884g 5s 90c, got %d (%s)", result.EstimatedCost, result.EstimatedCostFormatted)
+	}
+	wantUnpriced := []string{
+		"Mystic Clover", "Gift of Exploration", "Gift of Battle", "Obsidian Shard", "Icy Runestone",
+	}
+	if !slices.Equal(result.Unpriced, wantUnpriced) {
+		t.Errorf("Expected unpriced %v, got %v", wantUnpriced, result.Unpriced)
+	}
+
+	components := make(map[string]LegendaryComponent, len(result.Components))
+	for _, component := range result.Components {
+		components[component.Name] = component
+	}
+	tests := []struct {
+		name string
+		want LegendaryComponent
+	}{
+		{name: "held in the bank, materials and bags", want: LegendaryComponent{
+			Name: "Glob of Ectoplasm", Kind: RequirementItem, Parent: "Gift of Fortune", CostFormatted: "18g 3s 90c",
+			ID: 19721, Depth: 1, Required: 250, Owned: 180, Missing: 70, Cost: 180390,
+		}},
+		{name: "held in full", want: LegendaryComponent{
+			Name: "Pile of Crystalline Dust", Kind: RequirementItem, Parent: "Gift of Magic",
+			ID: 24277, Depth: 2, Required: 250, Owned: 250, Done: true,
+		}},
+		{name: "made from currency", want: LegendaryComponent{
+			Name: "Bloodstone Shard", Kind: RequirementItem, Parent: "Gift of Mastery",
+			ID: 20797, Depth: 1, Required: 1, Missing: 1, Done: true,
+		}},
+		{name: "currency", want: LegendaryComponent{
+			Name: "Spirit Shard", Kind: RequirementCurrency, Parent: "Bloodstone Shard",
+			ID: 23, Depth: 2, Required: 200, Owned: 200, Done: true,
+		}},
+		{name: "priced from components", want: LegendaryComponent{
+			Name: "Gift of Magic", Kind: RequirementItem, Parent: "Gift of Fortune", CostFormatted: "148g 40s",
+			ID: 19673, Depth: 1, Required: 1, Missing: 1, Cost: 1484000,
+		}},
+		{name: "already set aside for another gift", want: LegendaryComponent{
+			Name: "Vial of Powerful Blood", Kind: RequirementItem, Parent: "Gift of Twilight", CostFormatted: "27g",
+			ID: 24295, Depth: 1, Required: 100, Missing: 100, Cost: 270000,
+		}},
+		{name: "priced from nested components", want: LegendaryComponent{
+			Name: "Gift of Twilight", Kind: RequirementItem, CostFormatted: "36g 37s",
+			ID: 19648, Required: 1, Missing: 1, Cost: 363700,
+		}},
+		{name: "account bound", want: LegendaryComponent{
+			Name: "Mystic Clover", Kind: RequirementItem, Parent: "Gift of Fortune",
+			ID: 19675, Depth: 1, Required: 77, Owned: 5, Missing: 72,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := components[tt.want.Name]; got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := client.GetLegendaryProgress(context.Background(), gw2mock.KeyFull, 30689, LanguageEnglish); err == nil {
+		t.Error("Expected an error for a legendary without a known recipe")
+	}
+}
+
+func TestClient_GetLegendaryProgress_Achievements(t *testing.T) {
+	mock := httptest.NewServer(gw2mock.New())
+	defer mock.Close()
+
+	client := NewClient(cache.NewManager(), nil, WithBaseURL(mock.URL+"/v2"))
+	result, err := client.GetLegendaryProgress(context.Background(), gw2mock.KeyFull, 81908, LanguageEnglish)
+	if err != nil {
+		t.Fatalf("GetLegendaryProgress failed: %v", err)
+	}
+
+	if result.Name != "Aurora" || result.Unlocked != 1 || result.ComponentsDone != 1 || result.ComponentsTotal != 4 {
+		t.Errorf("Expected Aurora unlocked once with 1 of 4 components done, got %q unlocked %d times with %d of %d",
+			result.Name, result.Unlocked, result.ComponentsDone, result.ComponentsTotal)
+	}
+
+	// Only the Mystic Coins have a price; the collections and map materials are account bound
+	if result.EstimatedCost != 689940 || result.EstimatedCostFormatted != "68g 99s 40c" {
+		t.Errorf("Expected 68g 99s 40c, got %d (%s)", result.EstimatedCost, result.EstimatedCostFormatted)
+	}
+	wantUnpriced := []string{
+		"Aurora: Empowering", "Blood Ruby", "Petrified Wood", "Fresh Winterberry", "Jade Shard",
+		"Fire Orchid Blossom", "Orrian Pearl", "Unbound Magic",
+	}
+	if !slices.Equal(result.Unpriced, wantUnpriced) {
+		t.Errorf("Expected unpriced %v, got %v", wantUnpriced, result.Unpriced)
+	}
+
+	components := make(map[string]LegendaryComponent, len(result.Components))
+	for _, component := range result.Components {
+		components[component.Name] = component
+	}
+	tests := []struct {
+		name string
+		want LegendaryComponent
+	}{
+		{name: "collection done", want: LegendaryComponent{
+			Name: "Aurora: Awakening", Kind: RequirementAchievement, ID: 3213, Required: 12, Owned: 12, Done: true,
+		}},
+		{name: "collection in progress", want: LegendaryComponent{
+			Name: "Aurora: Empowering", Kind: RequirementAchievement, ID: 3292, Required: 8, Owned: 5, Missing: 3,
+		}},
+		{name: "currency of a gift", want: LegendaryComponent{
+			Name: "Unbound Magic", Kind: RequirementCurrency, Parent: "Gift of Aurora",
+			ID: 32, Depth: 1, Required: 1000, Missing: 1000,
+		}},
+		{name: "partly held", want: LegendaryComponent{
+			Name: "Mystic Coin", Kind: RequirementItem, CostFormatted: "68g 99s 40c",
+			ID: 19976, Required: 100, Owned: 40, Missing: 60, Cost: 689940,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := components[tt.want.Name]; got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := client.GetLegendaryProgress(context.Background(), gw2mock.KeyTradingPost, 81908,
+		LanguageEnglish); err == nil {
+		t.Error("Expected a missing scope error")
+	}
+}
+
+// armoryNames returns the names of armory items
+func armoryNames(items []ArmoryItem) []string {
+	var names []string
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names
+}
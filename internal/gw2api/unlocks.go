@@ -0,0 +1,380 @@
+package gw2api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AlyxPink/gw2-mcp/internal/progress"
+)
+
+// UnlockKind is a kind of account unlock, named after its /v2/account endpoint
+type UnlockKind string
+
+const (
+	// UnlockSkins are wardrobe skins of armor, weapons, backs and gathering tools
+	UnlockSkins UnlockKind = "skins"
+	// UnlockDyes are dye colors
+	UnlockDyes UnlockKind = "dyes"
+	// UnlockMinis are miniatures
+	UnlockMinis UnlockKind = "minis"
+	// UnlockOutfits are outfits
+	UnlockOutfits UnlockKind = "outfits"
+	// UnlockGliders are glider skins
+	UnlockGliders UnlockKind = "gliders"
+	// UnlockMountSkins are mount skins
+	UnlockMountSkins UnlockKind = "mounts/skins"
+	// UnlockMountTypes are mounts, identified by name such as raptor
+	UnlockMountTypes UnlockKind = "mounts/types"
+	// UnlockNovelties are novelties such as chairs, instruments and tonics
+	UnlockNovelties UnlockKind = "novelties"
+	// UnlockFinishers are PvP and WvW finishers
+	UnlockFinishers UnlockKind = "finishers"
+	// UnlockMailCarriers are mail carriers
+	UnlockMailCarriers UnlockKind = "mailcarriers"
+	// UnlockTitles are titles
+	UnlockTitles UnlockKind = "titles"
+	// UnlockEmotes are unlockable emotes, identified by name such as shiver
+	UnlockEmotes UnlockKind = "emotes"
+	// UnlockJadeBots are jade bot skins
+	UnlockJadeBots UnlockKind = "jadebots"
+	// UnlockSkiffs are skiff skins
+	UnlockSkiffs UnlockKind = "skiffs"
+)
+
+// UnlockKinds returns every supported unlock kind
+func UnlockKinds() []UnlockKind {
+	return []UnlockKind{
+		UnlockSkins, UnlockDyes, UnlockMinis, UnlockOutfits, UnlockGliders, UnlockMountSkins, UnlockMountTypes,
+		UnlockNovelties, UnlockFinishers, UnlockMailCarriers, UnlockTitles, UnlockEmotes, UnlockJadeBots,
+		UnlockSkiffs,
+	}
+}
+
+// ParseUnlockKind validates an unlock kind
+func ParseUnlockKind(s string) (UnlockKind, error) {
+	kind := UnlockKind(strings.ToLower(strings.TrimSpace(s)))
+	for _, supported := range UnlockKinds() {
+		if kind == supported {
+			return kind, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported unlock kind %q", s)
+}
+
+// metadataPath returns the path of the endpoint describing every unlock of the kind
+func (k UnlockKind) metadataPath() string {
+	if k == UnlockDyes {
+		return "/colors"
+	}
+	return "/" + string(k)
+}
+
+// UnlockList selects the unlocks listed by a report
+type UnlockList string
+
+const (
+	// UnlockListMissing lists the unlocks the account does not have
+	UnlockListMissing UnlockList = "missing"
+	// UnlockListOwned lists the unlocks the account has
+	UnlockListOwned UnlockList = "owned"
+	// UnlockListNone only counts unlocks
+	UnlockListNone UnlockList = "none"
+)
+
+// ParseUnlockList validates the unlocks to list, defaulting to the missing ones
+func ParseUnlockList(s string) (UnlockList, error) {
+	switch list := UnlockList(strings.ToLower(strings.TrimSpace(s))); list {
+	case "":
+		return UnlockListMissing, nil
+	case UnlockListMissing, UnlockListOwned, UnlockListNone:
+		return list, nil
+	default:
+		return "", fmt.Errorf("unsupported list %q, expected missing, owned or none", s)
+	}
+}
+
+// Unlock is something an account can unlock, such as a skin or a dye
+type Unlock struct {
+	ID       string `json:"id"` // a number, or a name for mount types and emotes
+	Name     string `json:"name"`
+	Category string `json:"category,omitempty"` // e.g. Armor for skins, the hue of dyes or the mount of mount skins
+	Subtype  string `json:"subtype,omitempty"`  // e.g. Coat for skins or the material of dyes
+}
+
+// unlockRecord holds the fields of the unlock endpoints used to describe an unlock
+type unlockRecord struct {
+	ID      json.RawMessage `json:"id"`
+	Name    string          `json:"name"`
+	Type    string          `json:"type"` // skins
+	Details struct {
+		Type string `json:"type"`
+	} `json:"details"` // skins
+	Categories []string `json:"categories"` // dyes: hue, material and rarity
+	Mount      string   `json:"mount"`      // mount skins
+	Slot       string   `json:"slot"`       // novelties
+	Commands   []string `json:"commands"`   // emotes, which have no name
+}
+
+// unlock converts a record to an unlock
+func (r unlockRecord) unlock() (Unlock, error) {
+	id, err := unlockID(r.ID)
+	if err != nil {
+		return Unlock{}, err
+	}
+
+	unlock := Unlock{ID: id, Name: r.Name, Subtype: r.Details.Type}
+	if unlock.Name == "" && len(r.Commands) > 0 {
+		unlock.Name = r.Commands[0]
+	}
+	if unlock.Name == "" {
+		unlock.Name = id
+	}
+	for _, category := range []string{r.Type, r.Mount, r.Slot} {
+		if category != "" {
+			unlock.Category = category
+		}
+	}
+	if len(r.Categories) > 0 {
+		unlock.Category = r.Categories[0]
+	}
+	if len(r.Categories) > 1 {
+		unlock.Subtype = r.Categories[1]
+	}
+	return unlock, nil
+}
+
+// unlockID returns the ID of an unlock record or of an account unlock entry, which is a
+// number, a name, or an object with an id field such as the finishers of an account
+func unlockID(raw json.RawMessage) (string, error) {
+	if len(raw) > 0 && raw[0] == '{' {
+		var entry struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return "", err
+		}
+		raw = entry.ID
+	}
+
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		return name, nil
+	}
+	var id int
+	if err := json.Unmarshal(raw, &id); err != nil {
+		return "", fmt.Errorf("invalid unlock ID %s", raw)
+	}
+	return strconv.Itoa(id), nil
+}
+
+// GetUnlocks retrieves every unlock of a kind in the given language. The first call
+// fetches them in batches of 200, e.g. about 50 requests for skins, and caches the list.
+func (c *Client) GetUnlocks(ctx context.Context, kind UnlockKind, lang Language) ([]Unlock, error) {
+	cacheKey := c.cache.GetUnlocksKey(string(kind), string(lang))
+
+	// Try cache first
+	var unlocks []Unlock
+	if c.cache.GetJSON(cacheKey, &unlocks) {
+		c.logger.Debug("Unlocks cache hit", "kind", kind, "lang", lang)
+		return unlocks, nil
+	}
+
+	c.logger.Debug("Unlocks cache miss, fetching from API", "kind", kind, "lang", lang)
+
+	path := kind.metadataPath()
+	var rawIDs []json.RawMessage
+	if err := c.getJSON(ctx, path, "", &rawIDs); err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", kind, err)
+	}
+	ids := make([]string, len(rawIDs))
+	for i, raw := range rawIDs {
+		id, err := unlockID(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", kind, err)
+		}
+		ids[i] = url.QueryEscape(id)
+	}
+
+	unlocks = make([]Unlock, 0, len(ids))
+	for start := 0; start < len(ids); start += itemBatchSize {
+		batch := ids[start:min(start+itemBatchSize, len(ids))]
+
+		var records []unlockRecord
+		batchPath := path + "?ids=" + strings.Join(batch, ",") + "&lang=" + string(lang)
+		if err := c.getJSON(ctx, batchPath, "", &records); err != nil {
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+				return nil, fmt.Errorf("failed to fetch %s: %w", kind, err)
+			}
+			// none of the batch exists anymore
+		}
+		for _, record := range records {
+			unlock, err := record.unlock()
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch %s: %w", kind, err)
+			}
+			unlocks = append(unlocks, unlock)
+		}
+
+		done := start + len(batch)
+		progress.Report(ctx, done, len(ids), fmt.Sprintf("fetched %d/%d %s", done, len(ids), kind))
+	}
+
+	// Cache the result
+	if err := c.cache.SetJSON(cacheKey, unlocks, c.cache.TTLs().StaticData); err != nil {
+		c.logger.Warn("Failed to cache unlocks", "kind", kind, "error", err)
+	}
+
+	return unlocks, nil
+}
+
+// GetAccountUnlocks retrieves the IDs of the unlocks of a kind owned by the account, which
+// needs the unlocks scope
+func (c *Client) GetAccountUnlocks(ctx context.Context, apiKey string, kind UnlockKind) ([]string, error) {
+	apiKeyHash := HashAPIKey(apiKey)
+	cacheKey := c.cache.GetAccountUnlocksKey(apiKeyHash, string(kind))
+
+	// Try cache first
+	var ids []string
+	if c.cache.GetJSON(cacheKey, &ids) {
+		c.logger.Debug("Account unlocks cache hit", "api_key_hash", apiKeyHash, "kind", kind)
+		return ids, nil
+	}
+
+	c.logger.Debug("Account unlocks cache miss, fetching from API", "api_key_hash", apiKeyHash, "kind", kind)
+
+	var entries []json.RawMessage
+	if err := c.getJSON(ctx, "/account/"+string(kind), apiKey, &entries); err != nil {
+		return nil, fmt.Errorf("failed to fetch account %s: %w", kind, err)
+	}
+	ids = make([]string, len(entries))
+	for i, entry := range entries {
+		id, err := unlockID(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch account %s: %w", kind, err)
+		}
+		ids[i] = id
+	}
+
+	// Cache the result
+	if err := c.cache.SetJSON(cacheKey, ids, c.cache.TTLs().WalletData); err != nil {
+		c.logger.Warn("Failed to cache account unlocks", "kind", kind, "error", err)
+	}
+
+	return ids, nil
+}
+
+// UnlockQuery selects the unlocks of a report
+type UnlockQuery struct {
+	Kind     UnlockKind
+	Category string // category or subtype, every unlock when empty
+	Name     string // part of the name, every unlock when empty
+	List     UnlockList
+	Lang     Language
+	Limit    int // maximum number of unlocks listed
+}
+
+// UnlockCategory is the completion of one category of unlocks
+type UnlockCategory struct {
+	Name  string `json:"name"`
+	Owned int    `json:"owned"`
+	Total int    `json:"total"`
+}
+
+// UnlockReport tells how complete the collection of an account is for a kind of unlocks
+type UnlockReport struct {
+	Kind UnlockKind `json:"kind"`
+	List UnlockList `json:"list"`
+	// Unlocks are the owned or missing unlocks selected by list, by category and name, up to the limit
+	Unlocks           []Unlock         `json:"unlocks"`
+	Categories        []UnlockCategory `json:"categories,omitempty"` // completion of each category
+	Owned             int              `json:"owned"`
+	Missing           int              `json:"missing"`
+	Total             int              `json:"total"`
+	CompletionPercent float64          `json:"completion_percent"`
+}
+
+// GetUnlockReport reports the unlocks of a kind the account owning the API key has and
+// misses, among those matching the category and name of the query. Unlocks the account
+// owns that the API no longer describes are left out.
+func (c *Client) GetUnlockReport(ctx context.Context, apiKey string, query UnlockQuery) (*UnlockReport, error) {
+	ownedIDs, err := c.GetAccountUnlocks(ctx, apiKey, query.Kind)
+	if err != nil {
+		return nil, err
+	}
+	unlocks, err := c.GetUnlocks(ctx, query.Kind, query.Lang)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make(map[string]bool, len(ownedIDs))
+	for _, id := range ownedIDs {
+		owned[id] = true
+	}
+
+	report := UnlockReport{Kind: query.Kind, List: query.List, Unlocks: []Unlock{}}
+	categories := make(map[string]*UnlockCategory)
+	name := strings.ToLower(query.Name)
+	for _, unlock := range unlocks {
+		if query.Category != "" && !strings.EqualFold(unlock.Category, query.Category) &&
+			!strings.EqualFold(unlock.Subtype, query.Category) {
+			continue
+		}
+		if name != "" && !strings.Contains(strings.ToLower(unlock.Name), name) {
+			continue
+		}
+
+		report.Total++
+		if owned[unlock.ID] {
+			report.Owned++
+		}
+		if unlock.Category != "" {
+			category := categories[unlock.Category]
+			if category == nil {
+				category = &UnlockCategory{Name: unlock.Category}
+				categories[unlock.Category] = category
+			}
+			category.Total++
+			if owned[unlock.ID] {
+				category.Owned++
+			}
+		}
+
+		if query.List == UnlockListOwned && owned[unlock.ID] || query.List == UnlockListMissing && !owned[unlock.ID] {
+			report.Unlocks = append(report.Unlocks, unlock)
+		}
+	}
+	report.Missing = report.Total - report.Owned
+	if report.Total > 0 {
+		report.CompletionPercent = math.Round(float64(report.Owned)/float64(report.Total)*10000) / 100
+	}
+
+	for _, category := range categories {
+		report.Categories = append(report.Categories, *category)
+	}
+	sort.Slice(report.Categories, func(i, j int) bool {
+		return report.Categories[i].Name < report.Categories[j].Name
+	})
+
+	sort.Slice(report.Unlocks, func(i, j int) bool {
+		a, b := report.Unlocks[i], report.Unlocks[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+	report.Unlocks = report.Unlocks[:min(query.Limit, len(report.Unlocks))]
+
+	return &report, nil
+}
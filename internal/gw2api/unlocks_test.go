@@ -0,0 +1,200 @@
+package gw2api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AlyxPink/gw2-mcp/internal/cache"
+	"github.com/AlyxPink/gw2-mcp/internal/gw2mock"
+)
+
+func TestParseUnlockKind(t *testing.T) {
+	for _, kind := range UnlockKinds() {
+		if parsed, err := ParseUnlockKind(" " + strings.ToUpper(string(kind)) + " "); err != nil || parsed != kind {
+			t.Errorf("Expected %s, got %q (%v)", kind, parsed, err)
+		}
+	}
+	if _, err := ParseUnlockKind("wardrobe"); err == nil {
+		t.Error("Expected an error for an unknown kind")
+	}
+}
+
+func TestUnlockID(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{raw: `4677`, want: "4677"},
+		{raw: `"raptor"`, want: "raptor"},
+		{raw: `{"id":3,"permanent":false,"quantity":5}`, want: "3"},
+		{raw: `{"id":"beckon"}`, want: "beckon"},
+		{raw: `1.5`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := unlockID(json.RawMessage(tt.raw))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClient_GetUnlocks(t *testing.T) {
+	mock := gw2mock.New()
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	client := NewClient(cache.NewManager(), nil, WithBaseURL(server.URL+"/v2"))
+	ctx := context.Background()
+
+	tests := []struct {
+		kind UnlockKind
+		want Unlock
+	}{
+		{kind: UnlockSkins, want: Unlock{ID: "10", Name: "Seer Coat", Category: "Armor", Subtype: "Coat"}},
+		{kind: UnlockSkins, want: Unlock{ID: "6013", Name: "Wings of Glory", Category: "Back"}},
+		{kind: UnlockDyes, want: Unlock{ID: "104", Name: "Abyss", Category: "Gray", Subtype: "Metal"}},
+		{kind: UnlockMountSkins, want: Unlock{ID: "27", Name: "Tiger Raptor", Category: "raptor"}},
+		{kind: UnlockMountTypes, want: Unlock{ID: "griffon", Name: "Griffon"}},
+		{kind: UnlockNovelties, want: Unlock{ID: "2", Name: "Magnanimous Choir Bell", Category: "Music"}},
+		{kind: UnlockEmotes, want: Unlock{ID: "shiver", Name: "/shiver"}},
+		{kind: UnlockSkiffs, want: Unlock{ID: "2", Name: "Shark Skiff"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.kind)+" "+tt.want.ID, func(t *testing.T) {
+			unlocks, err := client.GetUnlocks(ctx, tt.kind, LanguageEnglish)
+			if err != nil {
+				t.Fatalf("GetUnlocks failed: %v", err)
+			}
+			if !slices.Contains(unlocks, tt.want) {
+				t.Errorf("Expected %+v among %+v", tt.want, unlocks)
+			}
+		})
+	}
+
+	// Unlocks are cached per kind and language
+	requests := mock.Requests()
+	if _, err := client.GetUnlocks(ctx, UnlockSkins, LanguageEnglish); err != nil {
+		t.Fatalf("GetUnlocks failed: %v", err)
+	}
+	if mock.Requests() != requests {
+		t.Errorf("Expected cached skins, got %d more requests", mock.Requests()-requests)
+	}
+}
+
+func TestClient_GetUnlockReport(t *testing.T) {
+	mock := httptest.NewServer(gw2mock.New())
+	defer mock.Close()
+
+	client := NewClient(cache.NewManager(), nil, WithBaseURL(mock.URL+"/v2"))
+
+	tests := []struct {
+		name           string
+		query          UnlockQuery
+		wantUnlocks    []string
+		wantOwned      int
+		wantTotal      int
+		wantCompletion float64
+		wantCategories []UnlockCategory
+	}{
+		{
+			name:           "missing skins",
+			query:          UnlockQuery{Kind: UnlockSkins, List: UnlockListMissing, Limit: 10},
+			wantUnlocks:    []string{"Seer Pants", "Wings of Glory", "Sunrise"},
+			wantOwned:      3,
+			wantTotal:      6,
+			wantCompletion: 50,
+			wantCategories: []UnlockCategory{
+				{Name: "Armor", Owned: 1, Total: 2},
+				{Name: "Back", Total: 1},
+				{Name: "Weapon", Owned: 2, Total: 3},
+			},
+		},
+		{
+			name:           "owned greatswords",
+			query:          UnlockQuery{Kind: UnlockSkins, Category: "greatsword", List: UnlockListOwned, Limit: 10},
+			wantUnlocks:    []string{"Eternity", "Twilight"},
+			wantOwned:      2,
+			wantTotal:      3,
+			wantCompletion: 66.67,
+			wantCategories: []UnlockCategory{{Name: "Weapon", Owned: 2, Total: 3}},
+		},
+		{
+			name:           "limit",
+			query:          UnlockQuery{Kind: UnlockDyes, List: UnlockListMissing, Limit: 1},
+			wantUnlocks:    []string{"Celestial"},
+			wantOwned:      3,
+			wantTotal:      5,
+			wantCompletion: 60,
+			wantCategories: []UnlockCategory{{Name: "Gray", Owned: 2, Total: 3}, {Name: "Red", Total: 1}},
+		},
+		{
+			name:           "name filter",
+			query:          UnlockQuery{Kind: UnlockMountSkins, Name: "RAPTOR", List: UnlockListOwned, Limit: 10},
+			wantUnlocks:    []string{"Raptor"},
+			wantOwned:      1,
+			wantTotal:      2,
+			wantCompletion: 50,
+			wantCategories: []UnlockCategory{{Name: "raptor", Owned: 1, Total: 2}},
+		},
+		{
+			name:           "finishers with uses left",
+			query:          UnlockQuery{Kind: UnlockFinishers, List: UnlockListNone, Limit: 10},
+			wantOwned:      2,
+			wantTotal:      3,
+			wantCompletion: 66.67,
+		},
+		{
+			name:           "emotes by name",
+			query:          UnlockQuery{Kind: UnlockEmotes, List: UnlockListMissing, Limit: 10},
+			wantUnlocks:    []string{"/shiver", "/step"},
+			wantOwned:      1,
+			wantTotal:      3,
+			wantCompletion: 33.33,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Lang = LanguageEnglish
+			report, err := client.GetUnlockReport(context.Background(), gw2mock.KeyFull, tt.query)
+			if err != nil {
+				t.Fatalf("GetUnlockReport failed: %v", err)
+			}
+
+			var names []string
+			for _, unlock := range report.Unlocks {
+				names = append(names, unlock.Name)
+			}
+			if !slices.Equal(names, tt.wantUnlocks) {
+				t.Errorf("Expected unlocks %v, got %v", tt.wantUnlocks, names)
+			}
+			if report.Owned != tt.wantOwned || report.Total != tt.wantTotal ||
+				report.Missing != tt.wantTotal-tt.wantOwned || report.CompletionPercent != tt.wantCompletion {
+				t.Errorf("Expected %d of %d owned (%.2f%%), got %d of %d with %d missing (%.2f%%)", tt.wantOwned,
+					tt.wantTotal, tt.wantCompletion, report.Owned, report.Total, report.Missing,
+					report.CompletionPercent)
+			}
+			if !slices.Equal(report.Categories, tt.wantCategories) {
+				t.Errorf("Expected categories %+v, got %+v", tt.wantCategories, report.Categories)
+			}
+		})
+	}
+
+	if _, err := client.GetUnlockReport(context.Background(), gw2mock.KeyWallet,
+		UnlockQuery{Kind: UnlockSkins, Lang: LanguageEnglish}); err == nil ||
+		!strings.Contains(err.Error(), "requires scope unlocks") {
+		t.Errorf("Expected a missing scope error, got %v", err)
+	}
+}
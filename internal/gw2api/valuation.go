@@ -0,0 +1,334 @@
+package gw2api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/AlyxPink/gw2-mcp/internal/progress"
+)
+
+// CoinCurrencyID is the wallet currency ID of coins
+const CoinCurrencyID = 1
+
+// ValuationBreakdown splits the value of an account by where it is held, in copper
+type ValuationBreakdown struct {
+	Wallet      int `json:"wallet"` // coins only
+	Bank        int `json:"bank"`
+	Materials   int `json:"materials"`
+	Inventories int `json:"inventories"`  // shared inventory slots and character bags
+	TradingPost int `json:"trading_post"` // buy orders, sell listings and the delivery box
+}
+
+// ItemValue is the quantity of an item held by an account and its value in copper
+type ItemValue struct {
+	Count int `json:"count"`
+	Value int `json:"value"`
+}
+
+// AccountValuation is the value of an account at one point in time. Items are valued at
+// the highest buy order after the trading post fees, which is what selling them instantly
+// yields, and items listed for sale at their listing price after the exchange fee. Items
+// without a buy order, such as account bound ones, are counted but not valued.
+type AccountValuation struct {
+	TakenAt        time.Time          `json:"taken_at"`
+	Account        string             `json:"account"`
+	TotalFormatted string             `json:"total_formatted"`
+	Currencies     map[int]int        `json:"currencies"` // wallet balances by currency ID, coins included
+	Items          map[int]ItemValue  `json:"items"`      // by item ID, across storages and sell listings
+	Unvalued       []int              `json:"unvalued"`   // IDs of held items without a buy order
+	Breakdown      ValuationBreakdown `json:"breakdown"`
+	Total          int                `json:"total"` // in copper
+}
+
+// ValueAccount values the wallet, bank, material storage, inventories and trading post
+// orders of the account owning the API key, which needs the wallet, inventories,
+// characters and tradingpost scopes. Every storage is required, so a missing scope fails
+// the valuation rather than understating it.
+func (c *Client) ValueAccount(ctx context.Context, apiKey string) (*AccountValuation, error) {
+	const steps = 6
+
+	account, err := c.GetAccount(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	valuation := AccountValuation{
+		TakenAt:    time.Now().UTC().Truncate(time.Second),
+		Account:    account.Name,
+		Currencies: make(map[int]int),
+		Items:      make(map[int]ItemValue),
+		Unvalued:   []int{},
+	}
+	progress.Report(ctx, 1, steps, "fetched account")
+
+	wallet, err := c.fetchWallet(ctx, apiKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wallet: %w", err)
+	}
+	for _, entry := range wallet {
+		if entry.Value != 0 {
+			valuation.Currencies[entry.ID] = entry.Value
+		}
+	}
+	valuation.Breakdown.Wallet = valuation.Currencies[CoinCurrencyID]
+	progress.Report(ctx, 2, steps, "fetched wallet")
+
+	bank, err := c.GetBank(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	materials, err := c.GetMaterials(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	inventories, err := c.GetSharedInventory(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	progress.Report(ctx, 3, steps, "fetched bank, materials and shared inventory")
+
+	names, err := c.GetCharacterNames(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	characters := progress.Sub(ctx, 4, steps)
+	for i, name := range names {
+		stacks, err := c.GetCharacterInventory(ctx, apiKey, name)
+		if err != nil {
+			return nil, err
+		}
+		inventories = append(inventories, stacks...)
+		progress.Report(characters, i+1, len(names), fmt.Sprintf("fetched %d/%d characters", i+1, len(names)))
+	}
+	progress.Report(ctx, 4, steps, fmt.Sprintf("fetched inventories of %d characters", len(names)))
+
+	orders := progress.Sub(ctx, 5, steps)
+	buys, err := c.GetTransactions(progress.Sub(orders, 1, 2), apiKey, TransactionsCurrent, TransactionBuys)
+	if err != nil {
+		return nil, err
+	}
+	progress.Report(orders, 1, 2, "fetched buy orders")
+	sells, err := c.GetTransactions(progress.Sub(orders, 2, 2), apiKey, TransactionsCurrent, TransactionSells)
+	if err != nil {
+		return nil, err
+	}
+	var delivery Delivery
+	if err := c.getJSON(ctx, "/commerce/delivery", apiKey, &delivery); err != nil {
+		return nil, fmt.Errorf("failed to fetch delivery box: %w", err)
+	}
+	progress.Report(ctx, 5, steps, "fetched trading post orders and delivery box")
+
+	deliveredItems := make([]ItemStack, len(delivery.Items))
+	for i, item := range delivery.Items {
+		deliveredItems[i] = ItemStack{ID: item.ID, Count: item.Count}
+	}
+
+	var ids []int
+	for _, stacks := range [][]ItemStack{bank, materials, inventories, deliveredItems} {
+		for _, stack := range stacks {
+			ids = append(ids, stack.ID)
+		}
+	}
+	prices, err := c.GetPrices(progress.Sub(ctx, 6, steps), ids)
+	if err != nil {
+		return nil, err
+	}
+	progress.Report(ctx, 6, steps, fmt.Sprintf("fetched prices of %d items", len(prices)))
+
+	unvalued := make(map[int]bool)
+	value := func(stacks []ItemStack) int {
+		total := 0
+		for _, stack := range stacks {
+			worth := 0
+			if price := prices[stack.ID].Buys.UnitPrice; price > 0 {
+				worth = price*stack.Count - SellFees(price, stack.Count)
+			} else {
+				unvalued[stack.ID] = true
+			}
+			valuation.addItem(stack.ID, stack.Count, worth)
+			total += worth
+		}
+		return total
+	}
+
+	valuation.Breakdown.Bank = value(bank)
+	valuation.Breakdown.Materials = value(materials)
+	valuation.Breakdown.Inventories = value(inventories)
+	valuation.Breakdown.TradingPost = delivery.Coins + value(deliveredItems)
+	for _, buy := range buys {
+		// Coins of buy orders are refunded when cancelled
+		valuation.Breakdown.TradingPost += buy.Price * buy.Quantity
+	}
+	for _, sell := range sells {
+		// The listing fee of sell listings is already paid
+		worth := sell.Price*sell.Quantity - ExchangeFee(sell.Price, sell.Quantity)
+		valuation.addItem(sell.ItemID, sell.Quantity, worth)
+		valuation.Breakdown.TradingPost += worth
+	}
+
+	for id := range unvalued {
+		valuation.Unvalued = append(valuation.Unvalued, id)
+	}
+	sort.Ints(valuation.Unvalued)
+
+	breakdown := valuation.Breakdown
+	valuation.Total = breakdown.Wallet + breakdown.Bank + breakdown.Materials + breakdown.Inventories +
+		breakdown.TradingPost
+	valuation.TotalFormatted = FormatCoins(valuation.Total)
+
+	return &valuation, nil
+}
+
+// addItem adds held items and their value to the valuation
+func (v *AccountValuation) addItem(id, count, value int) {
+	item := v.Items[id]
+	item.Count += count
+	item.Value += value
+	v.Items[id] = item
+}
+
+// CurrencyChange is the change of a wallet balance between two valuations
+type CurrencyChange struct {
+	Name   string `json:"name,omitempty"`
+	ID     int    `json:"id"`
+	From   int    `json:"from"`
+	To     int    `json:"to"`
+	Change int    `json:"change"`
+}
+
+// ItemChange is the change of an item held between two valuations
+type ItemChange struct {
+	Name        string `json:"name,omitempty"`
+	ID          int    `json:"id"`
+	CountChange int    `json:"count_change"`
+	ValueChange int    `json:"value_change"` // in copper, including price moves of items kept
+}
+
+// AccountDiff compares two valuations of an account
+type AccountDiff struct {
+	From            time.Time          `json:"from"`
+	To              time.Time          `json:"to"`
+	ChangeFormatted string             `json:"change_formatted"`
+	Currencies      []CurrencyChange   `json:"currencies"` // changed balances, largest change first
+	Items           []ItemChange       `json:"items"`      // largest value change first
+	Breakdown       ValuationBreakdown `json:"breakdown"`  // change of each part
+	FromTotal       int                `json:"from_total"`
+	ToTotal         int                `json:"to_total"`
+	Change          int                `json:"change"` // in copper
+	ChangePercent   float64            `json:"change_percent"`
+	ItemsChanged    int                `json:"items_changed"` // before the limit
+}
+
+// DiffValuations compares two valuations of an account, keeping the itemLimit items whose
+// value changed the most, with currency and item names in the given language. Names are
+// left empty when they cannot be fetched.
+func (c *Client) DiffValuations(ctx context.Context, from, to *AccountValuation, itemLimit int,
+	lang Language,
+) (*AccountDiff, error) {
+	diff := AccountDiff{
+		From:      from.TakenAt,
+		To:        to.TakenAt,
+		FromTotal: from.Total,
+		ToTotal:   to.Total,
+		Change:    to.Total - from.Total,
+		Breakdown: ValuationBreakdown{
+			Wallet:      to.Breakdown.Wallet - from.Breakdown.Wallet,
+			Bank:        to.Breakdown.Bank - from.Breakdown.Bank,
+			Materials:   to.Breakdown.Materials - from.Breakdown.Materials,
+			Inventories: to.Breakdown.Inventories - from.Breakdown.Inventories,
+			TradingPost: to.Breakdown.TradingPost - from.Breakdown.TradingPost,
+		},
+		Currencies: []CurrencyChange{},
+		Items:      []ItemChange{},
+	}
+	diff.ChangeFormatted = FormatCoins(diff.Change)
+	if from.Total != 0 {
+		diff.ChangePercent = math.Round(float64(diff.Change)/math.Abs(float64(from.Total))*10000) / 100
+	}
+
+	var currencyIDs []int
+	for id := range unionKeys(from.Currencies, to.Currencies) {
+		if change := to.Currencies[id] - from.Currencies[id]; change != 0 {
+			diff.Currencies = append(diff.Currencies, CurrencyChange{
+				ID: id, From: from.Currencies[id], To: to.Currencies[id], Change: change,
+			})
+			currencyIDs = append(currencyIDs, id)
+		}
+	}
+	sort.Slice(diff.Currencies, func(i, j int) bool {
+		a, b := diff.Currencies[i], diff.Currencies[j]
+		if abs(a.Change) != abs(b.Change) {
+			return abs(a.Change) > abs(b.Change)
+		}
+		return a.ID < b.ID
+	})
+
+	for id := range unionKeys(from.Items, to.Items) {
+		change := ItemChange{
+			ID:          id,
+			CountChange: to.Items[id].Count - from.Items[id].Count,
+			ValueChange: to.Items[id].Value - from.Items[id].Value,
+		}
+		if change.CountChange != 0 || change.ValueChange != 0 {
+			diff.Items = append(diff.Items, change)
+		}
+	}
+	sort.Slice(diff.Items, func(i, j int) bool {
+		a, b := diff.Items[i], diff.Items[j]
+		if abs(a.ValueChange) != abs(b.ValueChange) {
+			return abs(a.ValueChange) > abs(b.ValueChange)
+		}
+		return a.ID < b.ID
+	})
+	diff.ItemsChanged = len(diff.Items)
+	diff.Items = diff.Items[:min(itemLimit, len(diff.Items))]
+
+	if len(currencyIDs) > 0 {
+		currencies, err := c.GetCurrencies(ctx, currencyIDs, lang)
+		if err != nil {
+			c.logger.Warn("Failed to get currency metadata", "error", err)
+		}
+		for i := range diff.Currencies {
+			diff.Currencies[i].Name = currencies[diff.Currencies[i].ID].Name
+		}
+	}
+
+	itemIDs := make([]int, len(diff.Items))
+	for i, item := range diff.Items {
+		itemIDs[i] = item.ID
+	}
+	names, err := c.GetItemNames(ctx, itemIDs, lang)
+	if err != nil {
+		c.logger.Warn("Failed to get item names", "error", err)
+	}
+	for i := range diff.Items {
+		diff.Items[i].Name = names[diff.Items[i].ID]
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &diff, nil
+}
+
+// unionKeys returns the keys present in either map
+func unionKeys[V any](a, b map[int]V) map[int]bool {
+	keys := make(map[int]bool, len(a)+len(b))
+	for key := range a {
+		keys[key] = true
+	}
+	for key := range b {
+		keys[key] = true
+	}
+	return keys
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
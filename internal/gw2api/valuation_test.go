@@ -0,0 +1,118 @@
+package gw2api
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlyxPink/gw2-mcp/internal/cache"
+	"github.com/AlyxPink/gw2-mcp/internal/gw2mock"
+)
+
+func TestClient_ValueAccount(t *testing.T) {
+	mock := httptest.NewServer(gw2mock.New())
+	defer mock.Close()
+
+	client := NewClient(cache.NewManager(), nil, WithBaseURL(mock.URL+"/v2"))
+	valuation, err := client.ValueAccount(context.Background(), gw2mock.KeyFull)
+	if err != nil {
+		t.Fatalf("ValueAccount failed: %v", err)
+	}
+
+	if valuation.Account != "Mock Account.1234" {
+		t.Errorf("Expected Mock Account.1234, got %q", valuation.Account)
+	}
+
+	// Items sell to the highest buy order minus 15% fees, e.g. Glob of Ectoplasm at 2412
+	// yields 2050 each; the sell listing of 100 globs at 2650 only owes the 10% exchange fee
+	want := ValuationBreakdown{
+		Wallet:      12345678,
+		Bank:        452740,
+		Materials:   1045372,
+		Inventories: 79662,
+		TradingPost: 256250 + 238500 + 1234567 + 512500 + 4081,
+	}
+	if valuation.Breakdown != want {
+		t.Errorf("Expected breakdown %+v, got %+v", want, valuation.Breakdown)
+	}
+	if valuation.Total != 16169350 || valuation.TotalFormatted != "1616g 93s 50c" {
+		t.Errorf("Expected 1616g 93s 50c, got %d (%s)", valuation.Total, valuation.TotalFormatted)
+	}
+
+	if item := valuation.Items[19721]; item != (ItemValue{Count: 530, Value: 1120000}) {
+		t.Errorf("Expected 530 globs worth 1120000, got %+v", item)
+	}
+	if !slices.Equal(valuation.Unvalued, []int{19675, 30689}) {
+		t.Errorf("Expected Mystic Clover and Eternity unvalued, got %v", valuation.Unvalued)
+	}
+	if valuation.Currencies[2] != 2841530 || valuation.Currencies[4] != 0 {
+		t.Errorf("Expected non-zero balances only, got %v", valuation.Currencies)
+	}
+
+	if _, err := client.ValueAccount(context.Background(), gw2mock.KeyWallet); err == nil ||
+		!strings.Contains(err.Error(), "requires scope inventories") {
+		t.Errorf("Expected a missing scope error, got %v", err)
+	}
+}
+
+func TestClient_DiffValuations(t *testing.T) {
+	mock := httptest.NewServer(gw2mock.New())
+	defer mock.Close()
+
+	client := NewClient(cache.NewManager(), nil, WithBaseURL(mock.URL+"/v2"))
+
+	from := &AccountValuation{
+		TakenAt:    time.Date(2026, 10, 11, 12, 0, 0, 0, time.UTC),
+		Currencies: map[int]int{1: 1000000, 2: 500, 3: 10},
+		Items: map[int]ItemValue{
+			19721: {Count: 100, Value: 205000},
+			24277: {Count: 50, Value: 46900},
+			68063: {Count: 1, Value: 4081},
+		},
+		Breakdown: ValuationBreakdown{Wallet: 1000000, Materials: 255981},
+		Total:     1255981,
+	}
+	to := &AccountValuation{
+		TakenAt:    time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+		Currencies: map[int]int{1: 1500000, 2: 500},
+		Items: map[int]ItemValue{
+			19721: {Count: 100, Value: 215000}, // price went up
+			24277: {Count: 10, Value: 9380},
+			19976: {Count: 1, Value: 8756},
+		},
+		Breakdown: ValuationBreakdown{Wallet: 1500000, Materials: 224380, Bank: 8756},
+		Total:     1733136,
+	}
+
+	diff, err := client.DiffValuations(context.Background(), from, to, 2, LanguageEnglish)
+	if err != nil {
+		t.Fatalf("DiffValuations failed: %v", err)
+	}
+
+	if diff.Change != 477155 || diff.ChangeFormatted != "47g 71s 55c" || diff.ChangePercent != 37.99 {
+		t.Errorf("Expected a change of 47g 71s 55c (37.99%%), got %d (%s, %v%%)", diff.Change,
+			diff.ChangeFormatted, diff.ChangePercent)
+	}
+	if want := (ValuationBreakdown{Wallet: 500000, Bank: 8756, Materials: -31601}); diff.Breakdown != want {
+		t.Errorf("Expected breakdown %+v, got %+v", want, diff.Breakdown)
+	}
+
+	wantCurrencies := []CurrencyChange{
+		{ID: 1, Name: "Coin", From: 1000000, To: 1500000, Change: 500000},
+		{ID: 3, Name: "Laurel", From: 10, To: 0, Change: -10},
+	}
+	if !slices.Equal(diff.Currencies, wantCurrencies) {
+		t.Errorf("Expected currencies %+v, got %+v", wantCurrencies, diff.Currencies)
+	}
+
+	wantItems := []ItemChange{
+		{ID: 24277, Name: "Pile of Crystalline Dust", CountChange: -40, ValueChange: -37520},
+		{ID: 19721, Name: "Glob of Ectoplasm", ValueChange: 10000},
+	}
+	if !slices.Equal(diff.Items, wantItems) || diff.ItemsChanged != 4 {
+		t.Errorf("Expected items %+v of 4, got %+v of %d", wantItems, diff.Items, diff.ItemsChanged)
+	}
+}
@@ -0,0 +1,708 @@
+package gw2mock
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+// API key scopes, as listed by /v2/tokeninfo
+const (
+	ScopeAccount     = "account"
+	ScopeBuilds      = "builds"
+	ScopeCharacters  = "characters"
+	ScopeGuilds      = "guilds"
+	ScopeInventories = "inventories"
+	ScopeProgression = "progression"
+	ScopePvP         = "pvp"
+	ScopeTradingPost = "tradingpost"
+	ScopeUnlocks     = "unlocks"
+	ScopeWallet      = "wallet"
+	ScopeWvW         = "wvw"
+)
+
+// Fake API keys accepted by the mock, all belonging to the same account
+const (
+	// KeyFull has every scope
+	KeyFull = "MOCK-FULL-ACCESS-KEY"
+	// KeyAccount only has the account scope
+	KeyAccount = "MOCK-ACCOUNT-ONLY-KEY"
+	// KeyWallet has the account and wallet scopes
+	KeyWallet = "MOCK-WALLET-KEY"
+	// KeyTradingPost has the account and tradingpost scopes
+	KeyTradingPost = "MOCK-TRADINGPOST-KEY"
+)
+
+// Key is a fake API key with its scopes
+type Key struct {
+	Value       string
+	Name        string
+	Permissions []string
+}
+
+// HasScope reports whether the key grants a scope
+func (k Key) HasScope(scope string) bool {
+	return slices.Contains(k.Permissions, scope)
+}
+
+// Keys returns the fake API keys accepted by the mock
+func Keys() []Key {
+	return []Key{
+		{
+			Value: KeyFull,
+			Name:  "full access",
+			Permissions: []string{
+				ScopeAccount, ScopeBuilds, ScopeCharacters, ScopeGuilds, ScopeInventories, ScopeProgression,
+				ScopePvP, ScopeTradingPost, ScopeUnlocks, ScopeWallet, ScopeWvW,
+			},
+		},
+		{Value: KeyAccount, Name: "account only", Permissions: []string{ScopeAccount}},
+		{Value: KeyWallet, Name: "wallet", Permissions: []string{ScopeAccount, ScopeWallet}},
+		{Value: KeyTradingPost, Name: "trading post", Permissions: []string{ScopeAccount, ScopeTradingPost}},
+	}
+}
+
+// currency mirrors /v2/currencies
+type currency struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Order       int    `json:"order"`
+	Icon        string `json:"icon"`
+}
+
+// item mirrors /v2/items, with only the subtype of type specific details
+type item struct {
+	Details      map[string]any `json:"details,omitempty"`
+	Name         string         `json:"name"`
+	Description  string         `json:"description,omitempty"`
+	Type         string         `json:"type"`
+	Level        int            `json:"level"`
+	Rarity       string         `json:"rarity"`
+	VendorValue  int            `json:"vendor_value"`
+	GameTypes    []string       `json:"game_types"`
+	Flags        []string       `json:"flags"`
+	Restrictions []string       `json:"restrictions"`
+	ID           int            `json:"id"`
+	ChatLink     string         `json:"chat_link"`
+	Icon         string         `json:"icon"`
+}
+
+// priceSide is the best buy or sell offer of an item
+type priceSide struct {
+	Quantity  int `json:"quantity"`
+	UnitPrice int `json:"unit_price"`
+}
+
+// price mirrors /v2/commerce/prices
+type price struct {
+	ID          int       `json:"id"`
+	Whitelisted bool      `json:"whitelisted"`
+	Buys        priceSide `json:"buys"`
+	Sells       priceSide `json:"sells"`
+}
+
+// listingTier is one price tier of the order book of an item
+type listingTier struct {
+	Listings  int `json:"listings"`
+	UnitPrice int `json:"unit_price"`
+	Quantity  int `json:"quantity"`
+}
+
+// listing mirrors /v2/commerce/listings
+type listing struct {
+	ID    int           `json:"id"`
+	Buys  []listingTier `json:"buys"`
+	Sells []listingTier `json:"sells"`
+}
+
+// walletEntry mirrors the entries of /v2/account/wallet
+type walletEntry struct {
+	ID    int `json:"id"`
+	Value int `json:"value"`
+}
+
+// slot is a stack of items in the bank, the shared inventory or a bag; empty slots are null
+type slot struct {
+	ID      int    `json:"id"`
+	Count   int    `json:"count"`
+	Binding string `json:"binding,omitempty"`
+}
+
+// material mirrors the entries of /v2/account/materials
+type material struct {
+	ID       int `json:"id"`
+	Category int `json:"category"`
+	Count    int `json:"count"`
+}
+
+// bag mirrors the bags of /v2/characters/{name}/inventory; empty bag slots are null
+type bag struct {
+	ID        int     `json:"id"`
+	Size      int     `json:"size"`
+	Inventory []*slot `json:"inventory"`
+}
+
+// armoryItem mirrors /v2/legendaryarmory
+type armoryItem struct {
+	ID       int `json:"id"`
+	MaxCount int `json:"max_count"`
+}
+
+// achievementTier is one tier of an achievement
+type achievementTier struct {
+	Count  int `json:"count"`
+	Points int `json:"points"`
+}
+
+// achievement mirrors /v2/achievements, without bits and rewards
+type achievement struct {
+	ID          int               `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Requirement string            `json:"requirement"`
+	Type        string            `json:"type"`
+	Flags       []string          `json:"flags"`
+	Tiers       []achievementTier `json:"tiers"`
+}
+
+// achievementProgress mirrors the entries of /v2/account/achievements
+type achievementProgress struct {
+	ID      int  `json:"id"`
+	Current int  `json:"current"`
+	Max     int  `json:"max"`
+	Done    bool `json:"done"`
+}
+
+// transaction mirrors /v2/commerce/transactions
+type transaction struct {
+	ID        int64  `json:"id"`
+	ItemID    int    `json:"item_id"`
+	Price     int    `json:"price"`
+	Quantity  int    `json:"quantity"`
+	Created   string `json:"created"`
+	Purchased string `json:"purchased,omitempty"`
+}
+
+// deliveryItem is an item of /v2/commerce/delivery
+type deliveryItem struct {
+	ID    int `json:"id"`
+	Count int `json:"count"`
+}
+
+const renderURL = "https://render.guildwars2.com/file/"
+
+// Gem exchange model: buying gems costs more per gem the more coins are spent, and
+// selling gems yields less per gem the more gems are sold
+const (
+	gemBuyCoinsPerGem  = 3400  // coins per gem when buying a few gems
+	gemBuySlippage     = 20000 // copper spent per extra coin per gem
+	gemSellCoinsPerGem = 2600  // coins per gem when selling a few gems
+	gemSellSlippage    = 10    // gems sold per coin per gem lost
+)
+
+var currencies = map[int]currency{
+	1: {ID: 1, Name: "Coin", Order: 101,
+		Description: "The primary currency of Tyria. Spent at vendors throughout the world.",
+		Icon:        renderURL + "98457F504BA2FAC8457F532C4B30EDC23929ACF9/619316.png"},
+	2: {ID: 2, Name: "Karma", Order: 102,
+		Description: "Earned and spent throughout the world.",
+		Icon:        renderURL + "94953FA23D3E0D23559624015DFEA4CFAA07F0E5/155026.png"},
+	3: {ID: 3, Name: "Laurel", Order: 104,
+		Description: "Earned through the Wizard's Vault and login rewards. Spent at Laurel Merchants.",
+		Icon:        renderURL + "A1BD345AD9192C3A585BE2F6CB0617C5A797A1E2/619317.png"},
+	4: {ID: 4, Name: "Gem", Order: 103,
+		Description: "Purchased and spent via the Gem Store.",
+		Icon:        renderURL + "220061640ECA41C0577758030357221B4ECCE62C/502065.png"},
+	7: {ID: 7, Name: "Fractal Relic", Order: 120,
+		Description: "Earned in the Fractals of the Mists. Spent in the Fractals of the Mists and Lion's Arch.",
+		Icon:        renderURL + "8D6B6A6AD4B5A77FD2E6BCFE34D4D07B3E0B7E24/1465578.png"},
+	15: {ID: 15, Name: "Badge of Honor", Order: 130,
+		Description: "Earned in World vs. World. Spent at World vs. World vendors.",
+		Icon:        renderURL + "AC7C8F07ECBD6E4F3B69C6D4D5F3A0A1C61E4E5A/699327.png"},
+	23: {ID: 23, Name: "Spirit Shard", Order: 105,
+		Description: "Earned by gaining levels past 80 and from the Wizard's Vault. Spent in the Mystic Forge.",
+		Icon:        renderURL + "2B3F6B7F6B7CE1B1B79EA29C26E3C7CFD1D9A0C4/1302734.png"},
+	32: {ID: 32, Name: "Unbound Magic", Order: 160,
+		Description: "Collected in Season 3 and Path of Fire maps. Spent at magic merchants.",
+		Icon:        renderURL + "B4B1FE5D0FAA16AF8EBB29E5D2A3E7C4BE0C7C95/1465592.png"},
+	45: {ID: 45, Name: "Volatile Magic", Order: 170,
+		Description: "Collected in Season 4 and Icebrood Saga maps. Spent at magic merchants.",
+		Icon:        renderURL + "A3C9D7F6E51E1BB0A44F38B3E44F06E5CC2C9F40/1863845.png"},
+}
+
+var items = map[int]item{
+	19721: {ID: 19721, Name: "Glob of Ectoplasm", Type: "CraftingMaterial", Rarity: "Exotic", VendorValue: 64,
+		Description: "Salvage Item", ChatLink: "[&AgHRTAAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "18CE5D78317265000CF3C23ED76AB3CEE86BA60E/65941.png"},
+	19976: {ID: 19976, Name: "Mystic Coin", Type: "CraftingMaterial", Rarity: "Rare", VendorValue: 1,
+		Description: "Used to craft legendary weapons in the Mystic Forge.", ChatLink: "[&AgFQTgAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "6B9F0DF5B76D0F30DF0ED08C7DE7CE3A2B89A7E3/65577.png"},
+	19675: {ID: 19675, Name: "Mystic Clover", Type: "Trophy", Rarity: "Rare", VendorValue: 0,
+		Description: "Used in the Mystic Forge to create legendary gifts.", ChatLink: "[&AgHbTAAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"},
+		Flags:     []string{"AccountBound", "NoSell", "AccountBindOnUse"}, Restrictions: []string{},
+		Icon: renderURL + "C6C2F1BFD1A04A5B0FAB7A5A0A6C5BDF1EACC5A6/66985.png"},
+	19925: {ID: 19925, Name: "Obsidian Shard", Type: "Trophy", Rarity: "Exotic", VendorValue: 0,
+		Description: "Used in the Mystic Forge to create legendary gifts.", ChatLink: "[&AgEVTgAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"},
+		Flags:     []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "1C1ECB5C6C2C0F3BD2F5A8C3F3BF4E78B3F0A8D3/66001.png"},
+	24277: {ID: 24277, Name: "Pile of Crystalline Dust", Type: "CraftingMaterial", Rarity: "Exotic", VendorValue: 8,
+		Description: "Refine into Crystalline Dust.", ChatLink: "[&AgHVXgAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "DF6F0A5A3B7E4F70D55E9B35A1D94FDD4F2F3A0A/66653.png"},
+	46741: {ID: 46741, Name: "Bolt of Damask", Type: "CraftingMaterial", Rarity: "Ascended", VendorValue: 0,
+		Description: "Used in crafting ascended armor.", ChatLink: "[&AgGVtgAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "D2A5CB6D6C1A66C8D4DAE1D0E3E6A5B1B62B7C9B/699323.png"},
+	68063: {ID: 68063, Name: "Amalgamated Gemstone", Type: "CraftingMaterial", Rarity: "Exotic", VendorValue: 16,
+		Description: "Used in the crafting of ascended materials and legendary gifts.", ChatLink: "[&AgH/CQEA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "EB9F8B3AA2E3EEEAE3E61DD4A8D0D8CE3E7B0E4C/919341.png"},
+	19700: {ID: 19700, Name: "Mithril Ore", Type: "CraftingMaterial", Rarity: "Basic", VendorValue: 4,
+		Description: "Refine into Ingots.", ChatLink: "[&AgHsTAAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "1D2F8B9F2A8C0F1C6C3B6A0D6E5F4B1E9A3D2C7B/65720.png"},
+	24295: {ID: 24295, Name: "Vial of Powerful Blood", Type: "CraftingMaterial", Rarity: "Exotic", VendorValue: 8,
+		Description: "Refine into Vial of Powerful Blood.", ChatLink: "[&AgHHXgAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "8F9B3F3C1C4F5E2D8B0A7C6E3D9F1A2B4C5D6E7F/66971.png"},
+	24351: {ID: 24351, Name: "Vicious Claw", Type: "CraftingMaterial", Rarity: "Exotic", VendorValue: 8,
+		Description: "Used in crafting.", ChatLink: "[&AgH/XgAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "3A4B5C6D7E8F9A0B1C2D3E4F5A6B7C8D9E0F1A2B/66948.png"},
+	24358: {ID: 24358, Name: "Ancient Bone", Type: "CraftingMaterial", Rarity: "Exotic", VendorValue: 8,
+		Description: "Used in crafting.", ChatLink: "[&AgEGXwAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "5B6C7D8E9F0A1B2C3D4E5F6A7B8C9D0E1F2A3B4C/66955.png"},
+	30689: {ID: 30689, Name: "Eternity", Type: "Weapon", Rarity: "Legendary", Level: 80, VendorValue: 100000,
+		ChatLink:  "[&AgHheAAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"},
+		Flags:     []string{"HideSuffix", "NoSalvage", "NoSell", "DeleteWarning"}, Restrictions: []string{},
+		Details: map[string]any{"type": "Greatsword"},
+		Icon:    renderURL + "D8D5B6F1F2FAD3C0B7F2C6E1B2A0C5B7F2E5B0E1/456031.png"},
+	30703: {ID: 30703, Name: "Sunrise", Type: "Weapon", Rarity: "Legendary", Level: 80, VendorValue: 100000,
+		ChatLink:  "[&AgHvdwAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"},
+		Flags:     []string{"HideSuffix", "NoSalvage", "NoSell", "DeleteWarning"}, Restrictions: []string{},
+		Details: map[string]any{"type": "Greatsword"},
+		Icon:    renderURL + "0D4D8C4AD0E1A9F1B9C1E8E8B4D3E1B7A6C0F3D2/456026.png"},
+	30704: {ID: 30704, Name: "Twilight", Type: "Weapon", Rarity: "Legendary", Level: 80, VendorValue: 100000,
+		ChatLink:  "[&AgHwdwAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"},
+		Flags:     []string{"HideSuffix", "NoSalvage", "NoSell", "DeleteWarning"}, Restrictions: []string{},
+		Details: map[string]any{"type": "Greatsword"},
+		Icon:    renderURL + "D6E2E6A1F0B1C6E7F4A0D2E3C9B8A7F6E5D4C3B2/456027.png"},
+	81908: {ID: 81908, Name: "Aurora", Type: "Trinket", Rarity: "Legendary", Level: 80, VendorValue: 0,
+		ChatLink:  "[&AgH0PwEA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"},
+		Flags:     []string{"HideSuffix", "NoSalvage", "NoSell", "AccountBound"}, Restrictions: []string{},
+		Details: map[string]any{"type": "Accessory"},
+		Icon:    renderURL + "C9C8F1E0A3B2D5E4F7A6B9C8D1E0F3A2B5C4D7E6/1601380.png"},
+	29185: {ID: 29185, Name: "Dusk", Type: "Weapon", Rarity: "Exotic", Level: 80, VendorValue: 0,
+		ChatLink:  "[&AgEBcgAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"},
+		Flags:     []string{"HideSuffix"}, Restrictions: []string{},
+		Details: map[string]any{"type": "Greatsword"},
+		Icon:    renderURL + "A1B2C3D4E5F6A7B8C9D0E1F2A3B4C5D6E7F8A9B0/456015.png"},
+	19648: {ID: 19648, Name: "Gift of Twilight", Type: "Trophy", Rarity: "Legendary", VendorValue: 0,
+		Description: "Used to craft the legendary greatsword Twilight.", ChatLink: "[&AgHATAAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"},
+		Flags:     []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "B0A9F8E7D6C5B4A3F2E1D0C9B8A7F6E5D4C3B2A1/455854.png"},
+	19674: {ID: 19674, Name: "Gift of Mastery", Type: "Trophy", Rarity: "Legendary", VendorValue: 0,
+		Description: "Used to craft legendary weapons.", ChatLink: "[&AgHaTAAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"},
+		Flags:     []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "C1B0A9F8E7D6C5B4A3F2E1D0C9B8A7F6E5D4C3B2/455853.png"},
+	19626: {ID: 19626, Name: "Gift of Fortune", Type: "Trophy", Rarity: "Legendary", VendorValue: 0,
+		Description: "Used to craft legendary weapons.", ChatLink: "[&AgGqTAAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"},
+		Flags:     []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "D2C1B0A9F8E7D6C5B4A3F2E1D0C9B8A7F6E5D4C3/455851.png"},
+	19672: {ID: 19672, Name: "Gift of Might", Type: "Trophy", Rarity: "Legendary", VendorValue: 0,
+		Description: "Used to craft legendary weapons.", ChatLink: "[&AgHYTAAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"},
+		Flags:     []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "E3D2C1B0A9F8E7D6C5B4A3F2E1D0C9B8A7F6E5D4/455860.png"},
+	19673: {ID: 19673, Name: "Gift of Magic", Type: "Trophy", Rarity: "Legendary", VendorValue: 0,
+		Description: "Used to craft legendary weapons.", ChatLink: "[&AgHZTAAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"},
+		Flags:     []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "F4E3D2C1B0A9F8E7D6C5B4A3F2E1D0C9B8A7F6E5/455859.png"},
+	19677: {ID: 19677, Name: "Gift of Exploration", Type: "Trophy", Rarity: "Legendary", VendorValue: 0,
+		Description: "Awarded for world completion.", ChatLink: "[&AgHdTAAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"},
+		Flags:     []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "A5F4E3D2C1B0A9F8E7D6C5B4A3F2E1D0C9B8A7F6/455857.png"},
+	19678: {ID: 19678, Name: "Gift of Battle", Type: "Trophy", Rarity: "Legendary", VendorValue: 0,
+		Description: "Awarded by the Gift of Battle reward track.", ChatLink: "[&AgHeTAAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"},
+		Flags:     []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "B6A5F4E3D2C1B0A9F8E7D6C5B4A3F2E1D0C9B8A7/455856.png"},
+	20797: {ID: 20797, Name: "Bloodstone Shard", Type: "Trophy", Rarity: "Exotic", VendorValue: 0,
+		Description: "Used in the Mystic Forge to create legendary gifts.", ChatLink: "[&AgE9UQAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"},
+		Flags:     []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "C7B6A5F4E3D2C1B0A9F8E7D6C5B4A3F2E1D0C9B8/66917.png"},
+	24357: {ID: 24357, Name: "Vicious Fang", Type: "CraftingMaterial", Rarity: "Exotic", VendorValue: 8,
+		Description: "Used in crafting.", ChatLink: "[&AgElXwAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "D8C7B6A5F4E3D2C1B0A9F8E7D6C5B4A3F2E1D0C9/66954.png"},
+	24289: {ID: 24289, Name: "Armored Scale", Type: "CraftingMaterial", Rarity: "Exotic", VendorValue: 8,
+		Description: "Used in crafting.", ChatLink: "[&AgHhXgAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "E9D8C7B6A5F4E3D2C1B0A9F8E7D6C5B4A3F2E1D0/66964.png"},
+	24283: {ID: 24283, Name: "Powerful Venom Sac", Type: "CraftingMaterial", Rarity: "Exotic", VendorValue: 8,
+		Description: "Used in crafting.", ChatLink: "[&AgHbXgAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "F0E9D8C7B6A5F4E3D2C1B0A9F8E7D6C5B4A3F2E1/66966.png"},
+	24300: {ID: 24300, Name: "Elaborate Totem", Type: "CraftingMaterial", Rarity: "Exotic", VendorValue: 8,
+		Description: "Used in crafting.", ChatLink: "[&AgHsXgAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "A1F0E9D8C7B6A5F4E3D2C1B0A9F8E7D6C5B4A3F2/66960.png"},
+	19621: {ID: 19621, Name: "Gift of Metal", Type: "Trophy", Rarity: "Legendary", VendorValue: 0,
+		Description: "Used to craft legendary weapons.", ChatLink: "[&AgGlTAAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "A094A0485E5DBD6998CE57D550029C6361E2A82C/455855.png"},
+	19684: {ID: 19684, Name: "Mithril Ingot", Type: "CraftingMaterial", Rarity: "Basic", VendorValue: 8,
+		Description: "Used in crafting.", ChatLink: "[&AgHkTAAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "BE4C8B8FBC00EE2A4E1188D5BA156DDFBC8B22E3/219353.png"},
+	19685: {ID: 19685, Name: "Orichalcum Ingot", Type: "CraftingMaterial", Rarity: "Basic", VendorValue: 8,
+		Description: "Used in crafting.", ChatLink: "[&AgHlTAAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "A331D30A13F246066CC19EC5D4F7A61A778BD0E7/219355.png"},
+	19681: {ID: 19681, Name: "Darksteel Ingot", Type: "CraftingMaterial", Rarity: "Basic", VendorValue: 8,
+		Description: "Used in crafting.", ChatLink: "[&AgHhTAAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "29245A9C7E944424153FFE9F4FF233EF8651C955/219350.png"},
+	19686: {ID: 19686, Name: "Platinum Ingot", Type: "CraftingMaterial", Rarity: "Basic", VendorValue: 8,
+		Description: "Used in crafting.", ChatLink: "[&AgHmTAAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "A088B1BA1B91A3CAEB0BD4E8BD3CF20CD2874B4B/219354.png"},
+	24570: {ID: 24570, Name: "Superior Sigil of Blood", Type: "UpgradeComponent", Rarity: "Exotic", VendorValue: 54,
+		Description: "Double-click to apply to a weapon.", ChatLink: "[&AgH6XwAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{}, Restrictions: []string{},
+		Icon: renderURL + "10571D9F333D8CDF65612CBBC595566A1D39EC0E/220668.png"},
+	19676: {ID: 19676, Name: "Icy Runestone", Type: "Trophy", Rarity: "Rare", VendorValue: 100,
+		Description: "Used in the Mystic Forge to create legendary gifts.", ChatLink: "[&AgHcTAAA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "F151F3964B68CDAA1AEDA6C4BFB0D3354A0E06B2/455848.png"},
+	81957: {ID: 81957, Name: "Gift of Aurora", Type: "Trophy", Rarity: "Legendary", VendorValue: 0,
+		Description: "Used to craft the legendary trinket Aurora.", ChatLink: "[&AgElQAEA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "4E15836CA905EC0320AC27A5589723A57A5142D4/1601381.png"},
+	79280: {ID: 79280, Name: "Blood Ruby", Type: "CraftingMaterial", Rarity: "Exotic", VendorValue: 0,
+		Description: "Used in crafting.", ChatLink: "[&AgGwNQEA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "55E7D26546DF5AA423A993DBA12FF57C222F9AD5/1203024.png"},
+	79469: {ID: 79469, Name: "Petrified Wood", Type: "CraftingMaterial", Rarity: "Exotic", VendorValue: 0,
+		Description: "Used in crafting.", ChatLink: "[&AgFtNgEA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "132AB5B28A8C2D7919A05CAB4D7B171329F14C25/1203025.png"},
+	79899: {ID: 79899, Name: "Fresh Winterberry", Type: "CraftingMaterial", Rarity: "Exotic", VendorValue: 0,
+		Description: "Used in crafting.", ChatLink: "[&AgEbOAEA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "329BB4F6261B2ACFABC8AEDB88E4F00F3A6013D2/1203027.png"},
+	80332: {ID: 80332, Name: "Jade Shard", Type: "CraftingMaterial", Rarity: "Exotic", VendorValue: 0,
+		Description: "Used in crafting.", ChatLink: "[&AgHMOQEA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "D22F0BC13CC4660D67A7BA4BD02868B16C71F2F9/1302731.png"},
+	81127: {ID: 81127, Name: "Fire Orchid Blossom", Type: "CraftingMaterial", Rarity: "Exotic", VendorValue: 0,
+		Description: "Used in crafting.", ChatLink: "[&AgHnPAEA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "AF2258647C30968109424F997F2D0BA167AC53CC/1465480.png"},
+	81706: {ID: 81706, Name: "Orrian Pearl", Type: "CraftingMaterial", Rarity: "Exotic", VendorValue: 0,
+		Description: "Used in crafting.", ChatLink: "[&AgEqPwEA]",
+		GameTypes: []string{"Activity", "Wvw", "Dungeon", "Pve"}, Flags: []string{"AccountBound", "NoSell"}, Restrictions: []string{},
+		Icon: renderURL + "4573F8131863E47B30A4E1E0BC6786926E73F120/1601373.png"},
+}
+
+var prices = map[int]price{
+	19721: {ID: 19721, Whitelisted: false,
+		Buys: priceSide{Quantity: 412800, UnitPrice: 2412}, Sells: priceSide{Quantity: 93512, UnitPrice: 2577}},
+	19976: {ID: 19976, Whitelisted: false,
+		Buys: priceSide{Quantity: 168230, UnitPrice: 10301}, Sells: priceSide{Quantity: 27871, UnitPrice: 11499}},
+	24277: {ID: 24277, Whitelisted: false,
+		Buys: priceSide{Quantity: 251093, UnitPrice: 1103}, Sells: priceSide{Quantity: 60211, UnitPrice: 1212}},
+	68063: {ID: 68063, Whitelisted: false,
+		Buys: priceSide{Quantity: 31122, UnitPrice: 4801}, Sells: priceSide{Quantity: 9834, UnitPrice: 5350}},
+	// Spreads wide enough to flip after fees; Ancient Bone barely trades
+	19700: {ID: 19700, Whitelisted: false,
+		Buys: priceSide{Quantity: 2000000, UnitPrice: 100}, Sells: priceSide{Quantity: 900000, UnitPrice: 130}},
+	24295: {ID: 24295, Whitelisted: false,
+		Buys: priceSide{Quantity: 150000, UnitPrice: 2000}, Sells: priceSide{Quantity: 80000, UnitPrice: 2700}},
+	24351: {ID: 24351, Whitelisted: false,
+		Buys: priceSide{Quantity: 90000, UnitPrice: 1500}, Sells: priceSide{Quantity: 40000, UnitPrice: 2100}},
+	24358: {ID: 24358, Whitelisted: false,
+		Buys: priceSide{Quantity: 30, UnitPrice: 500}, Sells: priceSide{Quantity: 50, UnitPrice: 900}},
+	// Legendary crafting materials, with spreads too narrow to flip
+	24357: {ID: 24357, Whitelisted: false,
+		Buys: priceSide{Quantity: 120000, UnitPrice: 2000}, Sells: priceSide{Quantity: 45000, UnitPrice: 2300}},
+	24289: {ID: 24289, Whitelisted: false,
+		Buys: priceSide{Quantity: 210000, UnitPrice: 1000}, Sells: priceSide{Quantity: 70000, UnitPrice: 1150}},
+	24283: {ID: 24283, Whitelisted: false,
+		Buys: priceSide{Quantity: 95000, UnitPrice: 1200}, Sells: priceSide{Quantity: 38000, UnitPrice: 1380}},
+	24300: {ID: 24300, Whitelisted: false,
+		Buys: priceSide{Quantity: 88000, UnitPrice: 1900}, Sells: priceSide{Quantity: 31000, UnitPrice: 2180}},
+	29185: {ID: 29185, Whitelisted: false,
+		Buys: priceSide{Quantity: 14, UnitPrice: 14000000}, Sells: priceSide{Quantity: 9, UnitPrice: 15200000}},
+	// Gift of Twilight materials, with spreads too narrow to flip
+	19684: {ID: 19684, Whitelisted: false,
+		Buys: priceSide{Quantity: 320000, UnitPrice: 60}, Sells: priceSide{Quantity: 150000, UnitPrice: 68}},
+	19685: {ID: 19685, Whitelisted: false,
+		Buys: priceSide{Quantity: 180000, UnitPrice: 110}, Sells: priceSide{Quantity: 90000, UnitPrice: 125}},
+	19681: {ID: 19681, Whitelisted: false,
+		Buys: priceSide{Quantity: 140000, UnitPrice: 85}, Sells: priceSide{Quantity: 70000, UnitPrice: 96}},
+	19686: {ID: 19686, Whitelisted: false,
+		Buys: priceSide{Quantity: 260000, UnitPrice: 40}, Sells: priceSide{Quantity: 120000, UnitPrice: 45}},
+	24570: {ID: 24570, Whitelisted: false,
+		Buys: priceSide{Quantity: 2100, UnitPrice: 9000}, Sells: priceSide{Quantity: 800, UnitPrice: 10200}},
+}
+
+var listings = map[int]listing{
+	19721: {ID: 19721,
+		Buys:  []listingTier{{Listings: 12, UnitPrice: 2412, Quantity: 2950}, {Listings: 40, UnitPrice: 2411, Quantity: 11820}},
+		Sells: []listingTier{{Listings: 3, UnitPrice: 2577, Quantity: 612}, {Listings: 9, UnitPrice: 2580, Quantity: 1804}}},
+	19976: {ID: 19976,
+		Buys:  []listingTier{{Listings: 5, UnitPrice: 10301, Quantity: 1250}, {Listings: 18, UnitPrice: 10300, Quantity: 4312}},
+		Sells: []listingTier{{Listings: 2, UnitPrice: 11499, Quantity: 250}, {Listings: 7, UnitPrice: 11500, Quantity: 985}}},
+	24295: {ID: 24295,
+		Buys:  []listingTier{{Listings: 8, UnitPrice: 2000, Quantity: 1500}, {Listings: 30, UnitPrice: 1999, Quantity: 9000}},
+		Sells: []listingTier{{Listings: 1, UnitPrice: 2700, Quantity: 40}, {Listings: 6, UnitPrice: 2705, Quantity: 1200}}},
+	24351: {ID: 24351,
+		Buys:  []listingTier{{Listings: 3, UnitPrice: 1500, Quantity: 600}, {Listings: 12, UnitPrice: 1498, Quantity: 4100}},
+		Sells: []listingTier{{Listings: 4, UnitPrice: 2100, Quantity: 320}, {Listings: 9, UnitPrice: 2110, Quantity: 2500}}},
+}
+
+// transactions are the trading post orders of the mock account by state and type, newest first
+var transactions = map[string]map[string][]transaction{
+	"current": {
+		"buys": {
+			{ID: 7001, ItemID: 19976, Price: 10250, Quantity: 25, Created: "2026-10-15T18:20:00+00:00"},
+		},
+		"sells": {
+			{ID: 7002, ItemID: 19721, Price: 2650, Quantity: 100, Created: "2026-10-16T09:45:00+00:00"},
+		},
+	},
+	"history": {
+		"buys": {
+			{ID: 5003, ItemID: 19976, Price: 10300, Quantity: 10,
+				Created: "2026-10-02T20:10:00+00:00", Purchased: "2026-10-02T21:00:00+00:00"},
+			{ID: 5002, ItemID: 24277, Price: 1100, Quantity: 250,
+				Created: "2026-09-10T08:00:00+00:00", Purchased: "2026-09-10T08:30:00+00:00"},
+			{ID: 5001, ItemID: 19721, Price: 2400, Quantity: 250,
+				Created: "2026-09-01T10:00:00+00:00", Purchased: "2026-09-01T10:05:00+00:00"},
+		},
+		"sells": {
+			{ID: 6003, ItemID: 68063, Price: 5400, Quantity: 3,
+				Created: "2026-10-04T12:00:00+00:00", Purchased: "2026-10-05T07:15:00+00:00"},
+			{ID: 6002, ItemID: 24277, Price: 1210, Quantity: 100,
+				Created: "2026-09-19T16:00:00+00:00", Purchased: "2026-09-20T11:40:00+00:00"},
+			{ID: 6001, ItemID: 19721, Price: 2600, Quantity: 250,
+				Created: "2026-09-04T19:30:00+00:00", Purchased: "2026-09-05T14:00:00+00:00"},
+		},
+	},
+}
+
+// delivery is the trading post delivery box of the mock account
+var delivery = map[string]any{
+	"coins": 1234567,
+	"items": []deliveryItem{{ID: 19721, Count: 250}, {ID: 68063, Count: 1}},
+}
+
+// wallet is the wallet of the mock account, including currencies with a zero balance
+var wallet = []walletEntry{
+	{ID: 1, Value: 12345678},
+	{ID: 2, Value: 2841530},
+	{ID: 3, Value: 412},
+	{ID: 4, Value: 0},
+	{ID: 7, Value: 18740},
+	{ID: 15, Value: 0},
+	{ID: 23, Value: 1250},
+	{ID: 32, Value: 0},
+	{ID: 45, Value: 98420},
+}
+
+// legendaryArmory holds the items of the legendary armory with the number that can be unlocked
+var legendaryArmory = map[int]armoryItem{
+	30689: {ID: 30689, MaxCount: 2},
+	30703: {ID: 30703, MaxCount: 2},
+	30704: {ID: 30704, MaxCount: 2},
+	81908: {ID: 81908, MaxCount: 2},
+}
+
+// accountLegendaryArmory is the legendary armory of the mock account
+var accountLegendaryArmory = []slot{{ID: 30689, Count: 1}, {ID: 81908, Count: 1}}
+
+// achievements holds the achievements the mock serves
+var achievements = map[int]achievement{
+	1: {ID: 1, Name: "Centaur Slayer", Requirement: "Kill   centaurs.", Type: "Default",
+		Flags: []string{"CategoryDisplay"},
+		Tiers: []achievementTier{{Count: 100, Points: 1}, {Count: 500, Points: 5}}},
+	2: {ID: 2, Name: "Ghost Slayer", Requirement: "Kill   ghosts.", Type: "Default",
+		Flags: []string{"CategoryDisplay"},
+		Tiers: []achievementTier{{Count: 100, Points: 1}, {Count: 500, Points: 5}}},
+	3213: {ID: 3213, Name: "Aurora: Awakening", Requirement: "Complete the Aurora: Awakening collection.",
+		Type: "ItemSet", Flags: []string{"CategoryDisplay"}, Tiers: []achievementTier{{Count: 12, Points: 5}}},
+	3292: {ID: 3292, Name: "Aurora: Empowering", Requirement: "Complete the Aurora: Empowering collection.",
+		Type: "ItemSet", Flags: []string{"CategoryDisplay"}, Tiers: []achievementTier{{Count: 8, Points: 5}}},
+}
+
+// accountAchievements is the achievement progress of the mock account
+var accountAchievements = []achievementProgress{
+	{ID: 1, Current: 500, Max: 500, Done: true},
+	{ID: 2, Current: 120, Max: 500},
+	{ID: 3213, Current: 12, Max: 12, Done: true},
+	{ID: 3292, Current: 5, Max: 8},
+}
+
+// bank is the bank of the mock account
+var bank = []*slot{
+	{ID: 19721, Count: 50},
+	nil,
+	{ID: 19976, Count: 40},
+	{ID: 30689, Count: 1, Binding: "Account"},
+	nil,
+}
+
+// materials is the material storage of the mock account, including empty entries as on the real API
+var materials = []material{
+	{ID: 19721, Category: 6, Count: 120},
+	{ID: 24277, Category: 6, Count: 800},
+	{ID: 68063, Category: 6, Count: 12},
+	{ID: 19700, Category: 5, Count: 0},
+}
+
+// sharedInventory is the shared inventory of the mock account
+var sharedInventory = []*slot{{ID: 68063, Count: 2}, nil}
+
+// characterBags are the bags of the mock characters by name; empty bag slots are null
+var characterBags = map[string][]*bag{
+	"Mock Warrior": {
+		{ID: 8932, Size: 4, Inventory: []*slot{{ID: 19721, Count: 10}, nil, {ID: 19675, Count: 5, Binding: "Account"}, nil}},
+		nil,
+	},
+	"Mock Mesmer": {
+		{ID: 8932, Size: 2, Inventory: []*slot{{ID: 24295, Count: 30}, nil}},
+	},
+}
+
+// handleTokenInfo serves /v2/tokeninfo
+func handleTokenInfo(w http.ResponseWriter, _ *http.Request, key Key) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":          key.Value,
+		"name":        key.Name,
+		"permissions": key.Permissions,
+		"type":        "APIKey",
+	})
+}
+
+// handleAccount serves /v2/account
+func handleAccount(w http.ResponseWriter, _ *http.Request, _ Key) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":      "A1B2C3D4-0000-4000-8000-000000000001",
+		"name":    "Mock Account.1234",
+		"age":     15724800,
+		"world":   2003,
+		"created": "2015-08-28T00:00:00Z",
+		"access":  []string{"GuildWars2", "HeartOfThorns", "PathOfFire", "EndOfDragons", "SecretsOfTheObscure"},
+	})
+}
+
+// handleBank serves /v2/account/bank
+func handleBank(w http.ResponseWriter, _ *http.Request, _ Key) {
+	writeJSON(w, http.StatusOK, bank)
+}
+
+// handleMaterials serves /v2/account/materials
+func handleMaterials(w http.ResponseWriter, _ *http.Request, _ Key) {
+	writeJSON(w, http.StatusOK, materials)
+}
+
+// handleSharedInventory serves /v2/account/inventory
+func handleSharedInventory(w http.ResponseWriter, _ *http.Request, _ Key) {
+	writeJSON(w, http.StatusOK, sharedInventory)
+}
+
+// handleCharacters serves /v2/characters
+func handleCharacters(w http.ResponseWriter, _ *http.Request, _ Key) {
+	names := make([]string, 0, len(characterBags))
+	for name := range characterBags {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	writeJSON(w, http.StatusOK, names)
+}
+
+// handleCharacterInventory serves /v2/characters/{name}/inventory
+func handleCharacterInventory(w http.ResponseWriter, r *http.Request, _ Key) {
+	bags, ok := characterBags[r.PathValue("name")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "no such character")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"bags": bags})
+}
+
+// handleAccountLegendaryArmory serves /v2/account/legendaryarmory
+func handleAccountLegendaryArmory(w http.ResponseWriter, _ *http.Request, _ Key) {
+	writeJSON(w, http.StatusOK, accountLegendaryArmory)
+}
+
+// handleAccountAchievements serves /v2/account/achievements
+func handleAccountAchievements(w http.ResponseWriter, _ *http.Request, _ Key) {
+	writeJSON(w, http.StatusOK, accountAchievements)
+}
+
+// handleWallet serves /v2/account/wallet
+func handleWallet(w http.ResponseWriter, _ *http.Request, _ Key) {
+	writeJSON(w, http.StatusOK, wallet)
+}
+
+// handleExchange serves /v2/commerce/exchange/coins and /v2/commerce/exchange/gems,
+// quoting the gems received for quantity copper, or the copper received for quantity gems
+func handleExchange(from string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		quantity, err := strconv.Atoi(r.URL.Query().Get("quantity"))
+		if err != nil || quantity <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid quantity")
+			return
+		}
+
+		var coinsPerGem, received int
+		if from == "coins" {
+			coinsPerGem = gemBuyCoinsPerGem + quantity/gemBuySlippage
+			received = quantity / coinsPerGem
+		} else {
+			coinsPerGem = max(gemSellCoinsPerGem-quantity/gemSellSlippage, 1)
+			received = quantity * coinsPerGem
+		}
+
+		if received == 0 {
+			writeError(w, http.StatusBadRequest, "not enough coins")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]int{"coins_per_gem": coinsPerGem, "quantity": received})
+	})
+}
+
+// handleTransactions serves /v2/commerce/transactions/{current,history}/{buys,sells}
+func handleTransactions(w http.ResponseWriter, r *http.Request, _ Key) {
+	records, ok := transactions[r.PathValue("state")][r.PathValue("type")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	servePage(w, r, records)
+}
+
+// handleDelivery serves /v2/commerce/delivery
+func handleDelivery(w http.ResponseWriter, _ *http.Request, _ Key) {
+	writeJSON(w, http.StatusOK, delivery)
+}
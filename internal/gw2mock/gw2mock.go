@@ -0,0 +1,427 @@
+// Package gw2mock implements a local mock of a subset of the Guild Wars 2 API v2, so the
+// server and its end-to-end tests can run fully offline. It serves realistic data for
+// currencies, items, achievements, the legendary armory, unlocks such as skins and dyes,
+// trading post prices, listings and transactions, the gem exchange, token info, the account
+// wallet, bank, materials, inventories, characters, legendary armory, achievement progress
+// and unlocks, accepts fake API keys with varying scopes, and can inject latency, rate
+// limiting and responses that do not match the documented schemas.
+package gw2mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// maxIDs is the number of IDs bulk endpoints accept at once, as on the real API
+	maxIDs = 200
+
+	// defaultPageSize and maxPageSize bound the page_size of paginated endpoints
+	defaultPageSize = 50
+	maxPageSize     = 200
+
+	// allEndpoints enables schema errors on every endpoint
+	allEndpoints = "all"
+)
+
+// Server is an http.Handler serving the mock API under /v2
+type Server struct {
+	mux            *http.ServeMux
+	keys           map[string]Key
+	latency        time.Duration
+	rateLimitEvery int64
+	schemaErrors   map[string]bool
+	requests       atomic.Int64
+}
+
+// Option configures optional Server settings
+type Option func(*Server)
+
+// WithLatency delays every response, e.g. to exercise timeouts and cancellation
+func WithLatency(latency time.Duration) Option {
+	return func(s *Server) {
+		s.latency = latency
+	}
+}
+
+// WithRateLimitEvery answers every nth request with 429 Too Many Requests. Zero disables it.
+func WithRateLimitEvery(n int) Option {
+	return func(s *Server) {
+		s.rateLimitEvery = int64(n)
+	}
+}
+
+// WithSchemaErrors makes the given endpoints, e.g. "/v2/account/wallet", answer with
+// numbers encoded as strings, which do not match the documented schemas. "all" selects
+// every endpoint.
+func WithSchemaErrors(endpoints ...string) Option {
+	return func(s *Server) {
+		for _, endpoint := range endpoints {
+			s.schemaErrors[strings.TrimSuffix(endpoint, "/")] = true
+		}
+	}
+}
+
+// New creates a mock GW2 API server
+func New(opts ...Option) *Server {
+	s := &Server{
+		mux:          http.NewServeMux(),
+		keys:         make(map[string]Key),
+		schemaErrors: make(map[string]bool),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	for _, key := range Keys() {
+		s.keys[key.Value] = key
+	}
+
+	s.mux.Handle("GET /v2/currencies", bulk(currencies, true))
+	s.mux.Handle("GET /v2/currencies/{id}", single(currencies))
+	s.mux.Handle("GET /v2/items", bulk(items, false))
+	s.mux.Handle("GET /v2/items/{id}", single(items))
+	s.mux.Handle("GET /v2/achievements", bulk(achievements, false))
+	s.mux.Handle("GET /v2/achievements/{id}", single(achievements))
+	s.mux.Handle("GET /v2/legendaryarmory", bulk(legendaryArmory, true))
+	s.mux.Handle("GET /v2/legendaryarmory/{id}", single(legendaryArmory))
+	s.mux.Handle("GET /v2/commerce/prices", bulk(prices, false))
+	s.mux.Handle("GET /v2/commerce/prices/{id}", single(prices))
+	s.mux.Handle("GET /v2/commerce/listings", bulk(listings, false))
+	s.mux.Handle("GET /v2/commerce/listings/{id}", single(listings))
+	s.mux.Handle("GET /v2/commerce/exchange/coins", handleExchange("coins"))
+	s.mux.Handle("GET /v2/commerce/exchange/gems", handleExchange("gems"))
+	s.mux.Handle("GET /v2/tokeninfo", s.authenticated(nil, handleTokenInfo))
+	s.mux.Handle("GET /v2/account", s.authenticated([]string{ScopeAccount}, handleAccount))
+	s.mux.Handle("GET /v2/account/wallet", s.authenticated([]string{ScopeAccount, ScopeWallet}, handleWallet))
+	s.mux.Handle("GET /v2/account/bank", s.authenticated([]string{ScopeAccount, ScopeInventories}, handleBank))
+	s.mux.Handle("GET /v2/account/materials", s.authenticated([]string{ScopeAccount, ScopeInventories}, handleMaterials))
+	s.mux.Handle("GET /v2/account/inventory",
+		s.authenticated([]string{ScopeAccount, ScopeInventories}, handleSharedInventory))
+	s.mux.Handle("GET /v2/account/legendaryarmory",
+		s.authenticated([]string{ScopeAccount, ScopeInventories, ScopeUnlocks}, handleAccountLegendaryArmory))
+	s.mux.Handle("GET /v2/account/achievements",
+		s.authenticated([]string{ScopeAccount, ScopeProgression}, handleAccountAchievements))
+	s.mux.Handle("GET /v2/characters", s.authenticated([]string{ScopeAccount, ScopeCharacters}, handleCharacters))
+	s.mux.Handle("GET /v2/characters/{name}/inventory",
+		s.authenticated([]string{ScopeAccount, ScopeCharacters, ScopeInventories}, handleCharacterInventory))
+	s.mux.Handle("GET /v2/commerce/transactions/{state}/{type}",
+		s.authenticated([]string{ScopeAccount, ScopeTradingPost}, handleTransactions))
+	s.mux.Handle("GET /v2/commerce/delivery", s.authenticated([]string{ScopeAccount, ScopeTradingPost}, handleDelivery))
+	for path, records := range unlocks {
+		// Like the real API, /v2/skins has too many records for ids=all
+		s.mux.Handle("GET /v2/"+path, bulk(records, path != "skins"))
+		s.mux.Handle("GET /v2/"+path+"/{id}", single(records))
+	}
+	for path, records := range namedUnlocks {
+		s.mux.Handle("GET /v2/"+path, bulk(records, true))
+		s.mux.Handle("GET /v2/"+path+"/{id}", single(records))
+	}
+	for path, owned := range accountUnlocks {
+		s.mux.Handle("GET /v2/account/"+path, s.authenticated([]string{ScopeAccount, ScopeUnlocks},
+			handleAccountUnlocks(owned)))
+	}
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		writeError(w, http.StatusNotFound, "not found")
+	})
+
+	return s
+}
+
+// Requests returns the number of requests served so far
+func (s *Server) Requests() int {
+	return int(s.requests.Load())
+}
+
+// ServeHTTP implements http.Handler, applying latency, rate limiting and schema errors
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	count := s.requests.Add(1)
+
+	if s.latency > 0 {
+		select {
+		case <-time.After(s.latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if s.rateLimitEvery > 0 && count%s.rateLimitEvery == 0 {
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusTooManyRequests, "too many requests")
+		return
+	}
+
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		w.Header().Set("Content-Language", lang)
+	}
+
+	if s.schemaErrors[allEndpoints] || s.schemaErrors[endpoint(r.URL.Path)] {
+		s.mux.ServeHTTP(&schemaBreaker{ResponseWriter: w}, r)
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// endpoint returns the endpoint of a path, without the ID of single record paths
+func endpoint(path string) string {
+	path = strings.TrimSuffix(path, "/")
+	if i := strings.LastIndexByte(path, '/'); i >= 0 {
+		if _, err := strconv.Atoi(path[i+1:]); err == nil {
+			return path[:i]
+		}
+	}
+	return path
+}
+
+// authenticated wraps a handler requiring a valid API key with the given scopes, sent as
+// bearer token or access_token parameter
+func (s *Server) authenticated(scopes []string, handler func(http.ResponseWriter, *http.Request, Key)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			token = r.URL.Query().Get("access_token")
+		}
+
+		key, found := s.keys[token]
+		if !found {
+			writeError(w, http.StatusUnauthorized, "Invalid access token")
+			return
+		}
+
+		for _, scope := range scopes {
+			if !key.HasScope(scope) {
+				writeError(w, http.StatusForbidden, "requires scope "+scope)
+				return
+			}
+		}
+
+		handler(w, r, key)
+	})
+}
+
+// recordID is the type of record IDs: numbers for most endpoints, names for a few such as /v2/emotes
+type recordID interface {
+	int | string
+}
+
+// parseID parses the ID of a /{id} path or of an ids parameter
+func parseID[K recordID](raw string) (K, error) {
+	var id K
+	switch p := any(&id).(type) {
+	case *int:
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return id, err
+		}
+		*p = n
+	case *string:
+		*p = raw
+	}
+	return id, nil
+}
+
+// bulk serves a bulk endpoint: the list of IDs without parameters, or the records
+// selected with ids (or id), partially with 206 when some are unknown
+func bulk[K recordID, T any](records map[K]T, allowAll bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		if id := query.Get("id"); id != "" && !query.Has("ids") {
+			serveRecord(w, records, id)
+			return
+		}
+
+		if !query.Has("ids") {
+			ids := sortedIDs(records)
+			setResultHeaders(w, len(ids), len(ids))
+			writeJSON(w, http.StatusOK, ids)
+			return
+		}
+
+		var ids []K
+		switch param := query.Get("ids"); {
+		case param == "all" && allowAll:
+			ids = sortedIDs(records)
+		case param == "all":
+			writeError(w, http.StatusBadRequest, "all ids not supported for this endpoint")
+			return
+		default:
+			for _, part := range strings.Split(param, ",") {
+				id, err := parseID[K](part)
+				if err != nil {
+					writeError(w, http.StatusBadRequest, "invalid id list")
+					return
+				}
+				ids = append(ids, id)
+			}
+			if len(ids) > maxIDs {
+				writeError(w, http.StatusBadRequest,
+					fmt.Sprintf("id list too long; this endpoint is limited to %d ids at once", maxIDs))
+				return
+			}
+		}
+
+		found := make([]T, 0, len(ids))
+		for _, id := range ids {
+			if record, ok := records[id]; ok {
+				found = append(found, record)
+			}
+		}
+
+		status := http.StatusOK
+		switch {
+		case len(found) == 0:
+			writeError(w, http.StatusNotFound, "all ids provided are invalid")
+			return
+		case len(found) < len(ids):
+			status = http.StatusPartialContent
+		}
+
+		setResultHeaders(w, len(found), len(records))
+		writeJSON(w, status, found)
+	})
+}
+
+// single serves the record of a /{id} path
+func single[K recordID, T any](records map[K]T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveRecord(w, records, r.PathValue("id"))
+	})
+}
+
+// servePage writes one page of a paginated endpoint selected with page and page_size,
+// with the page headers of the real API
+func servePage[T any](w http.ResponseWriter, r *http.Request, records []T) {
+	query := r.URL.Query()
+
+	pageSize := defaultPageSize
+	if query.Has("page_size") {
+		size, err := strconv.Atoi(query.Get("page_size"))
+		if err != nil || size < 1 || size > maxPageSize {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid page_size. Use values 1 - %d.", maxPageSize))
+			return
+		}
+		pageSize = size
+	}
+
+	pages := (len(records) + pageSize - 1) / pageSize
+	page, err := strconv.Atoi(query.Get("page"))
+	if query.Has("page") && (err != nil || page < 0 || page >= max(pages, 1)) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("page out of range. Use page values 0 - %d.", max(pages-1, 0)))
+		return
+	}
+
+	start := page * pageSize
+	found := records[start:min(start+pageSize, len(records))]
+
+	w.Header().Set("X-Page-Size", strconv.Itoa(pageSize))
+	w.Header().Set("X-Page-Total", strconv.Itoa(pages))
+	setResultHeaders(w, len(found), len(records))
+	writeJSON(w, http.StatusOK, found)
+}
+
+// serveRecord writes a single record, or a no such id error
+func serveRecord[K recordID, T any](w http.ResponseWriter, records map[K]T, rawID string) {
+	id, err := parseID[K](rawID)
+	record, ok := records[id]
+	if err != nil || !ok {
+		writeError(w, http.StatusNotFound, "no such id")
+		return
+	}
+	writeJSON(w, http.StatusOK, record)
+}
+
+// sortedIDs returns the IDs of records in ascending order
+func sortedIDs[K recordID, T any](records map[K]T) []K {
+	ids := make([]K, 0, len(records))
+	for id := range records {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// setResultHeaders sets the result count headers of bulk endpoints
+func setResultHeaders(w http.ResponseWriter, count, total int) {
+	w.Header().Set("X-Result-Count", strconv.Itoa(count))
+	w.Header().Set("X-Result-Total", strconv.Itoa(total))
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+// writeError writes an error in the format of the real API, e.g. {"text":"no such id"}
+func writeError(w http.ResponseWriter, status int, text string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	data, _ := json.Marshal(map[string]string{"text": text})
+	_, _ = w.Write(data)
+}
+
+// schemaBreaker rewrites successful JSON responses with every number encoded as a string
+type schemaBreaker struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status until the body is rewritten
+func (b *schemaBreaker) WriteHeader(status int) {
+	b.status = status
+}
+
+// Write rewrites and writes the body, reporting the length of the original body as written
+func (b *schemaBreaker) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+
+	data := p
+	var value any
+	decoder := json.NewDecoder(strings.NewReader(string(p)))
+	decoder.UseNumber()
+	if b.status < http.StatusBadRequest && decoder.Decode(&value) == nil {
+		if broken, err := json.Marshal(numbersToStrings(value)); err == nil {
+			data = broken
+		}
+	}
+
+	b.ResponseWriter.WriteHeader(b.status)
+	if _, err := b.ResponseWriter.Write(data); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// numbersToStrings replaces every number in a decoded JSON value with its string form
+func numbersToStrings(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		return v.String()
+	case []any:
+		for i := range v {
+			v[i] = numbersToStrings(v[i])
+		}
+	case map[string]any:
+		for key := range v {
+			v[key] = numbersToStrings(v[key])
+		}
+	}
+	return value
+}
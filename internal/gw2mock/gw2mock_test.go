@@ -0,0 +1,222 @@
+package gw2mock
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServer_Endpoints(t *testing.T) {
+	mock := httptest.NewServer(New())
+	defer mock.Close()
+
+	tests := []struct {
+		name       string
+		path       string
+		key        string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "currency IDs", path: "/v2/currencies", wantStatus: http.StatusOK, wantBody: "[1,2,3,4,7,"},
+		{name: "all currencies", path: "/v2/currencies?ids=all", wantStatus: http.StatusOK, wantBody: `"name":"Coin"`},
+		{name: "some currencies", path: "/v2/currencies?ids=1,4", wantStatus: http.StatusOK, wantBody: `"name":"Gem"`},
+		{name: "partial ids", path: "/v2/currencies?ids=1,9999", wantStatus: http.StatusPartialContent},
+		{name: "unknown ids", path: "/v2/currencies?ids=9999", wantStatus: http.StatusNotFound,
+			wantBody: "all ids provided are invalid"},
+		{name: "single currency", path: "/v2/currencies/2", wantStatus: http.StatusOK, wantBody: `"name":"Karma"`},
+		{name: "id parameter", path: "/v2/items?id=19976", wantStatus: http.StatusOK, wantBody: `"name":"Mystic Coin"`},
+		{name: "unknown item", path: "/v2/items/1", wantStatus: http.StatusNotFound, wantBody: "no such id"},
+		{name: "items do not support all", path: "/v2/items?ids=all", wantStatus: http.StatusBadRequest},
+		{name: "too many ids", path: "/v2/items?ids=" + strings.Repeat("1,", 200) + "1",
+			wantStatus: http.StatusBadRequest, wantBody: "limited to 200 ids"},
+		{name: "prices", path: "/v2/commerce/prices?ids=19721,19976", wantStatus: http.StatusOK,
+			wantBody: `"unit_price":10301`},
+		{name: "listings", path: "/v2/commerce/listings/19721", wantStatus: http.StatusOK, wantBody: `"listings":12`},
+		{name: "buy gems", path: "/v2/commerce/exchange/coins?quantity=1000000", wantStatus: http.StatusOK,
+			wantBody: `{"coins_per_gem":3450,"quantity":289}`},
+		{name: "sell gems", path: "/v2/commerce/exchange/gems?quantity=400", wantStatus: http.StatusOK,
+			wantBody: `{"coins_per_gem":2560,"quantity":1024000}`},
+		{name: "not enough coins", path: "/v2/commerce/exchange/coins?quantity=100", wantStatus: http.StatusBadRequest,
+			wantBody: "not enough coins"},
+		{name: "missing quantity", path: "/v2/commerce/exchange/gems", wantStatus: http.StatusBadRequest},
+		{name: "tokeninfo", path: "/v2/tokeninfo", key: KeyWallet, wantStatus: http.StatusOK,
+			wantBody: `"permissions":["account","wallet"]`},
+		{name: "access_token parameter", path: "/v2/tokeninfo?access_token=" + KeyAccount, wantStatus: http.StatusOK},
+		{name: "missing key", path: "/v2/account/wallet", wantStatus: http.StatusUnauthorized,
+			wantBody: "Invalid access token"},
+		{name: "unknown key", path: "/v2/account/wallet", key: "NOT-A-KEY", wantStatus: http.StatusUnauthorized},
+		{name: "missing scope", path: "/v2/account/wallet", key: KeyAccount, wantStatus: http.StatusForbidden,
+			wantBody: "requires scope wallet"},
+		{name: "wallet", path: "/v2/account/wallet", key: KeyWallet, wantStatus: http.StatusOK,
+			wantBody: `{"id":1,"value":12345678}`},
+		{name: "bank", path: "/v2/account/bank", key: KeyFull, wantStatus: http.StatusOK,
+			wantBody: `[{"id":19721,"count":50},null,`},
+		{name: "materials without scope", path: "/v2/account/materials", key: KeyWallet,
+			wantStatus: http.StatusForbidden, wantBody: "requires scope inventories"},
+		{name: "characters", path: "/v2/characters", key: KeyFull, wantStatus: http.StatusOK,
+			wantBody: `["Mock Mesmer","Mock Warrior"]`},
+		{name: "character inventory", path: "/v2/characters/Mock%20Mesmer/inventory", key: KeyFull,
+			wantStatus: http.StatusOK, wantBody: `"inventory":[{"id":24295,"count":30},null]`},
+		{name: "unknown character", path: "/v2/characters/Nobody/inventory", key: KeyFull,
+			wantStatus: http.StatusNotFound, wantBody: "no such character"},
+		{name: "account", path: "/v2/account", key: KeyTradingPost, wantStatus: http.StatusOK,
+			wantBody: `"name":"Mock Account.1234"`},
+		{name: "transactions", path: "/v2/commerce/transactions/history/sells", key: KeyTradingPost,
+			wantStatus: http.StatusOK, wantBody: `{"id":6003,"item_id":68063,"price":5400,"quantity":3,`},
+		{name: "transactions page", path: "/v2/commerce/transactions/history/sells?page=1&page_size=2",
+			key: KeyTradingPost, wantStatus: http.StatusOK, wantBody: `[{"id":6001,`},
+		{name: "transactions page out of range", path: "/v2/commerce/transactions/current/buys?page=1",
+			key: KeyTradingPost, wantStatus: http.StatusBadRequest, wantBody: "page out of range. Use page values 0 - 0."},
+		{name: "transactions without scope", path: "/v2/commerce/transactions/current/buys", key: KeyWallet,
+			wantStatus: http.StatusForbidden, wantBody: "requires scope tradingpost"},
+		{name: "unknown transactions", path: "/v2/commerce/transactions/future/buys", key: KeyFull,
+			wantStatus: http.StatusNotFound},
+		{name: "delivery", path: "/v2/commerce/delivery", key: KeyFull, wantStatus: http.StatusOK,
+			wantBody: `"coins":1234567`},
+		{name: "legendary armory", path: "/v2/legendaryarmory?ids=all", wantStatus: http.StatusOK,
+			wantBody: `{"id":30689,"max_count":2}`},
+		{name: "account legendary armory", path: "/v2/account/legendaryarmory", key: KeyFull,
+			wantStatus: http.StatusOK, wantBody: `[{"id":30689,"count":1},{"id":81908,"count":1}]`},
+		{name: "legendary armory without scope", path: "/v2/account/legendaryarmory", key: KeyWallet,
+			wantStatus: http.StatusForbidden, wantBody: "requires scope inventories"},
+		{name: "achievements", path: "/v2/achievements?ids=1,2", wantStatus: http.StatusOK,
+			wantBody: `"name":"Centaur Slayer"`},
+		{name: "account achievements", path: "/v2/account/achievements", key: KeyFull, wantStatus: http.StatusOK,
+			wantBody: `{"id":2,"current":120,"max":500,"done":false}`},
+		{name: "achievements without scope", path: "/v2/account/achievements", key: KeyTradingPost,
+			wantStatus: http.StatusForbidden, wantBody: "requires scope progression"},
+		{name: "skins do not support all", path: "/v2/skins?ids=all", wantStatus: http.StatusBadRequest},
+		{name: "colors", path: "/v2/colors?ids=all", wantStatus: http.StatusOK,
+			wantBody: `"categories":["Gray","Metal","Rare"]`},
+		{name: "mount type IDs", path: "/v2/mounts/types", wantStatus: http.StatusOK,
+			wantBody: `["griffon","raptor","skimmer","springer"]`},
+		{name: "emotes by name", path: "/v2/emotes?ids=shiver,dance", wantStatus: http.StatusPartialContent,
+			wantBody: `"commands":["/shiver","/shiverplus"]`},
+		{name: "single mount type", path: "/v2/mounts/types/raptor", wantStatus: http.StatusOK,
+			wantBody: `"name":"Raptor"`},
+		{name: "account finishers", path: "/v2/account/finishers", key: KeyFull, wantStatus: http.StatusOK,
+			wantBody: `{"id":3,"permanent":false,"quantity":5}`},
+		{name: "account dyes without scope", path: "/v2/account/dyes", key: KeyWallet,
+			wantStatus: http.StatusForbidden, wantBody: "requires scope unlocks"},
+		{name: "unknown endpoint", path: "/v2/guild/upgrades", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := get(t, mock.URL+tt.path, tt.key)
+			if status != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, status, body)
+			}
+			if !strings.Contains(body, tt.wantBody) {
+				t.Errorf("Expected body to contain %q, got %s", tt.wantBody, body)
+			}
+		})
+	}
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	mock := httptest.NewServer(New(WithRateLimitEvery(3)))
+	defer mock.Close()
+
+	var statuses []int
+	for range 6 {
+		status, _ := get(t, mock.URL+"/v2/currencies", "")
+		statuses = append(statuses, status)
+	}
+
+	expected := []int{200, 200, 429, 200, 200, 429}
+	for i, status := range statuses {
+		if status != expected[i] {
+			t.Errorf("Request %d: expected status %d, got %d", i+1, expected[i], status)
+		}
+	}
+}
+
+func TestServer_SchemaErrors(t *testing.T) {
+	mock := httptest.NewServer(New(WithSchemaErrors("/v2/account/wallet", "/v2/items")))
+	defer mock.Close()
+
+	_, wallet := get(t, mock.URL+"/v2/account/wallet", KeyFull)
+	var entries []struct {
+		ID    int `json:"id"`
+		Value int `json:"value"`
+	}
+	if err := json.Unmarshal([]byte(wallet), &entries); err == nil {
+		t.Errorf("Expected the wallet not to decode into the documented schema, got %s", wallet)
+	}
+
+	if _, item := get(t, mock.URL+"/v2/items/19976", ""); !strings.Contains(item, `"id":"19976"`) {
+		t.Errorf("Expected single item paths to be affected too, got %s", item)
+	}
+
+	if _, currencies := get(t, mock.URL+"/v2/currencies?ids=1", ""); !strings.Contains(currencies, `"id":1`) {
+		t.Errorf("Expected other endpoints to be unaffected, got %s", currencies)
+	}
+
+	if status, body := get(t, mock.URL+"/v2/items/1", ""); status != http.StatusNotFound || !strings.Contains(body, "no such id") {
+		t.Errorf("Expected errors to be unaffected, got %d %s", status, body)
+	}
+
+	// The rewritten body is longer, but writers must report the bytes they were given
+	rec := httptest.NewRecorder()
+	body := []byte(`{"id":1,"value":100}`)
+	if n, err := (&schemaBreaker{ResponseWriter: rec}).Write(body); err != nil || n != len(body) {
+		t.Errorf("Expected Write to report %d bytes, got %d (%v)", len(body), n, err)
+	}
+	if got := rec.Body.String(); got != `{"id":"1","value":"100"}` {
+		t.Errorf("Expected numbers encoded as strings, got %s", got)
+	}
+}
+
+func TestServer_Latency(t *testing.T) {
+	mock := httptest.NewServer(New(WithLatency(time.Second)))
+	defer mock.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, mock.URL+"/v2/currencies", http.NoBody)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err == nil {
+		_ = resp.Body.Close()
+		t.Fatal("Expected the request to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the client timeout to apply, took %v", elapsed)
+	}
+}
+
+// get performs a GET request with an optional bearer key and returns the status and body
+func get(t *testing.T, rawURL, key string) (int, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, http.NoBody)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read body: %v", err)
+	}
+	return resp.StatusCode, string(body)
+}
@@ -0,0 +1,141 @@
+package gw2mock
+
+import "net/http"
+
+// unlock mirrors the records of the unlock endpoints keyed by number, such as /v2/skins,
+// /v2/colors and /v2/minis, with the fields each of them uses
+type unlock struct {
+	ID         int            `json:"id"`
+	Name       string         `json:"name"`
+	Type       string         `json:"type,omitempty"`       // skins
+	Rarity     string         `json:"rarity,omitempty"`     // skins
+	Details    map[string]any `json:"details,omitempty"`    // skins
+	Categories []string       `json:"categories,omitempty"` // dyes: hue, material and rarity
+	Mount      string         `json:"mount,omitempty"`      // mount skins
+	Slot       string         `json:"slot,omitempty"`       // novelties
+	Order      int            `json:"order,omitempty"`      // minis and outfits
+}
+
+// namedUnlock mirrors the records of the unlock endpoints keyed by name, /v2/mounts/types
+// and /v2/emotes
+type namedUnlock struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name,omitempty"`     // mount types
+	Commands []string `json:"commands,omitempty"` // emotes
+}
+
+// accountFinisher mirrors the entries of /v2/account/finishers
+type accountFinisher struct {
+	ID        int  `json:"id"`
+	Permanent bool `json:"permanent"`
+	Quantity  int  `json:"quantity,omitempty"` // uses left of a finisher that is not permanent
+}
+
+// unlocks holds the records of the unlock endpoints keyed by number, by path under /v2
+var unlocks = map[string]map[int]unlock{
+	"skins": {
+		10: {ID: 10, Name: "Seer Coat", Type: "Armor", Rarity: "Fine",
+			Details: map[string]any{"type": "Coat", "weight_class": "Light"}},
+		11: {ID: 11, Name: "Seer Pants", Type: "Armor", Rarity: "Fine",
+			Details: map[string]any{"type": "Leggings", "weight_class": "Light"}},
+		4677: {ID: 4677, Name: "Eternity", Type: "Weapon", Rarity: "Legendary",
+			Details: map[string]any{"type": "Greatsword", "damage_type": "Physical"}},
+		4678: {ID: 4678, Name: "Twilight", Type: "Weapon", Rarity: "Legendary",
+			Details: map[string]any{"type": "Greatsword", "damage_type": "Physical"}},
+		4679: {ID: 4679, Name: "Sunrise", Type: "Weapon", Rarity: "Legendary",
+			Details: map[string]any{"type": "Greatsword", "damage_type": "Physical"}},
+		6013: {ID: 6013, Name: "Wings of Glory", Type: "Back", Rarity: "Exotic"},
+	},
+	"colors": {
+		1:   {ID: 1, Name: "Dye Remover"},
+		2:   {ID: 2, Name: "Black", Categories: []string{"Gray", "Leather", "Starter"}},
+		104: {ID: 104, Name: "Abyss", Categories: []string{"Gray", "Metal", "Rare"}},
+		473: {ID: 473, Name: "Celestial", Categories: []string{"Gray", "Vibrant", "Rare"}},
+		485: {ID: 485, Name: "Flame", Categories: []string{"Red", "Vibrant", "Rare"}},
+	},
+	"minis": {
+		1: {ID: 1, Name: "Mini Rytlock", Order: 1},
+		2: {ID: 2, Name: "Mini Logan", Order: 2},
+		3: {ID: 3, Name: "Mini Zojja", Order: 3},
+	},
+	"outfits": {
+		1: {ID: 1, Name: "Cook's Outfit", Order: 1},
+		4: {ID: 4, Name: "Executioner Outfit", Order: 4},
+	},
+	"gliders": {
+		1: {ID: 1, Name: "Basic Glider"},
+		2: {ID: 2, Name: "Wings of Glory Glider"},
+	},
+	"mounts/skins": {
+		1:  {ID: 1, Name: "Raptor", Mount: "raptor"},
+		2:  {ID: 2, Name: "Springer", Mount: "springer"},
+		3:  {ID: 3, Name: "Skimmer", Mount: "skimmer"},
+		27: {ID: 27, Name: "Tiger Raptor", Mount: "raptor"},
+	},
+	"novelties": {
+		1: {ID: 1, Name: "Exalted Chair", Slot: "Chair"},
+		2: {ID: 2, Name: "Magnanimous Choir Bell", Slot: "Music"},
+		3: {ID: 3, Name: "Endless Quaggan Tonic", Slot: "Tonic"},
+	},
+	"finishers": {
+		1: {ID: 1, Name: "Rabbit Finisher"},
+		2: {ID: 2, Name: "Golem Finisher"},
+		3: {ID: 3, Name: "Llama Finisher"},
+	},
+	"mailcarriers": {
+		1: {ID: 1, Name: "Default Mail Carrier"},
+		2: {ID: 2, Name: "Quaggan Mail Carrier"},
+	},
+	"titles": {
+		1: {ID: 1, Name: "Been There. Done That."},
+		2: {ID: 2, Name: "Legendary Craftsman"},
+	},
+	"jadebots": {
+		1: {ID: 1, Name: "Jade Bot"},
+		2: {ID: 2, Name: "Sunspear Jade Bot"},
+	},
+	"skiffs": {
+		1: {ID: 1, Name: "Basic Skiff"},
+		2: {ID: 2, Name: "Shark Skiff"},
+	},
+}
+
+// namedUnlocks holds the records of the unlock endpoints keyed by name, by path under /v2
+var namedUnlocks = map[string]map[string]namedUnlock{
+	"mounts/types": {
+		"griffon":  {ID: "griffon", Name: "Griffon"},
+		"raptor":   {ID: "raptor", Name: "Raptor"},
+		"skimmer":  {ID: "skimmer", Name: "Skimmer"},
+		"springer": {ID: "springer", Name: "Springer"},
+	},
+	"emotes": {
+		"beckon": {ID: "beckon", Commands: []string{"/beckon"}},
+		"shiver": {ID: "shiver", Commands: []string{"/shiver", "/shiverplus"}},
+		"step":   {ID: "step", Commands: []string{"/step"}},
+	},
+}
+
+// accountUnlocks holds the unlocks of the mock account, by path under /v2/account
+var accountUnlocks = map[string]any{
+	"skins":        []int{10, 4677, 4678, 9999}, // 9999 is no longer described by /v2/skins
+	"dyes":         []int{1, 2, 104},
+	"minis":        []int{1, 3},
+	"outfits":      []int{1},
+	"gliders":      []int{1},
+	"mounts/skins": []int{1, 2, 3},
+	"mounts/types": []string{"raptor", "springer", "skimmer"},
+	"novelties":    []int{1, 2},
+	"finishers":    []accountFinisher{{ID: 1, Permanent: true}, {ID: 3, Quantity: 5}},
+	"mailcarriers": []int{1},
+	"titles":       []int{1},
+	"emotes":       []string{"beckon"},
+	"jadebots":     []int{1},
+	"skiffs":       []int{1},
+}
+
+// handleAccountUnlocks serves the unlocks of a kind under /v2/account
+func handleAccountUnlocks(owned any) func(http.ResponseWriter, *http.Request, Key) {
+	return func(w http.ResponseWriter, _ *http.Request, _ Key) {
+		writeJSON(w, http.StatusOK, owned)
+	}
+}
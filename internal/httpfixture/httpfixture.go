@@ -0,0 +1,235 @@
+// Package httpfixture records HTTP responses to fixture files and replays them, so the
+// GW2 API and wiki clients can be tested and run without network access.
+package httpfixture
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultDir is the default directory of fixture files
+	DefaultDir = "testdata/fixtures"
+
+	// redacted replaces API keys in recorded requests and responses
+	redacted = "REDACTED"
+
+	// maxNameLength caps the readable part of fixture file names
+	maxNameLength = 80
+)
+
+// recordedHeaders are the response headers kept in fixtures. Other headers, such as
+// dates and cookies, would make fixtures change on every recording.
+var recordedHeaders = []string{
+	"Content-Type",
+	"Content-Language",
+	"Retry-After",
+	"X-Page-Size",
+	"X-Page-Total",
+	"X-Result-Count",
+	"X-Result-Total",
+}
+
+// unsafeNameChars matches characters not kept in fixture file names
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// Fixture is a recorded HTTP exchange
+type Fixture struct {
+	Method        string            `json:"method"`
+	URL           string            `json:"url"`
+	Authenticated bool              `json:"authenticated,omitempty"`
+	Status        int               `json:"status"`
+	Header        map[string]string `json:"header,omitempty"`
+	Body          json.RawMessage   `json:"body,omitempty"`
+	Text          string            `json:"text,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that performs requests with the next transport and
+// writes every response to a fixture file, with API keys redacted
+type Recorder struct {
+	dir  string
+	next http.RoundTripper
+	mu   sync.Mutex
+}
+
+// NewRecorder creates a Recorder writing fixtures to dir. A nil next uses http.DefaultTransport.
+func NewRecorder(dir string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{dir: dir, next: next}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	closeErr := resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if closeErr != nil {
+		return nil, closeErr
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	fixture := Fixture{
+		Method:        req.Method,
+		URL:           canonicalURL(req.URL).String(),
+		Authenticated: apiKey(req) != "",
+		Status:        resp.StatusCode,
+		Header:        make(map[string]string),
+	}
+	for _, name := range recordedHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			fixture.Header[name] = value
+		}
+	}
+
+	if key := apiKey(req); key != "" {
+		body = bytes.ReplaceAll(body, []byte(key), []byte(redacted))
+	}
+	if json.Valid(body) {
+		fixture.Body = body
+	} else {
+		fixture.Text = string(body)
+	}
+
+	if err := r.write(req, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to record fixture: %w", err)
+	}
+
+	return resp, nil
+}
+
+// write stores a fixture in the recorder directory
+func (r *Recorder) write(req *http.Request, fixture *Fixture) error {
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(r.dir, FileName(req)), append(data, '\n'), 0o644)
+}
+
+// Replayer is an http.RoundTripper answering requests from fixture files, failing
+// requests that were never recorded
+type Replayer struct {
+	dir string
+}
+
+// NewReplayer creates a Replayer reading fixtures from dir
+func NewReplayer(dir string) *Replayer {
+	return &Replayer{dir: dir}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+
+	data, err := os.ReadFile(filepath.Join(r.dir, FileName(req)))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no recorded fixture for %s %s", req.Method, canonicalURL(req.URL).String())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture: %w", err)
+	}
+
+	// JSON bodies are stored indented for readable diffs and served compact like the APIs
+	body := []byte(fixture.Text)
+	if len(fixture.Body) > 0 {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, fixture.Body); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture body: %w", err)
+		}
+		body = compact.Bytes()
+	}
+
+	header := make(http.Header)
+	for name, value := range fixture.Header {
+		header.Set(name, value)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Status, http.StatusText(fixture.Status)),
+		StatusCode:    fixture.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// FileName returns the name of the fixture file of a request. Requests differing only by
+// their API key share a fixture, so fixtures recorded with a real key replay with any key.
+func FileName(req *http.Request) string {
+	canonical := canonicalURL(req.URL)
+	key := req.Method + " " + canonical.String()
+	if apiKey(req) != "" {
+		key += " authenticated"
+	}
+	sum := sha256.Sum256([]byte(key))
+
+	name := canonical.Host + canonical.Path
+	if query, err := url.QueryUnescape(canonical.RawQuery); err == nil && query != "" {
+		name += "_" + query
+	}
+	name = strings.Trim(unsafeNameChars.ReplaceAllString(name, "_"), "_")
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+
+	return fmt.Sprintf("%s_%s_%s.json", req.Method, name, hex.EncodeToString(sum[:4]))
+}
+
+// canonicalURL returns a URL with sorted query parameters and the access_token
+// parameter redacted
+func canonicalURL(u *url.URL) *url.URL {
+	canonical := *u
+	query := canonical.Query()
+	if query.Has("access_token") {
+		query.Set("access_token", redacted)
+	}
+	canonical.RawQuery = query.Encode()
+	canonical.Fragment = ""
+	return &canonical
+}
+
+// apiKey returns the API key sent with a request as bearer token or access_token parameter
+func apiKey(req *http.Request) string {
+	if token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); ok {
+		return token
+	}
+	return req.URL.Query().Get("access_token")
+}
@@ -0,0 +1,193 @@
+package httpfixture
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testKey = "ABCDEF12-3456-7890-ABCD-EF1234567890FEDCBA98-7654-3210-FEDC-BA9876543210"
+
+func TestRecordAndReplay(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("X-Result-Total", "1")
+		w.Header().Set("Set-Cookie", "session=secret")
+		switch r.URL.Path {
+		case "/v2/tokeninfo":
+			_, _ = w.Write([]byte(`{"id":"` + testKey + `","name":"test","permissions":["account","wallet"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("page not found"))
+		}
+	}))
+	defer upstream.Close()
+
+	dir := t.TempDir()
+	recording := &http.Client{Transport: NewRecorder(dir, nil)}
+	replaying := &http.Client{Transport: NewReplayer(dir)}
+
+	tests := []struct {
+		name       string
+		path       string
+		bearer     bool
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "bearer token",
+			path:       "/v2/tokeninfo",
+			bearer:     true,
+			wantStatus: http.StatusOK,
+			wantBody:   `"id":"REDACTED"`,
+		},
+		{
+			name:       "access_token parameter",
+			path:       "/v2/tokeninfo?access_token=" + testKey + "&v=latest",
+			wantStatus: http.StatusOK,
+			wantBody:   `"id":"REDACTED"`,
+		},
+		{
+			name:       "non-JSON error body",
+			path:       "/v2/missing",
+			wantStatus: http.StatusNotFound,
+			wantBody:   "page not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newRequest := func() *http.Request {
+				req, err := http.NewRequest(http.MethodGet, upstream.URL+tt.path, http.NoBody)
+				if err != nil {
+					t.Fatalf("Failed to create request: %v", err)
+				}
+				if tt.bearer {
+					req.Header.Set("Authorization", "Bearer "+testKey)
+				}
+				return req
+			}
+
+			// Recording passes the live response through unchanged
+			recorded := do(t, recording, newRequest())
+			if strings.Contains(tt.wantBody, "REDACTED") && !strings.Contains(recorded, testKey) {
+				t.Errorf("Expected the live response to keep the key, got %s", recorded)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, FileName(newRequest())))
+			if err != nil {
+				t.Fatalf("Expected a fixture file: %v", err)
+			}
+			if strings.Contains(string(data), testKey) {
+				t.Errorf("Expected the API key to be redacted from the fixture, got %s", data)
+			}
+			if strings.Contains(string(data), "session=secret") {
+				t.Errorf("Expected unlisted headers not to be recorded, got %s", data)
+			}
+
+			req := newRequest()
+			resp, err := replaying.Do(req)
+			if err != nil {
+				t.Fatalf("Replay failed: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+			if !strings.Contains(string(body), tt.wantBody) {
+				t.Errorf("Expected replayed body to contain %q, got %s", tt.wantBody, body)
+			}
+			if got := resp.Header.Get("X-Result-Total"); got != "1" {
+				t.Errorf("Expected recorded headers to be replayed, got X-Result-Total %q", got)
+			}
+		})
+	}
+}
+
+func TestReplayer_Missing(t *testing.T) {
+	client := &http.Client{Transport: NewReplayer(t.TempDir())}
+
+	_, err := client.Get("https://api.guildwars2.com/v2/currencies?access_token=" + testKey)
+	if err == nil {
+		t.Fatal("Expected an error for a request without fixture")
+	}
+	if !strings.Contains(err.Error(), "no recorded fixture") {
+		t.Errorf("Expected a missing fixture error, got %v", err)
+	}
+
+	// http.Client adds the request URL itself; the fixture error must not repeat the key
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) && strings.Contains(urlErr.Err.Error(), testKey) {
+		t.Errorf("Expected the API key to be redacted from the error, got %v", urlErr.Err)
+	}
+}
+
+func TestFileName(t *testing.T) {
+	request := func(rawURL, bearer string) *http.Request {
+		req, err := http.NewRequest(http.MethodGet, rawURL, http.NoBody)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		return req
+	}
+
+	base := FileName(request("https://api.guildwars2.com/v2/currencies?ids=1,4&lang=en", ""))
+	if !strings.HasPrefix(base, "GET_api.guildwars2.com_v2_currencies_ids_1_4_lang_en_") {
+		t.Errorf("Expected a readable file name, got %q", base)
+	}
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		same bool
+	}{
+		{"query order", request("https://api.guildwars2.com/v2/currencies?lang=en&ids=1,4", ""), true},
+		{"different query", request("https://api.guildwars2.com/v2/currencies?ids=1&lang=en", ""), false},
+		{"authenticated", request("https://api.guildwars2.com/v2/currencies?ids=1,4&lang=en", "KEY"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FileName(tt.req); (got == base) != tt.same {
+				t.Errorf("FileName() = %q, base %q, expected same: %v", got, base, tt.same)
+			}
+		})
+	}
+
+	// Fixtures recorded with one key replay with any other
+	first := FileName(request("https://api.guildwars2.com/v2/account", "KEY-1"))
+	second := FileName(request("https://api.guildwars2.com/v2/account?access_token=KEY-2", ""))
+	third := FileName(request("https://api.guildwars2.com/v2/account", "KEY-2"))
+	if first != third {
+		t.Errorf("Expected the same fixture for different bearer keys, got %q and %q", first, third)
+	}
+	if strings.Contains(second, "KEY-2") {
+		t.Errorf("Expected the access_token to be redacted from the file name, got %q", second)
+	}
+}
+
+// do performs a request and returns the response body
+func do(t *testing.T, client *http.Client, req *http.Request) string {
+	t.Helper()
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read body: %v", err)
+	}
+	return string(body)
+}
@@ -0,0 +1,41 @@
+// Package progress carries a progress reporter through a context, so API and wiki
+// clients can report the progress of long operations without knowing who listens.
+package progress
+
+import "context"
+
+// Reporter receives progress updates. Done may be fractional when a step reports its own
+// progress, and total is 0 when unknown.
+type Reporter func(done, total float64, message string)
+
+// reporterKey is the context key of the reporter
+type reporterKey struct{}
+
+// WithReporter returns a context carrying the reporter
+func WithReporter(ctx context.Context, reporter Reporter) context.Context {
+	return context.WithValue(ctx, reporterKey{}, reporter)
+}
+
+// Report sends a progress update to the reporter of the context, if any
+func Report(ctx context.Context, done, total int, message string) {
+	if reporter, ok := ctx.Value(reporterKey{}).(Reporter); ok && reporter != nil {
+		reporter(float64(done), float64(total), message)
+	}
+}
+
+// Sub returns a context for the given step, counted from 1, of an operation of steps
+// steps, which reports the progress of the step as a fraction of it to the reporter of
+// ctx. The end of the step is left for the operation itself to report, along with what
+// the step did, and updates of unknown total are dropped as they cannot be placed.
+func Sub(ctx context.Context, step, steps int) context.Context {
+	parent, ok := ctx.Value(reporterKey{}).(Reporter)
+	if !ok || parent == nil {
+		return ctx
+	}
+	return WithReporter(ctx, func(done, total float64, message string) {
+		if total <= 0 || done >= total {
+			return
+		}
+		parent(float64(step-1)+done/total, float64(steps), message)
+	})
+}
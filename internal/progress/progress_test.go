@@ -0,0 +1,52 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	// Reporting without a reporter is a no-op
+	Report(context.Background(), 1, 2, "ignored")
+
+	var got []string
+	ctx := WithReporter(context.Background(), func(done, total float64, message string) {
+		got = append(got, message)
+		if done != 3 || total != 12 {
+			t.Errorf("Expected 3/12, got %g/%g", done, total)
+		}
+	})
+
+	Report(ctx, 3, 12, "fetched 3/12 characters")
+
+	if len(got) != 1 || got[0] != "fetched 3/12 characters" {
+		t.Errorf("Expected one report, got %v", got)
+	}
+}
+
+func TestSub(t *testing.T) {
+	// Without a reporter there is nothing to scope
+	if ctx := context.Background(); Sub(ctx, 1, 2) != ctx {
+		t.Error("Expected the context to be returned as is")
+	}
+
+	var got []string
+	ctx := WithReporter(context.Background(), func(done, total float64, message string) {
+		got = append(got, fmt.Sprintf("%g/%g %s", done, total, message))
+	})
+
+	Report(ctx, 1, 4, "step 1")
+	step := Sub(ctx, 2, 4)
+	Report(step, 1, 4, "page 1")
+	Report(Sub(step, 2, 4), 1, 2, "nested")
+	Report(step, 0, 0, "unknown total")
+	Report(step, 4, 4, "page 4")
+	Report(ctx, 2, 4, "step 2")
+
+	want := []string{"1/4 step 1", "1.25/4 page 1", "1.375/4 nested", "2/4 step 2"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
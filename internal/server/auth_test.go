@@ -0,0 +1,232 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/auth"
+	"github.com/AlyxPink/gw2-mcp/internal/cache"
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+)
+
+func TestAuthenticatedSessions_WalletIsolation(t *testing.T) {
+	tests := []struct {
+		name      string
+		transport Transport
+		newClient func(serverURL, token string) (*client.Client, error)
+	}{
+		{
+			name:      "streamable HTTP",
+			transport: TransportStreamableHTTP,
+			newClient: func(serverURL, token string) (*client.Client, error) {
+				return client.NewStreamableHttpClient(serverURL+"/mcp",
+					transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + token}))
+			},
+		},
+		{
+			name:      "SSE",
+			transport: TransportSSE,
+			newClient: func(serverURL, token string) (*client.Client, error) {
+				return client.NewSSEMCPClient(serverURL+"/sse",
+					transport.WithHeaders(map[string]string{"Authorization": "Bearer " + token}))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := auth.NewStore([]auth.ProfileConfig{
+				{Name: "alice", Token: "alice-token", APIKey: "ALICE-KEY"},
+				{Name: "bob", Token: "bob-token", APIKey: "BOB-KEY"},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create store: %v", err)
+			}
+
+			s, err := NewMCPServer(log.New(io.Discard), WithTransport(tt.transport), WithAuth(store))
+			if err != nil {
+				t.Fatalf("Failed to create server: %v", err)
+			}
+
+			// Seed each profile's cached wallet so no upstream request is made
+			wallets := map[string]int{"ALICE-KEY": 111, "BOB-KEY": 222}
+			for apiKey, coins := range wallets {
+				cacheKey := s.cache.GetWalletKey(gw2api.HashAPIKey(apiKey), string(gw2api.DefaultLanguage))
+				wallet := gw2api.WalletInfo{Entries: []gw2api.WalletEntry{{ID: 1, Value: coins}}, Total: 1}
+				if err := s.cache.SetJSON(cacheKey, wallet, cache.WalletDataTTL); err != nil {
+					t.Fatalf("Failed to seed wallet: %v", err)
+				}
+			}
+
+			httpTransport, err := s.newHTTPTransport(nil)
+			if err != nil {
+				t.Fatalf("Failed to create HTTP transport: %v", err)
+			}
+			httpServer := httptest.NewServer(s.httpHandler(httpTransport))
+			defer httpServer.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			getWallet := func(token string, args map[string]any) *mcp.CallToolResult {
+				t.Helper()
+
+				mcpClient, err := tt.newClient(httpServer.URL, token)
+				if err != nil {
+					t.Fatalf("Failed to create client: %v", err)
+				}
+				defer func() { _ = mcpClient.Close() }()
+
+				if err := mcpClient.Start(ctx); err != nil {
+					t.Fatalf("Failed to start client: %v", err)
+				}
+
+				initRequest := mcp.InitializeRequest{}
+				initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+				if _, err := mcpClient.Initialize(ctx, initRequest); err != nil {
+					t.Fatalf("Failed to initialize: %v", err)
+				}
+
+				callRequest := mcp.CallToolRequest{}
+				callRequest.Params.Name = "get_wallet"
+				callRequest.Params.Arguments = args
+				result, err := mcpClient.CallTool(ctx, callRequest)
+				if err != nil {
+					t.Fatalf("Failed to call get_wallet: %v", err)
+				}
+				return result
+			}
+
+			coinsOf := func(result *mcp.CallToolResult) int {
+				t.Helper()
+
+				if result.IsError || len(result.Content) == 0 {
+					t.Fatalf("Unexpected tool result: %+v", result)
+				}
+				var wallet gw2api.WalletInfo
+				text := result.Content[0].(mcp.TextContent).Text
+				if err := json.Unmarshal([]byte(text), &wallet); err != nil {
+					t.Fatalf("Failed to decode wallet: %v", err)
+				}
+				return wallet.Entries[0].Value
+			}
+
+			if coins := coinsOf(getWallet("alice-token", nil)); coins != 111 {
+				t.Errorf("Expected profile alice to see its own wallet (111), got %d", coins)
+			}
+
+			if coins := coinsOf(getWallet("bob-token", nil)); coins != 222 {
+				t.Errorf("Expected profile bob to see its own wallet (222), got %d", coins)
+			}
+
+			// The session profile wins over an api_key argument naming the key of another profile
+			if coins := coinsOf(getWallet("bob-token", map[string]any{"api_key": "ALICE-KEY"})); coins != 222 {
+				t.Errorf("Expected profile bob's session to stay bound to its own key (222), got %d", coins)
+			}
+
+			// Unauthenticated clients are rejected before reaching any tool
+			mcpClient, err := tt.newClient(httpServer.URL, "")
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			defer func() { _ = mcpClient.Close() }()
+			if err := mcpClient.Start(ctx); err == nil {
+				if _, err := mcpClient.Initialize(ctx, mcp.InitializeRequest{}); err == nil {
+					t.Error("Expected unauthenticated initialize to fail")
+				}
+			}
+		})
+	}
+}
+
+func TestAuthenticatedSessions_ClosedSSESessionIsForgotten(t *testing.T) {
+	store, err := auth.NewStore([]auth.ProfileConfig{
+		{Name: "alice", Token: "alice-token", APIKey: "ALICE-KEY"},
+		{Name: "bob", Token: "bob-token", APIKey: "BOB-KEY"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	s, err := NewMCPServer(log.New(io.Discard), WithTransport(TransportSSE), WithAuth(store))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	httpTransport, err := s.newHTTPTransport(nil)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP transport: %v", err)
+	}
+	httpServer := httptest.NewServer(s.httpHandler(httpTransport))
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mcpClient, err := client.NewSSEMCPClient(httpServer.URL+"/sse",
+		transport.WithHeaders(map[string]string{"Authorization": "Bearer alice-token"}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if err := mcpClient.Start(ctx); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+	sseTransport, ok := mcpClient.GetTransport().(*transport.SSE)
+	if !ok {
+		t.Fatalf("Expected an SSE transport, got %T", mcpClient.GetTransport())
+	}
+	sessionID := sseTransport.GetEndpoint().Query().Get("sessionId")
+
+	// sendAsBob posts a message to the session with the token of the other profile
+	sendAsBob := func() int {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+			httpServer.URL+"/message?sessionId="+sessionID, http.NoBody)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer bob-token")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to post message: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// The session is bound as the SSE connection opens it, before alice sends any message
+	if code := sendAsBob(); code != http.StatusForbidden {
+		t.Fatalf("Expected profile bob to be denied the session of profile alice, got %d", code)
+	}
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	if _, err := mcpClient.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if code := sendAsBob(); code != http.StatusForbidden {
+		t.Fatalf("Expected profile bob to still be denied the session of profile alice, got %d", code)
+	}
+
+	// Closing the SSE connection ends the session, which frees its ID
+	if err := mcpClient.Close(); err != nil {
+		t.Fatalf("Failed to close client: %v", err)
+	}
+	for sendAsBob() == http.StatusForbidden {
+		if ctx.Err() != nil {
+			t.Fatal("Expected the closed session to be forgotten")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
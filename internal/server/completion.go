@@ -0,0 +1,223 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/auth"
+	"github.com/AlyxPink/gw2-mcp/internal/wiki"
+)
+
+// maxCompletionValues is the maximum number of values a completion response may hold
+const maxCompletionValues = 100
+
+var (
+	// professions lists the playable professions
+	professions = []string{
+		"Elementalist", "Engineer", "Guardian", "Mesmer", "Necromancer",
+		"Ranger", "Revenant", "Thief", "Warrior",
+	}
+	// disciplines lists the crafting disciplines
+	disciplines = []string{
+		"Armorsmith", "Artificer", "Chef", "Huntsman", "Jeweler",
+		"Leatherworker", "Scribe", "Tailor", "Weaponsmith",
+	}
+)
+
+// completeFunc returns the completion values for a partially typed argument
+type completeFunc func(ctx context.Context, value string) ([]string, error)
+
+// completionProvider completes prompt and resource template arguments from cached
+// metadata and local indexes, so completing does not call the API per keystroke
+type completionProvider struct {
+	server *MCPServer
+}
+
+// CompletePromptArgument completes a prompt argument. Arguments are completed by name,
+// since prompts share argument names such as profile and target_item.
+func (p *completionProvider) CompletePromptArgument(ctx context.Context, _ string,
+	argument mcp.CompleteArgument, _ mcp.CompleteContext,
+) (*mcp.Completion, error) {
+	var complete completeFunc
+	switch argument.Name {
+	case "profile":
+		complete = p.completeProfile
+	case "character":
+		complete = p.completeCharacter
+	case "profession":
+		complete = staticCompletion(professions)
+	case "discipline":
+		complete = staticCompletion(disciplines)
+	case "target_item":
+		complete = p.completeItemName
+	}
+
+	return p.run(ctx, complete, argument)
+}
+
+// CompleteResourceArgument completes a resource template argument
+func (p *completionProvider) CompleteResourceArgument(ctx context.Context, uri string,
+	argument mcp.CompleteArgument, _ mcp.CompleteContext,
+) (*mcp.Completion, error) {
+	var complete completeFunc
+	switch {
+	case uri == "gw2://currency/{id}" && argument.Name == "id":
+		complete = p.completeCurrencyID
+	case uri == "gw2://item/{id}" && argument.Name == "id":
+		complete = p.completeItemID
+	case uri == "wiki://page/{title}" && argument.Name == "title":
+		complete = p.completeWikiTitle
+	}
+
+	return p.run(ctx, complete, argument)
+}
+
+// run executes a completer and limits its values to what the protocol allows.
+// Completion is best effort: failures are logged and answered with no values.
+func (p *completionProvider) run(ctx context.Context, complete completeFunc,
+	argument mcp.CompleteArgument,
+) (*mcp.Completion, error) {
+	completion := &mcp.Completion{Values: []string{}}
+	if complete == nil {
+		return completion, nil
+	}
+
+	values, err := complete(ctx, argument.Value)
+	if err != nil {
+		p.server.logger.Warn("Failed to complete argument", "argument", argument.Name, "error", err)
+		return completion, nil
+	}
+
+	completion.Total = len(values)
+	if len(values) > maxCompletionValues {
+		values = values[:maxCompletionValues]
+		completion.HasMore = true
+	}
+	if values != nil {
+		completion.Values = values
+	}
+
+	return completion, nil
+}
+
+// completeProfile completes the profile of the authenticated session; other profiles are never revealed
+func (p *completionProvider) completeProfile(ctx context.Context, value string) ([]string, error) {
+	profile, ok := auth.ProfileFromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	return filterPrefix([]string{profile.Name}, value), nil
+}
+
+// completeCharacter completes the character names of the authenticated session's account
+func (p *completionProvider) completeCharacter(ctx context.Context, value string) ([]string, error) {
+	profile, ok := auth.ProfileFromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	names, err := p.server.gw2API.GetCharacterNames(ctx, profile.APIKey)
+	if err != nil {
+		return nil, err
+	}
+	return filterPrefix(names, value), nil
+}
+
+// completeItemName completes item names from the local index, falling back to wiki titles
+func (p *completionProvider) completeItemName(ctx context.Context, value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	items := p.server.gw2API.SearchItemNames(value, p.server.defaultLang)
+	if len(items) == 0 {
+		return p.completeWikiTitle(ctx, value)
+	}
+
+	// Several items can share a name, e.g. account bound and tradeable versions
+	seen := make(map[string]bool, len(items))
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		if !seen[item.Name] {
+			seen[item.Name] = true
+			names = append(names, item.Name)
+		}
+	}
+	return names, nil
+}
+
+// completeItemID completes item IDs from names typed into the {id} variable
+func (p *completionProvider) completeItemID(_ context.Context, value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	items := p.server.gw2API.SearchItemNames(value, p.server.defaultLang)
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = strconv.Itoa(item.ID)
+	}
+	return ids, nil
+}
+
+// completeCurrencyID completes currency IDs by ID prefix or name, using the cached currency list
+func (p *completionProvider) completeCurrencyID(ctx context.Context, value string) ([]string, error) {
+	currencies, err := p.server.gw2API.GetCurrencies(ctx, nil, p.server.defaultLang)
+	if err != nil {
+		return nil, err
+	}
+
+	query := strings.ToLower(strings.TrimSpace(value))
+	var ids []int
+	for id, currency := range currencies {
+		idStr := strconv.Itoa(id)
+		if strings.HasPrefix(idStr, query) || strings.Contains(strings.ToLower(currency.Name), query) {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Ints(ids)
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.Itoa(id)
+	}
+	return values, nil
+}
+
+// completeWikiTitle completes wiki article titles
+func (p *completionProvider) completeWikiTitle(ctx context.Context, value string) ([]string, error) {
+	return p.server.wiki.CompleteTitles(ctx, value, p.server.defaultWikiLanguage())
+}
+
+// staticCompletion completes from a fixed list of values
+func staticCompletion(values []string) completeFunc {
+	return func(_ context.Context, value string) ([]string, error) {
+		return filterPrefix(values, value), nil
+	}
+}
+
+// filterPrefix keeps the values starting with prefix, ignoring case
+func filterPrefix(values []string, prefix string) []string {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	var filtered []string
+	for _, value := range values {
+		if strings.HasPrefix(strings.ToLower(value), prefix) {
+			filtered = append(filtered, value)
+		}
+	}
+	return filtered
+}
+
+// defaultWikiLanguage returns the wiki matching the server language.
+// The GW2 API supports more languages than the wiki, English is used for those.
+func (s *MCPServer) defaultWikiLanguage() wiki.Language {
+	lang, err := wiki.ParseLanguage(string(s.defaultLang))
+	if err != nil {
+		return wiki.DefaultLanguage
+	}
+	return lang
+}
@@ -0,0 +1,214 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/auth"
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+	"github.com/AlyxPink/gw2-mcp/internal/wiki"
+)
+
+func TestCompletions(t *testing.T) {
+	var gw2Requests atomic.Int32
+	gw2Server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gw2Requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/currencies" && r.URL.Query().Get("ids") == "":
+			_, _ = w.Write([]byte(`[1,4,23]`))
+		case r.URL.Path == "/currencies":
+			_, _ = w.Write([]byte(`[{"id":1,"name":"Coin"},{"id":4,"name":"Gem"},{"id":23,"name":"Spirit Shard"}]`))
+		case r.URL.Path == "/items" && r.URL.Query().Get("ids") == "":
+			_, _ = w.Write([]byte(`[19721,19976,19675]`))
+		case r.URL.Path == "/items":
+			_, _ = w.Write([]byte(`[{"id":19721,"name":"Glob of Ectoplasm"},{"id":19976,"name":"Mystic Coin"},` +
+				`{"id":19675,"name":"Mystic Clover"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer gw2Server.Close()
+
+	wikiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`["Dra",["Dragon Bash","Dragonfall"],[],[]]`))
+	}))
+	defer wikiServer.Close()
+
+	s, err := NewMCPServer(log.New(io.Discard),
+		WithGW2APIOptions(gw2api.WithBaseURL(gw2Server.URL)),
+		WithWikiOptions(wiki.WithBaseURL(wiki.LanguageEnglish, wikiServer.URL)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.gw2API.BuildItemIndex(ctx, gw2api.LanguageEnglish); err != nil {
+		t.Fatalf("Failed to build item index: %v", err)
+	}
+
+	mcpClient, err := client.NewInProcessClient(s.mcp)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer func() { _ = mcpClient.Close() }()
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initResult, err := mcpClient.Initialize(ctx, initRequest)
+	if err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if initResult.Capabilities.Completions == nil {
+		t.Error("Expected the completions capability to be advertised")
+	}
+
+	tests := []struct {
+		name     string
+		ref      any
+		argument string
+		value    string
+		expected []string
+	}{
+		{
+			name:     "prompt target item from index",
+			ref:      mcp.PromptReference{Type: "ref/prompt", Name: promptLegendaryProgress},
+			argument: "target_item",
+			value:    "myst",
+			expected: []string{"Mystic Coin", "Mystic Clover"},
+		},
+		{
+			name:     "prompt target item falls back to wiki titles",
+			ref:      mcp.PromptReference{Type: "ref/prompt", Name: promptLegendaryProgress},
+			argument: "target_item",
+			value:    "Dra",
+			expected: []string{"Dragon Bash", "Dragonfall"},
+		},
+		{
+			name:     "prompt discipline",
+			ref:      mcp.PromptReference{Type: "ref/prompt", Name: promptCraftForProfit},
+			argument: "discipline",
+			value:    "we",
+			expected: []string{"Weaponsmith"},
+		},
+		{
+			name:     "prompt profile without session",
+			ref:      mcp.PromptReference{Type: "ref/prompt", Name: promptCraftForProfit},
+			argument: "profile",
+			value:    "",
+			expected: []string{},
+		},
+		{
+			name:     "currency id by name",
+			ref:      mcp.ResourceReference{Type: "ref/resource", URI: "gw2://currency/{id}"},
+			argument: "id",
+			value:    "shard",
+			expected: []string{"23"},
+		},
+		{
+			name:     "currency id by prefix",
+			ref:      mcp.ResourceReference{Type: "ref/resource", URI: "gw2://currency/{id}"},
+			argument: "id",
+			value:    "",
+			expected: []string{"1", "4", "23"},
+		},
+		{
+			name:     "item id by name",
+			ref:      mcp.ResourceReference{Type: "ref/resource", URI: "gw2://item/{id}"},
+			argument: "id",
+			value:    "ecto",
+			expected: []string{"19721"},
+		},
+		{
+			name:     "wiki title",
+			ref:      mcp.ResourceReference{Type: "ref/resource", URI: "wiki://page/{title}"},
+			argument: "title",
+			value:    "Dragon",
+			expected: []string{"Dragon Bash", "Dragonfall"},
+		},
+		{
+			name:     "unknown argument",
+			ref:      mcp.ResourceReference{Type: "ref/resource", URI: "gw2://recipe/{id}"},
+			argument: "id",
+			value:    "1",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CompleteRequest{}
+			request.Params.Ref = tt.ref
+			request.Params.Argument = mcp.CompleteArgument{Name: tt.argument, Value: tt.value}
+
+			result, err := mcpClient.Complete(ctx, request)
+			if err != nil {
+				t.Fatalf("Complete failed: %v", err)
+			}
+			if !reflect.DeepEqual(result.Completion.Values, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result.Completion.Values)
+			}
+		})
+	}
+
+	// Completing currencies twice reuses the cached list
+	before := gw2Requests.Load()
+	provider := &completionProvider{server: s}
+	if _, err := provider.completeCurrencyID(ctx, "g"); err != nil {
+		t.Fatalf("completeCurrencyID failed: %v", err)
+	}
+	if after := gw2Requests.Load(); after != before {
+		t.Errorf("Expected no API request for cached currencies, got %d", after-before)
+	}
+}
+
+func TestCompletions_SessionProfile(t *testing.T) {
+	gw2Server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer ALICE-KEY" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`["Alice Warrior","Alice Thief","Zed"]`))
+	}))
+	defer gw2Server.Close()
+
+	s, err := NewMCPServer(log.New(io.Discard), WithGW2APIOptions(gw2api.WithBaseURL(gw2Server.URL)))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	provider := &completionProvider{server: s}
+	ctx := auth.WithProfile(context.Background(), &auth.Profile{Name: "alice", APIKey: "ALICE-KEY"})
+
+	characters, err := provider.CompletePromptArgument(ctx, promptExplainBuild,
+		mcp.CompleteArgument{Name: "character", Value: "alice"}, mcp.CompleteContext{})
+	if err != nil {
+		t.Fatalf("CompletePromptArgument failed: %v", err)
+	}
+	if expected := []string{"Alice Warrior", "Alice Thief"}; !reflect.DeepEqual(characters.Values, expected) {
+		t.Errorf("Expected %v, got %v", expected, characters.Values)
+	}
+
+	profiles, err := provider.CompletePromptArgument(ctx, promptDailyGoldFarm,
+		mcp.CompleteArgument{Name: "profile", Value: "a"}, mcp.CompleteContext{})
+	if err != nil {
+		t.Fatalf("CompletePromptArgument failed: %v", err)
+	}
+	if expected := []string{"alice"}; !reflect.DeepEqual(profiles.Values, expected) {
+		t.Errorf("Expected %v, got %v", expected, profiles.Values)
+	}
+}
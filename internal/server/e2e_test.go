@@ -0,0 +1,253 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+	"github.com/AlyxPink/gw2-mcp/internal/gw2mock"
+)
+
+func TestEndToEnd_MockGW2API(t *testing.T) {
+	tests := []struct {
+		name      string
+		mockOpts  []gw2mock.Option
+		tool      string
+		arguments map[string]any
+		isError   bool
+		contains  string
+	}{
+		{
+			name:      "wallet",
+			tool:      "get_wallet",
+			arguments: map[string]any{"api_key": gw2mock.KeyWallet, "format": "markdown"},
+			contains:  "| 1 | Coin | 12345678 |",
+		},
+		{
+			name:      "wallet without scope",
+			tool:      "get_wallet",
+			arguments: map[string]any{"api_key": gw2mock.KeyAccount},
+			isError:   true,
+			contains:  "requires scope wallet",
+		},
+		{
+			name:      "unknown key",
+			tool:      "get_wallet",
+			arguments: map[string]any{"api_key": "NOT-A-KEY"},
+			isError:   true,
+			contains:  "Invalid access token",
+		},
+		{
+			name:      "currencies",
+			tool:      "get_currencies",
+			arguments: map[string]any{"ids": []int{1, 4}, "format": "compact"},
+			contains:  `"name":"Gem"`,
+		},
+		{
+			name:      "gem exchange",
+			tool:      "get_gem_exchange",
+			arguments: map[string]any{"from": "coins", "amounts": []float64{100, 0.5}, "format": "compact"},
+			contains:  `"coins":1000000,"gems":289,"coins_per_gem":3450,"slippage_percent":1.47`,
+		},
+		{
+			name:      "gem exchange defaults",
+			tool:      "get_gem_exchange",
+			arguments: map[string]any{"from": "gems", "format": "markdown"},
+			contains:  "| 480g | 4800000 | 2000 | 2400 | 7.34 |",
+		},
+		{
+			name:      "gem exchange fractional gems",
+			tool:      "get_gem_exchange",
+			arguments: map[string]any{"from": "gems", "amounts": []float64{1.5}},
+			isError:   true,
+			contains:  "whole numbers",
+		},
+		{
+			name: "trading post history",
+			tool: "get_tp_transactions",
+			arguments: map[string]any{"api_key": gw2mock.KeyTradingPost, "since": "2026-09-05", "until": "2026-09-30",
+				"format": "compact"},
+			contains: `"profit_formatted":"38g 3s"`,
+		},
+		{
+			name:      "trading post open orders",
+			tool:      "get_tp_transactions",
+			arguments: map[string]any{"api_key": gw2mock.KeyTradingPost, "state": "current", "type": "buys", "format": "markdown"},
+			contains:  "| Mystic Coin | buys | 7001 | 19976 | 10250 | 25 | 256250 |",
+		},
+		{
+			name:      "trading post invalid date",
+			tool:      "get_tp_transactions",
+			arguments: map[string]any{"api_key": gw2mock.KeyTradingPost, "since": "last week"},
+			isError:   true,
+			contains:  "Invalid since parameter",
+		},
+		{
+			name:      "trading post without scope",
+			tool:      "get_tp_transactions",
+			arguments: map[string]any{"api_key": gw2mock.KeyWallet},
+			isError:   true,
+			contains:  "requires scope tradingpost",
+		},
+		{
+			name:      "trading post delivery",
+			tool:      "get_tp_delivery",
+			arguments: map[string]any{"api_key": gw2mock.KeyTradingPost, "format": "markdown"},
+			contains:  "| Glob of Ectoplasm | 19721 | 250 |",
+		},
+		{
+			name:      "trading post flips",
+			tool:      "find_tp_flips",
+			arguments: map[string]any{"sort_by": "roi", "limit": 1, "format": "compact"},
+			contains:  `"item_name":"Vicious Claw","profit_formatted":"28s 30c","capital_formatted":"1g 60s 60c"`,
+		},
+		{
+			name:      "trading post flips capital limit",
+			tool:      "find_tp_flips",
+			arguments: map[string]any{"max_capital": 0.5, "format": "compact"},
+			contains:  `"flips":[{"item_name":"Mithril Ore"`,
+		},
+		{
+			name:      "trading post flips invalid quantity",
+			tool:      "find_tp_flips",
+			arguments: map[string]any{"quantity": 0},
+			isError:   true,
+			contains:  "Invalid quantity parameter",
+		},
+		{
+			name:      "legendary armory",
+			tool:      "get_legendary_armory",
+			arguments: map[string]any{"api_key": gw2mock.KeyFull, "type": "Greatsword", "format": "compact"},
+			contains:  `"unlocked":[{"name":"Eternity","type":"Weapon","subtype":"Greatsword","id":30689,"count":1,`,
+		},
+		{
+			name:      "legendary armory missing items",
+			tool:      "get_legendary_armory",
+			arguments: map[string]any{"api_key": gw2mock.KeyFull, "include_missing": true, "format": "markdown"},
+			contains:  "| Twilight | Weapon | Greatsword | 30704 | 0 | 2 |",
+		},
+		{
+			name:      "legendary progress",
+			tool:      "get_legendary_progress",
+			arguments: map[string]any{"api_key": gw2mock.KeyFull, "legendary": "twilight", "format": "compact"},
+			contains:  `"name":"Twilight","estimated_cost_formatted":"1884g 5s 90c"`,
+		},
+		{
+			name:      "legendary progress unknown legendary",
+			tool:      "get_legendary_progress",
+			arguments: map[string]any{"api_key": gw2mock.KeyFull, "legendary": "Bolt"},
+			isError:   true,
+			contains:  "known legendaries: Twilight",
+		},
+		{
+			name:      "legendary progress without scope",
+			tool:      "get_legendary_progress",
+			arguments: map[string]any{"api_key": gw2mock.KeyWallet, "legendary": "30704"},
+			isError:   true,
+			contains:  "requires scope inventories",
+		},
+		{
+			name:      "missing skins",
+			tool:      "get_unlocks",
+			arguments: map[string]any{"api_key": gw2mock.KeyFull, "kind": "skins", "format": "compact"},
+			contains:  `"owned":3,"missing":3,"total":6,"completion_percent":50`,
+		},
+		{
+			name: "owned dyes by hue",
+			tool: "get_unlocks",
+			arguments: map[string]any{
+				"api_key": gw2mock.KeyFull, "kind": "dyes", "category": "gray", "list": "owned", "format": "markdown",
+			},
+			contains: "| 104 | Abyss | Gray | Metal |",
+		},
+		{
+			name:      "unlocks of an unknown kind",
+			tool:      "get_unlocks",
+			arguments: map[string]any{"api_key": gw2mock.KeyFull, "kind": "wardrobe"},
+			isError:   true,
+			contains:  "Invalid kind parameter",
+		},
+		{
+			name:      "unlocks without scope",
+			tool:      "get_unlocks",
+			arguments: map[string]any{"api_key": gw2mock.KeyWallet, "kind": "minis"},
+			isError:   true,
+			contains:  "requires scope unlocks",
+		},
+		{
+			name:      "rate limited",
+			mockOpts:  []gw2mock.Option{gw2mock.WithRateLimitEvery(1)},
+			tool:      "get_currencies",
+			arguments: map[string]any{"ids": []int{1}},
+			isError:   true,
+			contains:  "429",
+		},
+		{
+			name:      "schema error",
+			mockOpts:  []gw2mock.Option{gw2mock.WithSchemaErrors("/v2/account/wallet")},
+			tool:      "get_wallet",
+			arguments: map[string]any{"api_key": gw2mock.KeyFull},
+			isError:   true,
+			contains:  "Failed to get wallet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := httptest.NewServer(gw2mock.New(tt.mockOpts...))
+			defer mock.Close()
+
+			s, err := NewMCPServer(log.New(io.Discard), WithGW2APIOptions(gw2api.WithBaseURL(mock.URL+"/v2")))
+			if err != nil {
+				t.Fatalf("Failed to create server: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			mcpClient := newInProcessClient(ctx, t, s)
+
+			callRequest := mcp.CallToolRequest{}
+			callRequest.Params.Name = tt.tool
+			callRequest.Params.Arguments = tt.arguments
+			result, err := mcpClient.CallTool(ctx, callRequest)
+			if err != nil {
+				t.Fatalf("Failed to call %s: %v", tt.tool, err)
+			}
+
+			text := result.Content[0].(mcp.TextContent).Text
+			if result.IsError != tt.isError {
+				t.Fatalf("Expected IsError %v, got %v: %s", tt.isError, result.IsError, text)
+			}
+			if !strings.Contains(text, tt.contains) {
+				t.Errorf("Expected result to contain %q, got:\n%s", tt.contains, text)
+			}
+		})
+	}
+}
+
+// newInProcessClient returns an initialized MCP client connected to the server in process
+func newInProcessClient(ctx context.Context, t *testing.T, s *MCPServer) *client.Client {
+	t.Helper()
+
+	mcpClient, err := client.NewInProcessClient(s.mcp)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = mcpClient.Close() })
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	if _, err := mcpClient.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	return mcpClient
+}
@@ -4,8 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/auth"
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+	"github.com/AlyxPink/gw2-mcp/internal/store"
+	"github.com/AlyxPink/gw2-mcp/internal/wiki"
 )
 
 // handleWikiSearch handles wiki search requests
@@ -19,45 +29,242 @@ func (s *MCPServer) handleWikiSearch(ctx context.Context, request mcp.CallToolRe
 	const defaultLimit = 5
 	limit := request.GetInt("limit", defaultLimit)
 
-	s.logger.Debug("Wiki search request", "query", query, "limit", limit)
+	lang, err := wiki.ParseLanguage(request.GetString("lang", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid lang parameter: %v", err)), nil
+	}
+
+	format, err := ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
+	}
+
+	s.logger.Debug("Wiki search request", "query", query, "limit", limit, "lang", lang)
 
 	// Perform wiki search
-	results, err := s.wiki.Search(ctx, query, limit)
+	results, err := s.wiki.Search(ctx, query, limit, lang, wikiTextFormat(format))
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Wiki search failed: %v", err)), nil
 	}
 
-	// Format results as JSON
-	resultJSON, err := json.MarshalIndent(results, "", "  ")
+	return s.structuredResult(results, nil, format, "results"), nil
+}
+
+// handleWikiPage handles wiki page summary requests
+func (s *MCPServer) handleWikiPage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	title, err := request.RequireString("title")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid title parameter: %v", err)), nil
+	}
+
+	lang, err := wiki.ParseLanguage(request.GetString("lang", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid lang parameter: %v", err)), nil
+	}
+
+	format, err := ParseFormat(request.GetString("format", ""))
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to format results: %v", err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(string(resultJSON)), nil
+	s.logger.Debug("Wiki page request", "title", title, "lang", lang)
+
+	// Get page summary
+	page, err := s.wiki.GetPage(ctx, title, lang, wikiTextFormat(format))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get wiki page: %v", err)), nil
+	}
+
+	return s.textResult(page, format, "page"), nil
+}
+
+// wikiTextFormat returns the text format of wiki snippets and extracts for an output format,
+// keeping bold text and links in markdown output
+func wikiTextFormat(format Format) wiki.TextFormat {
+	if format == FormatMarkdown {
+		return wiki.TextMarkdown
+	}
+	return wiki.TextPlain
+}
+
+// handleWikiResolve handles wiki title resolution requests
+func (s *MCPServer) handleWikiResolve(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	title, err := request.RequireString("title")
+	if err != nil || strings.TrimSpace(title) == "" {
+		return mcp.NewToolResultError("Invalid title parameter: title must not be empty"), nil
+	}
+
+	lang, err := wiki.ParseLanguage(request.GetString("lang", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid lang parameter: %v", err)), nil
+	}
+
+	format, err := ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
+	}
+
+	s.logger.Debug("Wiki resolve request", "title", title, "lang", lang)
+
+	// Resolve the title
+	resolution, err := s.wiki.Resolve(ctx, title, lang)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to resolve title: %v", err)), nil
+	}
+
+	return s.textResult(resolution, format, "resolution"), nil
+}
+
+// handleWikiTranslateTitle handles wiki title translation requests
+func (s *MCPServer) handleWikiTranslateTitle(ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	title, err := request.RequireString("title")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid title parameter: %v", err)), nil
+	}
+
+	from, err := wiki.ParseLanguage(request.GetString("from", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid from parameter: %v", err)), nil
+	}
+
+	toCode, err := request.RequireString("to")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid to parameter: %v", err)), nil
+	}
+
+	to, err := wiki.ParseLanguage(toCode)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid to parameter: %v", err)), nil
+	}
+
+	format, err := ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
+	}
+
+	s.logger.Debug("Wiki title translation request", "title", title, "from", from, "to", to)
+
+	translated, err := s.wiki.TranslateTitle(ctx, title, from, to)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to translate title: %v", err)), nil
+	}
+
+	return s.textResult(map[string]string{
+		"title":            title,
+		"from":             string(from),
+		"to":               string(to),
+		"translated_title": translated,
+	}, format, "translation"), nil
+}
+
+// handleWikiCategoryMembers handles wiki category listing requests
+func (s *MCPServer) handleWikiCategoryMembers(ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	category, err := request.RequireString("category")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid category parameter: %v", err)), nil
+	}
+
+	// Get limit parameter (optional)
+	const (
+		defaultLimit = 100
+		maxLimit     = 2000
+	)
+	limit := request.GetInt("limit", defaultLimit)
+	if limit <= 0 || limit > maxLimit {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid limit parameter: must be between 1 and %d", maxLimit)), nil
+	}
+
+	lang, err := wiki.ParseLanguage(request.GetString("lang", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid lang parameter: %v", err)), nil
+	}
+
+	format, err := ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
+	}
+
+	s.logger.Debug("Wiki category request", "category", category, "limit", limit, "lang", lang)
+
+	// Get category members
+	members, err := s.wiki.GetCategoryMembers(ctx, category, limit, lang)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get category members: %v", err)), nil
+	}
+
+	return s.textResult(members, format, "category members"), nil
+}
+
+// handleWikiRecentChanges handles wiki recent changes requests
+func (s *MCPServer) handleWikiRecentChanges(ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	namespace := request.GetInt("namespace", 0)
+	if namespace < 0 {
+		return mcp.NewToolResultError("Invalid namespace parameter: must not be negative"), nil
+	}
+
+	// Get limit parameter (optional)
+	const (
+		defaultLimit = 25
+		maxLimit     = 500
+	)
+	limit := request.GetInt("limit", defaultLimit)
+	if limit <= 0 || limit > maxLimit {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid limit parameter: must be between 1 and %d", maxLimit)), nil
+	}
+
+	lang, err := wiki.ParseLanguage(request.GetString("lang", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid lang parameter: %v", err)), nil
+	}
+
+	format, err := ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
+	}
+
+	s.logger.Debug("Wiki recent changes request", "namespace", namespace, "limit", limit, "lang", lang)
+
+	// Get recent changes
+	changes, err := s.wiki.GetRecentChanges(ctx, namespace, limit, lang)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get recent changes: %v", err)), nil
+	}
+
+	return s.textResult(changes, format, "recent changes"), nil
 }
 
 // handleGetWallet handles wallet information requests
 func (s *MCPServer) handleGetWallet(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	apiKey, err := request.RequireString("api_key")
+	apiKey, err := s.resolveAPIKey(ctx, request)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Invalid api_key parameter: %v", err)), nil
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	s.logger.Debug("Wallet request", "api_key_length", len(apiKey))
+	lang, err := gw2api.ParseLanguage(request.GetString("lang", ""), s.defaultLang)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid lang parameter: %v", err)), nil
+	}
 
-	// Get wallet information
-	wallet, err := s.gw2API.GetWallet(ctx, apiKey)
+	format, err := ParseFormat(request.GetString("format", ""))
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get wallet: %v", err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
 	}
 
-	// Format wallet as JSON
-	walletJSON, err := json.MarshalIndent(wallet, "", "  ")
+	s.logger.Debug("Wallet request", "api_key_length", len(apiKey), "lang", lang)
+
+	// Get wallet information
+	wallet, err := s.gw2API.GetWallet(ctx, apiKey, lang)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to format wallet: %v", err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get wallet: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(string(walletJSON)), nil
+	return s.structuredResult(wallet, newWalletView(wallet), format, "wallet"), nil
 }
 
 // handleGetCurrencies handles currency information requests
@@ -65,21 +272,344 @@ func (s *MCPServer) handleGetCurrencies(ctx context.Context, request mcp.CallToo
 	// Parse optional currency IDs
 	currencyIDs := request.GetIntSlice("ids", nil)
 
-	s.logger.Debug("Currency request", "currency_ids", currencyIDs)
+	lang, err := gw2api.ParseLanguage(request.GetString("lang", ""), s.defaultLang)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid lang parameter: %v", err)), nil
+	}
+
+	format, err := ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
+	}
+
+	s.logger.Debug("Currency request", "currency_ids", currencyIDs, "lang", lang)
 
 	// Get currency information
-	currencies, err := s.gw2API.GetCurrencies(ctx, currencyIDs)
+	currencies, err := s.gw2API.GetCurrencies(ctx, currencyIDs, lang)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get currencies: %v", err)), nil
 	}
 
-	// Format currencies as JSON
-	currenciesJSON, err := json.MarshalIndent(currencies, "", "  ")
+	return s.structuredResult(currencies, currencyList(currencies), format, "currencies"), nil
+}
+
+// Amounts quoted by get_gem_exchange when none are given
+var (
+	defaultGoldAmounts = []float64{10, 100, 1000}
+	defaultGemAmounts  = []float64{100, 400, 800, 2000}
+)
+
+// maxExchangeAmounts limits the amounts quoted by one get_gem_exchange call
+const maxExchangeAmounts = 10
+
+// handleGetGemExchange handles gem exchange quote requests
+func (s *MCPServer) handleGetGemExchange(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	rawFrom, err := request.RequireString("from")
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to format currencies: %v", err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid from parameter: %v", err)), nil
 	}
+	from, err := gw2api.ParseExchangeFrom(rawFrom)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid from parameter: %v", err)), nil
+	}
+
+	amounts := defaultGemAmounts
+	if from == gw2api.ExchangeCoins {
+		amounts = defaultGoldAmounts
+	}
+	amounts = request.GetFloatSlice("amounts", amounts)
+
+	quantities, err := exchangeQuantities(from, amounts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid amounts parameter: %v", err)), nil
+	}
+
+	format, err := ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
+	}
+
+	s.logger.Debug("Gem exchange request", "from", from, "quantities", quantities)
+
+	exchange, err := s.gw2API.GetGemExchange(ctx, from, quantities)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get gem exchange: %v", err)), nil
+	}
+
+	return s.structuredResult(exchange, nil, format, "gem exchange"), nil
+}
+
+// exchangeQuantities converts get_gem_exchange amounts, in gold or gems, to the
+// quantities of the API, in copper or gems
+func exchangeQuantities(from gw2api.ExchangeFrom, amounts []float64) ([]int, error) {
+	switch {
+	case len(amounts) == 0:
+		return nil, fmt.Errorf("at least one amount is required")
+	case len(amounts) > maxExchangeAmounts:
+		return nil, fmt.Errorf("at most %d amounts can be quoted at once, got %d", maxExchangeAmounts, len(amounts))
+	}
+
+	quantities := make([]int, len(amounts))
+	for i, amount := range amounts {
+		if from == gw2api.ExchangeCoins {
+			quantities[i] = int(math.Round(amount * gw2api.CopperPerGold))
+		} else if amount == math.Trunc(amount) {
+			quantities[i] = int(amount)
+		} else {
+			return nil, fmt.Errorf("gem amounts must be whole numbers, got %v", amount)
+		}
+		if quantities[i] <= 0 {
+			return nil, fmt.Errorf("amounts must be positive, got %v", amount)
+		}
+	}
+	return quantities, nil
+}
 
-	return mcp.NewToolResultText(string(currenciesJSON)), nil
+// joinAmounts formats amounts for tool descriptions, e.g. "10, 100 and 1000"
+func joinAmounts(amounts []float64) string {
+	parts := make([]string, len(amounts))
+	for i, amount := range amounts {
+		parts[i] = strconv.FormatFloat(amount, 'f', -1, 64)
+	}
+	if len(parts) < 2 {
+		return strings.Join(parts, "")
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
+
+// Page sizes of get_tp_transactions
+const (
+	defaultTransactionPageSize = 50
+	maxTransactionPageSize     = 200
+)
+
+// handleGetTPTransactions handles trading post transaction requests
+func (s *MCPServer) handleGetTPTransactions(ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	apiKey, err := s.resolveAPIKey(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	state, err := gw2api.ParseTransactionState(request.GetString("state", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid state parameter: %v", err)), nil
+	}
+
+	types, err := gw2api.ParseTransactionTypes(request.GetString("type", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid type parameter: %v", err)), nil
+	}
+
+	since, err := parseDate(request.GetString("since", ""), false)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid since parameter: %v", err)), nil
+	}
+
+	until, err := parseDate(request.GetString("until", ""), true)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid until parameter: %v", err)), nil
+	}
+
+	page := request.GetInt("page", 1)
+	if page < 1 {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid page parameter: must be at least 1, got %d", page)), nil
+	}
+
+	pageSize := request.GetInt("page_size", defaultTransactionPageSize)
+	if pageSize < 1 || pageSize > maxTransactionPageSize {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid page_size parameter: must be between 1 and %d, got %d",
+			maxTransactionPageSize, pageSize)), nil
+	}
+
+	lang, err := gw2api.ParseLanguage(request.GetString("lang", ""), s.defaultLang)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid lang parameter: %v", err)), nil
+	}
+
+	format, err := ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
+	}
+
+	s.logger.Debug("Trading post transactions request", "api_key_length", len(apiKey), "state", state,
+		"types", types, "since", since, "until", until, "page", page, "page_size", pageSize, "lang", lang)
+
+	report, err := s.gw2API.GetTransactionReport(ctx, apiKey, gw2api.TransactionQuery{
+		Since:    since,
+		Until:    until,
+		State:    state,
+		Lang:     lang,
+		Types:    types,
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get trading post transactions: %v", err)), nil
+	}
+
+	return s.structuredResult(report, nil, format, "transactions"), nil
+}
+
+// handleGetTPDelivery handles trading post delivery box requests
+func (s *MCPServer) handleGetTPDelivery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey, err := s.resolveAPIKey(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	lang, err := gw2api.ParseLanguage(request.GetString("lang", ""), s.defaultLang)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid lang parameter: %v", err)), nil
+	}
+
+	format, err := ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
+	}
+
+	s.logger.Debug("Trading post delivery request", "api_key_length", len(apiKey), "lang", lang)
+
+	delivery, err := s.gw2API.GetDelivery(ctx, apiKey, lang)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get trading post delivery box: %v", err)), nil
+	}
+
+	return s.structuredResult(delivery, nil, format, "delivery box"), nil
+}
+
+// Flip finder defaults and bounds
+const (
+	defaultFlipMinVolume = 1000
+	defaultFlipQuantity  = 10
+	maxFlipQuantity      = 10000
+	defaultFlipLimit     = 20
+	maxFlipLimit         = 100
+)
+
+// handleFindTPFlips handles trading post flip finder requests
+func (s *MCPServer) handleFindTPFlips(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query := gw2api.FlipQuery{
+		ItemIDs:   request.GetIntSlice("item_ids", nil),
+		MinVolume: request.GetInt("min_volume", defaultFlipMinVolume),
+		MinROI:    request.GetFloat("min_roi", 0),
+		Quantity:  request.GetInt("quantity", defaultFlipQuantity),
+		Limit:     request.GetInt("limit", defaultFlipLimit),
+	}
+
+	if query.MinVolume < 0 {
+		return mcp.NewToolResultError("Invalid min_volume parameter: must not be negative"), nil
+	}
+	if query.Quantity < 1 || query.Quantity > maxFlipQuantity {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid quantity parameter: must be between 1 and %d",
+			maxFlipQuantity)), nil
+	}
+	if query.Limit < 1 || query.Limit > maxFlipLimit {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid limit parameter: must be between 1 and %d",
+			maxFlipLimit)), nil
+	}
+
+	maxCapital := request.GetFloat("max_capital", 0)
+	if maxCapital < 0 {
+		return mcp.NewToolResultError("Invalid max_capital parameter: must not be negative"), nil
+	}
+	query.MaxCapital = int(math.Round(maxCapital * gw2api.CopperPerGold))
+
+	var err error
+	if query.SortBy, err = gw2api.ParseFlipSort(request.GetString("sort_by", "")); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid sort_by parameter: %v", err)), nil
+	}
+
+	if query.Lang, err = gw2api.ParseLanguage(request.GetString("lang", ""), s.defaultLang); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid lang parameter: %v", err)), nil
+	}
+
+	format, err := ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
+	}
+
+	s.logger.Debug("Trading post flips request", "candidates", len(query.ItemIDs), "sort_by", query.SortBy)
+
+	report, err := s.gw2API.FindFlips(ctx, query)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to find trading post flips: %v", err)), nil
+	}
+
+	return s.structuredResult(report, nil, format, "trading post flips"), nil
+}
+
+// defaultPriceWindow is how far back the price history looks by default
+const defaultPriceWindow = "7d"
+
+// handleGetPriceHistory handles recorded trading post price history requests
+func (s *MCPServer) handleGetPriceHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	itemID, err := request.RequireInt("item_id")
+	if err != nil || itemID <= 0 {
+		return mcp.NewToolResultError("Invalid item_id parameter: expected a positive item ID"), nil
+	}
+
+	windowValue := request.GetString("window", defaultPriceWindow)
+	window, err := parseWindow(windowValue)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid window parameter: %v", err)), nil
+	}
+
+	lang, err := gw2api.ParseLanguage(request.GetString("lang", ""), s.defaultLang)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid lang parameter: %v", err)), nil
+	}
+
+	format, err := ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
+	}
+
+	s.logger.Debug("Price history request", "item_id", itemID, "window", window)
+
+	until := time.Now().UTC().Truncate(time.Second)
+	since := until.Add(-window)
+	samples, err := s.store.PriceSamples(ctx, itemID, since, until)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get price history: %v", err)), nil
+	}
+	if len(samples) == 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("No prices of item %d were recorded in the last %s. "+
+			"Only items on the server's price watchlist are recorded", itemID, windowValue)), nil
+	}
+
+	history := store.SummarizePrices(itemID, since, until, samples)
+
+	names, err := s.gw2API.GetItemNames(ctx, []int{itemID}, lang)
+	if err != nil {
+		s.logger.Warn("Failed to get item name", "id", itemID, "error", err)
+	}
+	history.ItemName = names[itemID]
+
+	return s.structuredResult(history, nil, format, "price history"), nil
+}
+
+// parseDate parses an optional YYYY-MM-DD or RFC 3339 date. With endOfDay, a date without
+// a time returns the start of the next day, so it can be used as an exclusive upper bound.
+func parseDate(value string, endOfDay bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		if endOfDay {
+			date = date.AddDate(0, 0, 1)
+		}
+		return date, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a date as YYYY-MM-DD or RFC 3339, got %q", value)
+	}
+	return t, nil
 }
 
 // handleCurrencyListResource handles the currency list resource
@@ -89,7 +619,7 @@ func (s *MCPServer) handleCurrencyListResource(ctx context.Context,
 	s.logger.Debug("Currency list resource request")
 
 	// Get all currencies
-	currencies, err := s.gw2API.GetCurrencies(ctx, nil)
+	currencies, err := s.gw2API.GetCurrencies(ctx, nil, s.defaultLang)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get currencies: %w", err)
 	}
@@ -108,3 +638,139 @@ func (s *MCPServer) handleCurrencyListResource(ctx context.Context,
 		},
 	}, nil
 }
+
+// handleCurrencyResource handles the gw2://currency/{id} resource template
+func (s *MCPServer) handleCurrencyResource(ctx context.Context,
+	request mcp.ReadResourceRequest,
+) ([]mcp.ResourceContents, error) {
+	id, err := templateID(request)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("Currency resource request", "id", id)
+
+	currencies, err := s.gw2API.GetCurrencies(ctx, []int{id}, s.defaultLang)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get currency: %w", err)
+	}
+
+	currency, ok := currencies[id]
+	if !ok {
+		return nil, fmt.Errorf("currency %d not found", id)
+	}
+
+	return jsonResourceContents(request.Params.URI, currency)
+}
+
+// handleItemResource handles the gw2://item/{id} resource template
+func (s *MCPServer) handleItemResource(ctx context.Context,
+	request mcp.ReadResourceRequest,
+) ([]mcp.ResourceContents, error) {
+	id, err := templateID(request)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("Item resource request", "id", id)
+
+	item, err := s.gw2API.GetItem(ctx, id, s.defaultLang)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item: %w", err)
+	}
+
+	return jsonResourceContents(request.Params.URI, item)
+}
+
+// handleRecipeResource handles the gw2://recipe/{id} resource template
+func (s *MCPServer) handleRecipeResource(ctx context.Context,
+	request mcp.ReadResourceRequest,
+) ([]mcp.ResourceContents, error) {
+	id, err := templateID(request)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("Recipe resource request", "id", id)
+
+	recipe, err := s.gw2API.GetRecipe(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recipe: %w", err)
+	}
+
+	return jsonResourceContents(request.Params.URI, recipe)
+}
+
+// handleWikiPageResource handles the wiki://page/{title} resource template
+func (s *MCPServer) handleWikiPageResource(ctx context.Context,
+	request mcp.ReadResourceRequest,
+) ([]mcp.ResourceContents, error) {
+	title, err := url.PathUnescape(templateArgument(request, "title"))
+	if err != nil || strings.TrimSpace(title) == "" {
+		return nil, fmt.Errorf("invalid wiki page title in %q", request.Params.URI)
+	}
+
+	lang := s.defaultWikiLanguage()
+
+	s.logger.Debug("Wiki page resource request", "title", title, "lang", lang)
+
+	page, err := s.wiki.GetPage(ctx, title, lang, wiki.TextPlain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wiki page: %w", err)
+	}
+
+	return jsonResourceContents(request.Params.URI, page)
+}
+
+// templateArgument returns a variable matched from a resource template URI
+func templateArgument(request mcp.ReadResourceRequest, name string) string {
+	switch value := request.Params.Arguments[name].(type) {
+	case []string:
+		if len(value) > 0 {
+			return value[0]
+		}
+	case string:
+		return value
+	}
+	return ""
+}
+
+// templateID returns the numeric {id} variable of a resource template URI
+func templateID(request mcp.ReadResourceRequest) (int, error) {
+	id, err := strconv.Atoi(templateArgument(request, "id"))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid id in %q: must be a positive integer", request.Params.URI)
+	}
+	return id, nil
+}
+
+// jsonResourceContents formats a value as the JSON contents of a resource
+func jsonResourceContents(uri string, value any) ([]mcp.ResourceContents, error) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format resource: %w", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
+
+// resolveAPIKey returns the API key of the authenticated session profile,
+// falling back to the api_key argument when the session is not authenticated
+func (s *MCPServer) resolveAPIKey(ctx context.Context, request mcp.CallToolRequest) (string, error) {
+	if profile, ok := auth.ProfileFromContext(ctx); ok {
+		return profile.APIKey, nil
+	}
+
+	apiKey, err := request.RequireString("api_key")
+	if err != nil || apiKey == "" {
+		return "", fmt.Errorf("invalid api_key parameter: an API key is required when the session is not authenticated")
+	}
+
+	return apiKey, nil
+}
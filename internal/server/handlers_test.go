@@ -0,0 +1,74 @@
+package server
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		endOfDay bool
+		want     time.Time
+		wantErr  bool
+	}{
+		{name: "empty", value: ""},
+		{name: "date", value: "2026-09-05", want: time.Date(2026, 9, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "date as upper bound", value: "2026-09-30", endOfDay: true, want: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "timestamp as upper bound", value: "2026-09-30T12:00:00Z", endOfDay: true,
+			want: time.Date(2026, 9, 30, 12, 0, 0, 0, time.UTC)},
+		{name: "invalid", value: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDate(tt.value, tt.endOfDay)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExchangeQuantities(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    gw2api.ExchangeFrom
+		amounts []float64
+		want    []int
+		wantErr string
+	}{
+		{name: "gold to copper", from: gw2api.ExchangeCoins, amounts: []float64{100, 0.5}, want: []int{1000000, 5000}},
+		{name: "gems", from: gw2api.ExchangeGems, amounts: []float64{400}, want: []int{400}},
+		{name: "fractional gems", from: gw2api.ExchangeGems, amounts: []float64{1.5}, wantErr: "whole numbers"},
+		{name: "zero", from: gw2api.ExchangeCoins, amounts: []float64{0}, wantErr: "positive"},
+		{name: "none", from: gw2api.ExchangeCoins, amounts: []float64{}, wantErr: "at least one"},
+		{name: "too many", from: gw2api.ExchangeGems, amounts: make([]float64, maxExchangeAmounts+1), wantErr: "at most"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := exchangeQuantities(tt.from, tt.amounts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("exchangeQuantities failed: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+)
+
+// handleGetLegendaryArmory handles legendary armory requests
+func (s *MCPServer) handleGetLegendaryArmory(ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	apiKey, err := s.resolveAPIKey(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	query := gw2api.ArmoryQuery{
+		Type:           strings.TrimSpace(request.GetString("type", "")),
+		IncludeMissing: request.GetBool("include_missing", false),
+	}
+
+	if query.Lang, err = gw2api.ParseLanguage(request.GetString("lang", ""), s.defaultLang); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid lang parameter: %v", err)), nil
+	}
+
+	format, err := ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
+	}
+
+	s.logger.Debug("Legendary armory request", "type", query.Type, "include_missing", query.IncludeMissing)
+
+	armory, err := s.gw2API.GetLegendaryArmory(ctx, apiKey, query)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get legendary armory: %v", err)), nil
+	}
+
+	return s.structuredResult(armory, nil, format, "legendary armory"), nil
+}
+
+// handleGetLegendaryProgress handles legendary crafting progress requests
+func (s *MCPServer) handleGetLegendaryProgress(ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	legendary, err := request.RequireString("legendary")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid legendary parameter: %v", err)), nil
+	}
+
+	apiKey, err := s.resolveAPIKey(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	lang, err := gw2api.ParseLanguage(request.GetString("lang", ""), s.defaultLang)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid lang parameter: %v", err)), nil
+	}
+
+	format, err := ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
+	}
+
+	id, err := s.gw2API.FindLegendary(ctx, legendary, lang)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid legendary parameter: %v", err)), nil
+	}
+
+	s.logger.Debug("Legendary progress request", "legendary", legendary, "id", id, "lang", lang)
+
+	result, err := s.gw2API.GetLegendaryProgress(ctx, apiKey, id, lang)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get legendary progress: %v", err)), nil
+	}
+
+	return s.structuredResult(result, nil, format, "legendary progress"), nil
+}
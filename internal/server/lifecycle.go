@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultShutdownTimeout bounds how long in-flight tool calls may take to finish on shutdown
+const DefaultShutdownTimeout = 15 * time.Second
+
+// errShutdownTimeout is returned when in-flight tool calls did not finish before the deadline
+var errShutdownTimeout = errors.New("timed out waiting for in-flight tool calls")
+
+// WithShutdownTimeout sets how long in-flight tool calls may take to finish on shutdown
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(s *MCPServer) {
+		s.shutdownTimeout = timeout
+	}
+}
+
+// WithCacheFile persists the cache to path across restarts
+func WithCacheFile(path string) Option {
+	return func(s *MCPServer) {
+		s.cacheFile = path
+	}
+}
+
+// trackInFlight is a tool middleware that counts running tool calls and
+// rejects new ones once the server started shutting down
+func (s *MCPServer) trackInFlight(next mcpserver.ToolHandlerFunc) mcpserver.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.lifecycleMu.Lock()
+		if s.draining {
+			s.lifecycleMu.Unlock()
+			return mcp.NewToolResultError("Server is shutting down, please retry later"), nil
+		}
+		s.inFlight.Add(1)
+		s.lifecycleMu.Unlock()
+
+		defer s.inFlight.Done()
+		return next(ctx, request)
+	}
+}
+
+// drain stops accepting tool calls and waits for the running ones until the deadline
+func (s *MCPServer) drain(deadline time.Time) error {
+	s.lifecycleMu.Lock()
+	s.draining = true
+	s.lifecycleMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(time.Until(deadline)):
+		return errShutdownTimeout
+	}
+}
+
+// shutdown drains in-flight tool calls, stops the transport and closes the cache and the database.
+// stopTransport receives a context expiring at the shutdown deadline.
+func (s *MCPServer) shutdown(stopTransport func(ctx context.Context) error) error {
+	s.logger.Info("Server shutdown requested, draining in-flight tool calls", "timeout", s.shutdownTimeout)
+
+	deadline := time.Now().Add(s.shutdownTimeout)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	var errs []error
+
+	if err := s.drain(deadline); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := stopTransport(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to stop transport: %w", err))
+	}
+
+	// Stop background work before closing the cache and the database it uses. The context
+	// given to Start is not done when stdin reached EOF or the HTTP listener failed.
+	if s.stopBackground != nil {
+		s.stopBackground()
+	}
+	s.background.Wait()
+
+	if err := s.cache.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close cache: %w", err))
+	}
+
+	if s.store != nil {
+		if err := s.store.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close database: %w", err))
+		}
+	}
+
+	if len(errs) == 0 {
+		s.logger.Info("Server stopped cleanly")
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+	"github.com/AlyxPink/gw2-mcp/internal/gw2mock"
+)
+
+func TestLifecycle_DrainWaitsForInFlightCalls(t *testing.T) {
+	s, err := NewMCPServer(log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := s.trackInFlight(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		<-release
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	go func() {
+		_, _ = handler(context.Background(), mcp.CallToolRequest{})
+	}()
+	<-started
+
+	drained := make(chan error, 1)
+	go func() {
+		drained <- s.drain(time.Now().Add(5 * time.Second))
+	}()
+
+	select {
+	case <-drained:
+		t.Fatal("Drain returned while a tool call was still running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// New calls are rejected while draining
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil || !result.IsError {
+		t.Errorf("Expected tool error while draining, got %+v, %v", result, err)
+	}
+
+	close(release)
+
+	select {
+	case err := <-drained:
+		if err != nil {
+			t.Errorf("Expected clean drain, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Drain did not return after the tool call finished")
+	}
+}
+
+func TestLifecycle_DrainTimeout(t *testing.T) {
+	s, err := NewMCPServer(log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+
+	started := make(chan struct{})
+	handler := s.trackInFlight(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		<-release
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	go func() {
+		_, _ = handler(context.Background(), mcp.CallToolRequest{})
+	}()
+	<-started
+
+	if err := s.drain(time.Now().Add(20 * time.Millisecond)); err != errShutdownTimeout {
+		t.Errorf("Expected errShutdownTimeout, got %v", err)
+	}
+}
+
+func TestLifecycle_StdinEOFStopsBackgroundWork(t *testing.T) {
+	mock := httptest.NewServer(gw2mock.New())
+	defer mock.Close()
+
+	s, err := NewMCPServer(log.New(io.Discard),
+		WithGW2APIOptions(gw2api.WithBaseURL(mock.URL+"/v2")),
+		WithDatabase(filepath.Join(t.TempDir(), "gw2-mcp.db")),
+		WithPriceWatchlist([]int{19721}, time.Hour),
+		WithItemIndex(true),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	// The client closed stdin, while the context given to Start is never cancelled
+	s.stdin = strings.NewReader("")
+	s.stdout = io.Discard
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- s.Start(context.Background())
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start did not return after stdin was closed")
+	}
+}
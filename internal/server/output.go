@@ -0,0 +1,22 @@
+package server
+
+import (
+	"time"
+
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+)
+
+// Output schemas are generated from Go types. The API types key currencies by integer
+// IDs, which JSON encodes as object keys but schema generation cannot describe, so the
+// types below mirror them with string keys. They are only used to declare schemas.
+
+// walletSchema describes the structured output of get_wallet
+type walletSchema struct {
+	UpdatedAt  time.Time                  `json:"updated_at"`
+	Currencies map[string]gw2api.Currency `json:"currencies"`
+	Entries    []gw2api.WalletEntry       `json:"entries"`
+	Total      int                        `json:"total_currencies"`
+}
+
+// currenciesSchema describes the structured output of get_currencies, keyed by currency ID
+type currenciesSchema map[string]gw2api.Currency
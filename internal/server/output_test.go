@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+	"github.com/AlyxPink/gw2-mcp/internal/wiki"
+)
+
+func TestStructuredOutput(t *testing.T) {
+	gw2Server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/account/wallet":
+			_, _ = w.Write([]byte(`[{"id":1,"value":123456},{"id":4,"value":800}]`))
+		case "/currencies":
+			_, _ = w.Write([]byte(`[{"id":1,"name":"Coin","description":"Gold","order":101,"icon":"coin.png"},` +
+				`{"id":4,"name":"Gem","description":"Purchased","order":80,"icon":"gem.png"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer gw2Server.Close()
+
+	wikiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("list") == "search" {
+			_, _ = w.Write([]byte(`{"query":{"search":[{"title":"Mystic Coin","pageid":1,"snippet":"A coin"}]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"query":{"pages":{"1":{"pageid":1,"title":"Mystic Coin","extract":"A coin."}}}}`))
+	}))
+	defer wikiServer.Close()
+
+	s, err := NewMCPServer(log.New(io.Discard),
+		WithGW2APIOptions(gw2api.WithBaseURL(gw2Server.URL)),
+		WithWikiOptions(wiki.WithBaseURL(wiki.LanguageEnglish, wikiServer.URL)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	mcpClient, err := client.NewInProcessClient(s.mcp)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer func() { _ = mcpClient.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	if _, err := mcpClient.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	tools, err := mcpClient.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("Failed to list tools: %v", err)
+	}
+	schemas := make(map[string]mcp.ToolOutputSchema)
+	for _, tool := range tools.Tools {
+		schemas[tool.Name] = tool.OutputSchema
+	}
+
+	tests := []struct {
+		tool      string
+		arguments map[string]any
+	}{
+		{tool: "wiki_search", arguments: map[string]any{"query": "mystic coin"}},
+		{tool: "get_wallet", arguments: map[string]any{"api_key": "KEY"}},
+		{tool: "get_currencies", arguments: map[string]any{"ids": []int{1, 4}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			schema, ok := schemas[tt.tool]
+			if !ok || schema.Type != "object" {
+				t.Fatalf("Expected %s to declare an object output schema, got %+v", tt.tool, schema)
+			}
+
+			callRequest := mcp.CallToolRequest{}
+			callRequest.Params.Name = tt.tool
+			callRequest.Params.Arguments = tt.arguments
+			result, err := mcpClient.CallTool(ctx, callRequest)
+			if err != nil {
+				t.Fatalf("Failed to call %s: %v", tt.tool, err)
+			}
+			if result.IsError {
+				t.Fatalf("%s returned an error: %v", tt.tool, result.Content)
+			}
+
+			structured := toJSONValue(t, result.StructuredContent)
+			validateAgainstSchema(t, schema, structured)
+
+			// The text content mirrors the structured content for older clients
+			text, ok := result.Content[0].(mcp.TextContent)
+			if !ok {
+				t.Fatalf("Expected text fallback, got %T", result.Content[0])
+			}
+			var fallback any
+			if err := json.Unmarshal([]byte(text.Text), &fallback); err != nil {
+				t.Fatalf("Text fallback is not JSON: %v", err)
+			}
+			if !reflect.DeepEqual(fallback, structured) {
+				t.Error("Expected the text fallback to match the structured content")
+			}
+		})
+	}
+}
+
+// toJSONValue converts a value to its generic JSON representation
+func toJSONValue(t *testing.T, value any) any {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	return generic
+}
+
+// validateAgainstSchema checks a JSON value against a declared output schema
+func validateAgainstSchema(t *testing.T, outputSchema mcp.ToolOutputSchema, value any) {
+	t.Helper()
+
+	data, err := json.Marshal(outputSchema)
+	if err != nil {
+		t.Fatalf("Failed to marshal schema: %v", err)
+	}
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		t.Fatalf("Failed to resolve schema: %v", err)
+	}
+	if err := resolved.Validate(value); err != nil {
+		t.Errorf("Structured content does not match the output schema: %v\nschema: %s", err, data)
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/AlyxPink/gw2-mcp/internal/progress"
+)
+
+// trackProgress forwards progress reports of a tool call to the client as MCP progress
+// notifications, when the client asked for them with a progress token
+func (s *MCPServer) trackProgress(next mcpserver.ToolHandlerFunc) mcpserver.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+			return next(ctx, request)
+		}
+
+		notifier := &progressNotifier{
+			server: mcpserver.ServerFromContext(ctx),
+			token:  request.Params.Meta.ProgressToken,
+			ctx:    ctx,
+			logger: s.logger,
+		}
+		return next(progress.WithReporter(ctx, notifier.notify), request)
+	}
+}
+
+// progressNotifier sends the progress of one tool call to its client
+type progressNotifier struct {
+	ctx    context.Context
+	server *mcpserver.MCPServer
+	token  mcp.ProgressToken
+	logger *log.Logger
+	mu     sync.Mutex
+	last   float64
+}
+
+// notify sends a progress notification. Progress must increase with each notification,
+// so updates that do not move forward are dropped.
+func (n *progressNotifier) notify(done, total float64, message string) {
+	if n.server == nil {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if done <= n.last {
+		return
+	}
+	n.last = done
+
+	params := map[string]any{
+		"progressToken": n.token,
+		"progress":      done,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+
+	if err := n.server.SendNotificationToClient(n.ctx, "notifications/progress", params); err != nil {
+		n.logger.Debug("Failed to send progress notification", "error", err)
+	}
+}
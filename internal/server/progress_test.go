@@ -0,0 +1,226 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+	"github.com/AlyxPink/gw2-mcp/internal/gw2mock"
+	"github.com/AlyxPink/gw2-mcp/internal/wiki"
+)
+
+func TestProgressNotifications(t *testing.T) {
+	// Two pages of category members, linked by a continuation token
+	wikiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cmcontinue") == "" {
+			_, _ = w.Write([]byte(`{"continue":{"cmcontinue":"page|2","continue":"-||"},` +
+				`"query":{"categorymembers":[{"pageid":1,"ns":0,"title":"Twilight"}]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"query":{"categorymembers":[{"pageid":2,"ns":0,"title":"Sunrise"}]}}`))
+	}))
+	defer wikiServer.Close()
+
+	s, err := NewMCPServer(log.New(io.Discard),
+		WithTransport(TransportStreamableHTTP),
+		WithWikiOptions(wiki.WithBaseURL(wiki.LanguageEnglish, wikiServer.URL)))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	// The in-process transport does not deliver server notifications, so go through HTTP
+	httpTransport, err := s.newHTTPTransport(nil)
+	if err != nil {
+		t.Fatalf("Failed to create HTTP transport: %v", err)
+	}
+	httpServer := httptest.NewServer(s.httpHandler(httpTransport))
+	defer httpServer.Close()
+
+	mcpClient, err := client.NewStreamableHttpClient(httpServer.URL + "/mcp")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer func() { _ = mcpClient.Close() }()
+
+	var mu sync.Mutex
+	var notifications []mcp.JSONRPCNotification
+	mcpClient.OnNotification(func(notification mcp.JSONRPCNotification) {
+		if notification.Method == "notifications/progress" {
+			mu.Lock()
+			notifications = append(notifications, notification)
+			mu.Unlock()
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := mcpClient.Start(ctx); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	if _, err := mcpClient.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	callRequest := mcp.CallToolRequest{}
+	callRequest.Params.Name = "wiki_category_members"
+	callRequest.Params.Arguments = map[string]any{"category": "Legendary weapons"}
+	callRequest.Params.Meta = &mcp.Meta{ProgressToken: "members"}
+
+	result, err := mcpClient.CallTool(ctx, callRequest)
+	if err != nil {
+		t.Fatalf("Failed to call tool: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Tool returned an error: %v", result.Content)
+	}
+
+	// Notifications are delivered asynchronously. The transport may drop one racing with
+	// the final response, so only the first is guaranteed.
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		count := len(notifications)
+		mu.Unlock()
+		if count > 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(notifications) == 0 || len(notifications) > 2 {
+		t.Fatalf("Expected 1 or 2 progress notifications, got %d", len(notifications))
+	}
+
+	for i, notification := range notifications {
+		fields := notification.Params.AdditionalFields
+		if fields["progressToken"] != "members" {
+			t.Errorf("Expected progress token 'members', got %v", fields["progressToken"])
+		}
+		if progress, ok := fields["progress"].(float64); !ok || int(progress) != i+1 {
+			t.Errorf("Expected progress %d, got %v", i+1, fields["progress"])
+		}
+		if fields["message"] == "" {
+			t.Error("Expected a progress message")
+		}
+	}
+}
+
+// recordingSession is a client session keeping the notifications sent to it
+type recordingSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (r *recordingSession) Initialize()       {}
+func (r *recordingSession) Initialized() bool { return true }
+func (r *recordingSession) SessionID() string { return "recording" }
+func (r *recordingSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return r.notifications
+}
+
+func TestProgressNotifications_NestedSteps(t *testing.T) {
+	mock := httptest.NewServer(gw2mock.New())
+	defer mock.Close()
+
+	s, err := NewMCPServer(log.New(io.Discard),
+		WithGW2APIOptions(gw2api.WithBaseURL(mock.URL+"/v2")),
+		WithDatabase(filepath.Join(t.TempDir(), "gw2-mcp.db")),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	t.Cleanup(func() { _ = s.store.Close() })
+
+	// Handle the call directly with a session of our own, as transports may drop
+	// notifications racing with the response
+	session := &recordingSession{notifications: make(chan mcp.JSONRPCNotification, 64)}
+	if err := s.mcp.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("Failed to register session: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(s.mcp.WithContext(context.Background(), session), 5*time.Second)
+	defer cancel()
+
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":      "snapshot_account",
+			"arguments": map[string]any{"api_key": gw2mock.KeyFull},
+			"_meta":     map[string]any{"progressToken": "snapshot"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode request: %v", err)
+	}
+	response, ok := s.mcp.HandleMessage(ctx, message).(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatal("Expected a successful response")
+	}
+	if result, ok := response.Result.(*mcp.CallToolResult); !ok || result.IsError {
+		t.Fatalf("Expected a tool result, got %+v", response.Result)
+	}
+	close(session.notifications)
+
+	// Steps of the valuation, with the characters and buy orders within theirs. The
+	// last page of orders and the only batch of prices end with their step.
+	want := []string{
+		"1/6 fetched account",
+		"2/6 fetched wallet",
+		"3/6 fetched bank, materials and shared inventory",
+		"3.5/6 fetched 1/2 characters",
+		"4/6 fetched inventories of 2 characters",
+		"4.5/6 fetched buy orders",
+		"5/6 fetched trading post orders and delivery box",
+		"6/6 fetched prices of 5 items",
+	}
+	var got []string
+	for notification := range session.notifications {
+		fields := notification.Params.AdditionalFields
+		if notification.Method != "notifications/progress" || fields["progressToken"] != "snapshot" {
+			t.Errorf("Unexpected notification %+v", notification)
+		}
+		got = append(got, fmt.Sprintf("%v/%v %v", fields["progress"], fields["total"], fields["message"]))
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected notifications\n%v\ngot\n%v", want, got)
+	}
+}
+
+func TestProgressNotifications_WithoutToken(t *testing.T) {
+	s, err := NewMCPServer(log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	called := false
+	handler := s.trackProgress(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	if _, err := handler(context.Background(), mcp.CallToolRequest{}); err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if !called {
+		t.Error("Expected the wrapped handler to be called")
+	}
+}
@@ -0,0 +1,242 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/auth"
+)
+
+// Prompt names
+const (
+	promptDailyGoldFarm     = "daily_gold_farm"
+	promptExplainBuild      = "explain_build"
+	promptCraftForProfit    = "craft_for_profit"
+	promptLegendaryProgress = "legendary_progress"
+)
+
+// registerPrompts registers the prompts for common GW2 workflows
+func (s *MCPServer) registerPrompts() {
+	s.mcp.AddPrompt(mcp.NewPrompt(promptDailyGoldFarm,
+		mcp.WithPromptDescription("Plan a daily gold farming routine that fits the available play time"),
+		mcp.WithArgument("playtime",
+			mcp.ArgumentDescription("Play time available today, e.g. '1 hour' (default: about an hour)"),
+		),
+		profileArgument(),
+	), s.handleDailyGoldFarmPrompt)
+
+	s.mcp.AddPrompt(mcp.NewPrompt(promptExplainBuild,
+		mcp.WithPromptDescription("Explain how a build works: its role, key skills and traits, and how to play it"),
+		mcp.WithArgument("build",
+			mcp.ArgumentDescription("Build template chat code, build site link or a description of the build"),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("profession",
+			mcp.ArgumentDescription("Profession or elite specialization, if not obvious from the build"),
+		),
+		mcp.WithArgument("character",
+			mcp.ArgumentDescription("Character playing the build, to tailor the explanation"),
+		),
+	), s.handleExplainBuildPrompt)
+
+	s.mcp.AddPrompt(mcp.NewPrompt(promptCraftForProfit,
+		mcp.WithPromptDescription("Find items worth crafting for profit with the player's disciplines and budget"),
+		mcp.WithArgument("discipline",
+			mcp.ArgumentDescription("Crafting discipline to focus on, e.g. Weaponsmith (default: all)"),
+		),
+		mcp.WithArgument("budget",
+			mcp.ArgumentDescription("Maximum gold to invest in materials, e.g. '50 gold'"),
+		),
+		profileArgument(),
+	), s.handleCraftForProfitPrompt)
+
+	s.mcp.AddPrompt(mcp.NewPrompt(promptLegendaryProgress,
+		mcp.WithPromptDescription("Report progress towards crafting a legendary item and list what is still missing"),
+		mcp.WithArgument("target_item",
+			mcp.ArgumentDescription("Legendary item to work towards, e.g. Twilight or Aurora"),
+			mcp.RequiredArgument(),
+		),
+		profileArgument(),
+	), s.handleLegendaryProgressPrompt)
+}
+
+// profileArgument returns the optional account profile argument shared by account based prompts
+func profileArgument() mcp.PromptOption {
+	return mcp.WithArgument("profile",
+		mcp.ArgumentDescription("Account profile to use; authenticated sessions use their own profile"),
+	)
+}
+
+// handleDailyGoldFarmPrompt expands the daily gold farm prompt
+func (s *MCPServer) handleDailyGoldFarmPrompt(ctx context.Context,
+	request mcp.GetPromptRequest,
+) (*mcp.GetPromptResult, error) {
+	account, err := accountInstructions(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	playtime := promptArgument(request, "playtime", "about an hour")
+
+	return promptResult("Daily gold farm plan",
+		fmt.Sprintf("Plan a Guild Wars 2 gold farming routine for today. I have %s to play.", playtime),
+		"",
+		account,
+		"",
+		"Steps:",
+		"1. Call get_wallet to see my current gold, karma and other currencies. Use get_currencies to explain "+
+			"any currency you are unsure about.",
+		"2. Call wiki_recent_changes and look for recent game updates that changed farms, events or rewards.",
+		"3. Use wiki_search and wiki_page to check the farms, daily activities and meta events you want to "+
+			"recommend, and cite their wiki URLs.",
+		"",
+		"Answer with an ordered plan that fits the play time: each activity with its expected duration, what it "+
+			"rewards and why it suits my wallet. Base every claim on tool results; say so when data is missing "+
+			"instead of guessing gold per hour figures.",
+	), nil
+}
+
+// handleExplainBuildPrompt expands the explain build prompt
+func (s *MCPServer) handleExplainBuildPrompt(_ context.Context,
+	request mcp.GetPromptRequest,
+) (*mcp.GetPromptResult, error) {
+	build, err := requirePromptArgument(request, "build")
+	if err != nil {
+		return nil, err
+	}
+
+	lines := []string{
+		"Explain this Guild Wars 2 build:",
+		"",
+		build,
+		"",
+	}
+	if profession := promptArgument(request, "profession", ""); profession != "" {
+		lines = append(lines, "Profession: "+profession, "")
+	}
+	if character := promptArgument(request, "character", ""); character != "" {
+		lines = append(lines, "I play it on my character "+character+".", "")
+	}
+	lines = append(lines,
+		"Steps:",
+		"1. Identify the profession, elite specialization, skills and traits. Use wiki_resolve on each name, "+
+			"since names in build descriptions are often abbreviated or misspelled.",
+		"2. Read the relevant pages with wiki_page to learn what each skill and trait actually does.",
+		"",
+		"Then explain the role of the build (damage, support, healing or hybrid), the key synergies between its "+
+			"traits and skills, a basic rotation or skill priority, and the game modes it suits. Link the wiki "+
+			"pages you relied on.",
+	)
+
+	return promptResult("Build explanation", lines...), nil
+}
+
+// handleCraftForProfitPrompt expands the craft for profit prompt
+func (s *MCPServer) handleCraftForProfitPrompt(ctx context.Context,
+	request mcp.GetPromptRequest,
+) (*mcp.GetPromptResult, error) {
+	account, err := accountInstructions(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	discipline := promptArgument(request, "discipline", "any discipline")
+	budget := promptArgument(request, "budget", "no fixed budget")
+
+	return promptResult("Profitable crafting",
+		fmt.Sprintf("Find Guild Wars 2 items worth crafting for profit. Discipline: %s. Budget: %s.", discipline, budget),
+		"",
+		account,
+		"",
+		"Steps:",
+		"1. Call get_wallet to check how much gold I can invest.",
+		"2. Use wiki_category_members on the recipe categories of the discipline, e.g. "+
+			"'Weaponsmith recipes', to collect candidate items.",
+		"3. Read candidate recipes through the gw2://recipe/{id} and gw2://item/{id} resources, or wiki_page, "+
+			"to list their ingredients.",
+		"",
+		"Rank the candidates by expected profit after the 15% trading post fees and list the materials to buy. "+
+			"If current trading post prices are not available from the tools, say so and explain how to verify "+
+			"the margins instead of inventing prices.",
+	), nil
+}
+
+// handleLegendaryProgressPrompt expands the legendary progress prompt
+func (s *MCPServer) handleLegendaryProgressPrompt(ctx context.Context,
+	request mcp.GetPromptRequest,
+) (*mcp.GetPromptResult, error) {
+	target, err := requirePromptArgument(request, "target_item")
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := accountInstructions(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return promptResult("Legendary progress report",
+		fmt.Sprintf("Write a progress report for crafting the legendary %q in Guild Wars 2.", target),
+		"",
+		account,
+		"",
+		"Steps:",
+		"1. Call get_legendary_armory to check whether the legendary is already unlocked.",
+		"2. Call get_legendary_progress with the legendary to get the components the account already holds, "+
+			"what is missing and its estimated cost on the trading post.",
+		"3. If get_legendary_progress does not know the legendary, call wiki_resolve to find its exact page, "+
+			"then wiki_page to read its recipe and its precursor, gifts and other components, and get_wallet "+
+			"to check the currencies the recipe needs, such as spirit shards, mystic coins or karma.",
+		"",
+		"Report each component as done, partially done or missing, with the remaining quantities and the "+
+			"estimated cost, then suggest the next three concrete steps. Only mark a component as done when the "+
+			"tool results show it.",
+	), nil
+}
+
+// accountInstructions describes which account the account based tools act on
+func accountInstructions(ctx context.Context, request mcp.GetPromptRequest) (string, error) {
+	requested := promptArgument(request, "profile", "")
+
+	if profile, ok := auth.ProfileFromContext(ctx); ok {
+		if requested != "" && requested != profile.Name {
+			return "", fmt.Errorf("profile %q is not available in this session", requested)
+		}
+		return fmt.Sprintf("Account tools such as get_wallet use the API key of my profile %q automatically.",
+			profile.Name), nil
+	}
+
+	if requested != "" {
+		return fmt.Sprintf("Account tools such as get_wallet need the API key of my profile %q; "+
+			"ask me for it if you do not have it.", requested), nil
+	}
+
+	return "Account tools such as get_wallet need my GW2 API key; ask me for it if you do not have it.", nil
+}
+
+// promptArgument returns a trimmed prompt argument or its default value
+func promptArgument(request mcp.GetPromptRequest, name, defaultValue string) string {
+	if value := strings.TrimSpace(request.Params.Arguments[name]); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// requirePromptArgument returns a prompt argument that must be provided
+func requirePromptArgument(request mcp.GetPromptRequest, name string) (string, error) {
+	value := promptArgument(request, name, "")
+	if value == "" {
+		return "", fmt.Errorf("missing required argument %q", name)
+	}
+	return value, nil
+}
+
+// promptResult builds a single user message prompt from lines of text
+func promptResult(description string, lines ...string) *mcp.GetPromptResult {
+	return mcp.NewGetPromptResult(description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(strings.Join(lines, "\n"))),
+	})
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/auth"
+)
+
+func TestPrompts(t *testing.T) {
+	s, err := NewMCPServer(log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	mcpClient, err := client.NewInProcessClient(s.mcp)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer func() { _ = mcpClient.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	if _, err := mcpClient.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	prompts, err := mcpClient.ListPrompts(ctx, mcp.ListPromptsRequest{})
+	if err != nil {
+		t.Fatalf("Failed to list prompts: %v", err)
+	}
+	if len(prompts.Prompts) != 4 {
+		t.Errorf("Expected 4 prompts, got %d", len(prompts.Prompts))
+	}
+
+	tests := []struct {
+		name      string
+		prompt    string
+		arguments map[string]string
+		contains  []string
+		wantErr   bool
+	}{
+		{
+			name:      "gold farm defaults",
+			prompt:    promptDailyGoldFarm,
+			arguments: map[string]string{},
+			contains:  []string{"about an hour", "get_wallet", "wiki_recent_changes", "ask me for it"},
+		},
+		{
+			name:      "explain build",
+			prompt:    promptExplainBuild,
+			arguments: map[string]string{"build": "[&DQEQGzEvPjZLFwAA]", "profession": "Guardian"},
+			contains:  []string{"[&DQEQGzEvPjZLFwAA]", "Profession: Guardian", "wiki_resolve"},
+		},
+		{
+			name:      "craft for profit",
+			prompt:    promptCraftForProfit,
+			arguments: map[string]string{"discipline": "Weaponsmith", "budget": "50 gold", "profile": "alt"},
+			contains:  []string{"Discipline: Weaponsmith", "Budget: 50 gold", `profile "alt"`, "gw2://recipe/{id}"},
+		},
+		{
+			name:      "legendary progress",
+			prompt:    promptLegendaryProgress,
+			arguments: map[string]string{"target_item": "Twilight"},
+			contains:  []string{`"Twilight"`, "get_legendary_progress", "wiki_resolve", "get_wallet"},
+		},
+		{
+			name:      "missing required argument",
+			prompt:    promptLegendaryProgress,
+			arguments: map[string]string{"target_item": "  "},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.GetPromptRequest{}
+			request.Params.Name = tt.prompt
+			request.Params.Arguments = tt.arguments
+
+			result, err := mcpClient.GetPrompt(ctx, request)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to get prompt: %v", err)
+			}
+
+			if len(result.Messages) != 1 {
+				t.Fatalf("Expected 1 message, got %d", len(result.Messages))
+			}
+			text, ok := result.Messages[0].Content.(mcp.TextContent)
+			if !ok {
+				t.Fatalf("Expected text content, got %T", result.Messages[0].Content)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(text.Text, want) {
+					t.Errorf("Expected prompt to contain %q, got:\n%s", want, text.Text)
+				}
+			}
+		})
+	}
+}
+
+func TestAccountInstructions_SessionProfile(t *testing.T) {
+	ctx := auth.WithProfile(context.Background(), &auth.Profile{Name: "alice", APIKey: "ALICE-KEY"})
+
+	request := mcp.GetPromptRequest{}
+	text, err := accountInstructions(ctx, request)
+	if err != nil {
+		t.Fatalf("accountInstructions failed: %v", err)
+	}
+	if !strings.Contains(text, `"alice"`) || strings.Contains(text, "ALICE-KEY") {
+		t.Errorf("Expected the profile name without its API key, got %q", text)
+	}
+
+	request.Params.Arguments = map[string]string{"profile": "bob"}
+	if _, err := accountInstructions(ctx, request); err == nil {
+		t.Error("Expected error when requesting another profile than the session's")
+	}
+}
@@ -0,0 +1,473 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+)
+
+// Format selects how tool results are rendered as text
+type Format string
+
+const (
+	// FormatJSON renders results as indented JSON with every field
+	FormatJSON Format = "json"
+	// FormatCompact renders results as single-line JSON without icons, descriptions or empty fields
+	FormatCompact Format = "compact"
+	// FormatMarkdown renders results as markdown lists and tables without icons or descriptions
+	FormatMarkdown Format = "markdown"
+)
+
+// DefaultMaxOutputSize is the default maximum size in bytes of a tool result's text
+const DefaultMaxOutputSize = 20000
+
+// omittedFields are dropped from compact and markdown renderings, as they rarely help
+// answering a question but make up most of the size of GW2 API metadata
+var omittedFields = map[string]bool{
+	"icon":        true,
+	"description": true,
+}
+
+// Formats returns all supported output formats
+func Formats() []Format {
+	return []Format{FormatJSON, FormatCompact, FormatMarkdown}
+}
+
+// ParseFormat parses an output format, defaulting to JSON when empty
+func ParseFormat(format string) (Format, error) {
+	if format == "" {
+		return FormatJSON, nil
+	}
+
+	for _, f := range Formats() {
+		if Format(strings.ToLower(format)) == f {
+			return f, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported format %q: must be json, compact or markdown", format)
+}
+
+// WithMaxOutputSize sets the maximum size in bytes of a tool result's text, beyond which
+// it is truncated with a notice. Zero disables the limit.
+func WithMaxOutputSize(size int) Option {
+	return func(s *MCPServer) {
+		s.maxOutputSize = size
+	}
+}
+
+// formatParam returns the optional output format parameter shared by all tools
+func formatParam() mcp.ToolOption {
+	formats := Formats()
+	codes := make([]string, len(formats))
+	for i, f := range formats {
+		codes[i] = string(f)
+	}
+
+	return mcp.WithString(
+		"format",
+		mcp.Description("Output format: json (default, every field), compact (single-line JSON without "+
+			"icons and descriptions) or markdown (tables, fewest tokens)"),
+		mcp.Enum(codes...),
+	)
+}
+
+// textResult returns a tool result with value rendered as text in the given format
+func (s *MCPServer) textResult(value any, format Format, what string) *mcp.CallToolResult {
+	text, err := s.render(value, format)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to format %s: %v", what, err))
+	}
+
+	return mcp.NewToolResultText(text)
+}
+
+// structuredResult returns a tool result carrying value as structured content, as tools
+// declaring an output schema must, with a rendering as text for clients that do not read
+// structured content. A non-nil view replaces value in compact and markdown renderings.
+func (s *MCPServer) structuredResult(value, view any, format Format, what string) *mcp.CallToolResult {
+	if view == nil || format == FormatJSON {
+		view = value
+	}
+
+	text, err := s.render(view, format)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to format %s: %v", what, err))
+	}
+
+	return mcp.NewToolResultStructured(value, text)
+}
+
+// render renders value as text in the given format, truncated to the maximum output size
+func (s *MCPServer) render(value any, format Format) (string, error) {
+	if format == FormatJSON {
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return truncateOutput(string(data), s.maxOutputSize), nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	node, err := decodeOrdered(data)
+	if err != nil {
+		return "", err
+	}
+	node = pruneNode(node)
+
+	var text string
+	switch format {
+	case FormatCompact:
+		compact, err := json.Marshal(node)
+		if err != nil {
+			return "", err
+		}
+		text = string(compact)
+	case FormatMarkdown:
+		var b strings.Builder
+		writeMarkdown(&b, node, 2)
+		text = strings.TrimSpace(b.String())
+	default:
+		return "", fmt.Errorf("unsupported format %q", format)
+	}
+
+	return truncateOutput(text, s.maxOutputSize), nil
+}
+
+// truncateOutput cuts text to at most maxSize bytes, preferably at a line break, and
+// appends a notice telling how much was left out
+func truncateOutput(text string, maxSize int) string {
+	if maxSize <= 0 || len(text) <= maxSize {
+		return text
+	}
+
+	cut := maxSize
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	if newline := strings.LastIndexByte(text[:cut], '\n'); newline > maxSize/2 {
+		cut = newline
+	}
+
+	return fmt.Sprintf("%s\n\n[Output truncated: showing %d of %d bytes. Request fewer results, "+
+		"specific IDs or format=markdown to see more.]", text[:cut], cut, len(text))
+}
+
+// Compact and markdown renderings work on a generic JSON tree rather than Go types, so
+// any result can be rendered. Objects keep their field order so that rendered columns
+// follow the struct definitions.
+
+// jsonField is a field of a JSON object
+type jsonField struct {
+	Key   string
+	Value any
+}
+
+// jsonObject is a JSON object preserving the order of its fields
+type jsonObject []jsonField
+
+// MarshalJSON encodes the object with its fields in order
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(field.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// decodeOrdered decodes JSON into jsonObject, []any and scalar values
+func decodeOrdered(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeNode(dec)
+}
+
+// decodeNode decodes the next JSON value from dec
+func decodeNode(dec *json.Decoder) (any, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		object := jsonObject{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, jsonField{Key: key.(string), Value: value})
+		}
+		_, err = dec.Token()
+		return object, err
+	case '[':
+		array := []any{}
+		for dec.More() {
+			value, err := decodeNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err = dec.Token()
+		return array, err
+	default:
+		return nil, fmt.Errorf("unexpected delimiter %q", delim)
+	}
+}
+
+// pruneNode drops omitted fields as well as null, empty string and empty container
+// values, returning nil when nothing is left
+func pruneNode(node any) any {
+	switch value := node.(type) {
+	case jsonObject:
+		var pruned jsonObject
+		for _, field := range value {
+			if omittedFields[field.Key] {
+				continue
+			}
+			if fieldValue := pruneNode(field.Value); fieldValue != nil {
+				pruned = append(pruned, jsonField{Key: field.Key, Value: fieldValue})
+			}
+		}
+		if len(pruned) == 0 {
+			return nil
+		}
+		return pruned
+	case []any:
+		var pruned []any
+		for _, element := range value {
+			if element = pruneNode(element); element != nil {
+				pruned = append(pruned, element)
+			}
+		}
+		if len(pruned) == 0 {
+			return nil
+		}
+		return pruned
+	case string:
+		if value == "" {
+			return nil
+		}
+	}
+	return node
+}
+
+// writeMarkdown renders a JSON tree as markdown: scalar fields as a bullet list, lists
+// of objects as tables and nested objects as sections with headings of the given level
+func writeMarkdown(b *strings.Builder, node any, level int) {
+	switch value := node.(type) {
+	case jsonObject:
+		var sections jsonObject
+		for _, field := range value {
+			switch fieldValue := field.Value.(type) {
+			case jsonObject:
+				sections = append(sections, field)
+			case []any:
+				if isScalarList(fieldValue) {
+					fmt.Fprintf(b, "- **%s**: %s\n", field.Key, joinScalars(fieldValue))
+				} else {
+					sections = append(sections, field)
+				}
+			default:
+				fmt.Fprintf(b, "- **%s**: %s\n", field.Key, markdownScalar(fieldValue))
+			}
+		}
+		for _, section := range sections {
+			fmt.Fprintf(b, "\n%s %s\n\n", strings.Repeat("#", min(level, 6)), section.Key)
+			writeMarkdown(b, section.Value, level+1)
+		}
+	case []any:
+		if isScalarList(value) {
+			for _, element := range value {
+				fmt.Fprintf(b, "- %s\n", markdownScalar(element))
+			}
+			return
+		}
+		writeMarkdownTable(b, value)
+	default:
+		fmt.Fprintf(b, "%s\n", markdownScalar(value))
+	}
+}
+
+// writeMarkdownTable renders a list as a table with a column per object field
+func writeMarkdownTable(b *strings.Builder, rows []any) {
+	var columns []string
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		object, ok := row.(jsonObject)
+		if !ok {
+			continue
+		}
+		for _, field := range object {
+			if !seen[field.Key] {
+				seen[field.Key] = true
+				columns = append(columns, field.Key)
+			}
+		}
+	}
+	if len(columns) == 0 {
+		columns = []string{"value"}
+	}
+
+	fmt.Fprintf(b, "| %s |\n", strings.Join(columns, " | "))
+	fmt.Fprintf(b, "|%s\n", strings.Repeat(" --- |", len(columns)))
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		object, ok := row.(jsonObject)
+		if !ok {
+			cells[0] = markdownCell(row)
+		}
+		for i, column := range columns {
+			for _, field := range object {
+				if field.Key == column {
+					cells[i] = markdownCell(field.Value)
+					break
+				}
+			}
+		}
+		fmt.Fprintf(b, "| %s |\n", strings.Join(cells, " | "))
+	}
+}
+
+// isScalarList reports whether a list holds no objects or lists
+func isScalarList(values []any) bool {
+	for _, value := range values {
+		switch value.(type) {
+		case jsonObject, []any:
+			return false
+		}
+	}
+	return true
+}
+
+// joinScalars renders a list of scalars on a single line
+func joinScalars(values []any) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = markdownScalar(value)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// markdownScalar renders a scalar JSON value as plain text
+func markdownScalar(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// markdownCell renders a value as the content of a table cell, nested values as compact JSON
+func markdownCell(value any) string {
+	cell := markdownScalar(value)
+	cell = strings.ReplaceAll(cell, "|", "\\|")
+	return strings.Join(strings.Fields(cell), " ")
+}
+
+// walletView is the compact and markdown rendering of a wallet: balances joined with
+// their currency names in game order, with zero balances summarized as a count
+type walletView struct {
+	UpdatedAt    string          `json:"updated_at"`
+	Balances     []walletBalance `json:"balances"`
+	ZeroBalances int             `json:"zero_balances_omitted,omitempty"`
+}
+
+// walletBalance is a named wallet entry
+type walletBalance struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+// newWalletView summarizes a wallet for compact and markdown renderings
+func newWalletView(wallet *gw2api.WalletInfo) walletView {
+	view := walletView{UpdatedAt: wallet.UpdatedAt.UTC().Format(time.RFC3339)}
+
+	for _, entry := range wallet.Entries {
+		if entry.Value == 0 {
+			view.ZeroBalances++
+			continue
+		}
+		view.Balances = append(view.Balances, walletBalance{
+			ID:    entry.ID,
+			Name:  wallet.Currencies[entry.ID].Name,
+			Value: entry.Value,
+		})
+	}
+
+	sort.SliceStable(view.Balances, func(i, j int) bool {
+		return currencyLess(wallet.Currencies[view.Balances[i].ID], wallet.Currencies[view.Balances[j].ID])
+	})
+
+	return view
+}
+
+// currencyList lists currencies in game order, for compact and markdown renderings
+func currencyList(currencies map[int]gw2api.Currency) []gw2api.Currency {
+	list := make([]gw2api.Currency, 0, len(currencies))
+	for _, currency := range currencies {
+		list = append(list, currency)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return currencyLess(list[i], list[j])
+	})
+
+	return list
+}
+
+// currencyLess orders currencies as the game wallet does
+func currencyLess(a, b gw2api.Currency) bool {
+	if a.Order != b.Order {
+		return a.Order < b.Order
+	}
+	return a.ID < b.ID
+}
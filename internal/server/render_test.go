@@ -0,0 +1,317 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+	"github.com/AlyxPink/gw2-mcp/internal/wiki"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Format
+		wantErr  bool
+	}{
+		{"", FormatJSON, false},
+		{"json", FormatJSON, false},
+		{"compact", FormatCompact, false},
+		{"Markdown", FormatMarkdown, false},
+		{"yaml", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			format, err := ParseFormat(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if format != tt.expected {
+				t.Errorf("ParseFormat(%q) = %q, want %q", tt.input, format, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	s := &MCPServer{}
+
+	value := map[string]any{
+		"total": 2,
+		"currencies": []gw2api.Currency{
+			{ID: 1, Name: "Coin", Description: "The primary currency", Icon: "coin.png", Order: 101},
+			{ID: 4, Name: "Gem | Store", Description: "Purchased", Icon: "gem.png", Order: 80},
+		},
+		"tags":  []string{"a", "b"},
+		"empty": "",
+	}
+
+	tests := []struct {
+		name        string
+		format      Format
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:     "json keeps every field",
+			format:   FormatJSON,
+			contains: []string{`"icon": "coin.png"`, `"description": "Purchased"`, `"empty": ""`},
+		},
+		{
+			name:        "compact drops icons, descriptions and empty fields",
+			format:      FormatCompact,
+			contains:    []string{`{"name":"Coin","id":1,"order":101}`, `"tags":["a","b"]`},
+			notContains: []string{"icon", "description", "empty", "\n"},
+		},
+		{
+			name:   "markdown renders lists of objects as tables",
+			format: FormatMarkdown,
+			contains: []string{
+				"- **tags**: a, b",
+				"- **total**: 2",
+				"## currencies",
+				"| name | id | order |",
+				"| Coin | 1 | 101 |",
+				`| Gem \| Store | 4 | 80 |`,
+			},
+			notContains: []string{"icon", "description", "empty"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := s.render(value, tt.format)
+			if err != nil {
+				t.Fatalf("render failed: %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, text)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(text, unwanted) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unwanted, text)
+				}
+			}
+		})
+	}
+}
+
+func TestTruncateOutput(t *testing.T) {
+	text := strings.Repeat("line of text\n", 100)
+
+	tests := []struct {
+		name      string
+		text      string
+		maxSize   int
+		truncated bool
+		keptSize  int
+	}{
+		{name: "within limit", text: "short", maxSize: 100},
+		{name: "limit disabled", text: text, maxSize: 0},
+		{name: "cut at line break", text: text, maxSize: 100, truncated: true, keptSize: 90},
+		{name: "cut without line break", text: strings.Repeat("x", 50), maxSize: 20, truncated: true, keptSize: 20},
+		{name: "cut before multibyte rune", text: strings.Repeat("é", 10), maxSize: 5, truncated: true, keptSize: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateOutput(tt.text, tt.maxSize)
+			if !tt.truncated {
+				if got != tt.text {
+					t.Errorf("Expected text to be kept as is, got %q", got)
+				}
+				return
+			}
+
+			kept, notice, found := strings.Cut(got, "\n\n[Output truncated")
+			if !found {
+				t.Fatalf("Expected a truncation notice, got %q", got)
+			}
+			if len(kept) != tt.keptSize || !strings.HasPrefix(tt.text, kept) {
+				t.Errorf("Expected the first %d bytes to be kept, got %q", tt.keptSize, kept)
+			}
+			if !strings.Contains(notice, fmt.Sprintf("of %d bytes", len(tt.text))) {
+				t.Errorf("Expected the notice to report the full size, got %q", notice)
+			}
+		})
+	}
+}
+
+func TestNewWalletView(t *testing.T) {
+	wallet := &gw2api.WalletInfo{
+		UpdatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Currencies: map[int]gw2api.Currency{
+			1: {ID: 1, Name: "Coin", Order: 101},
+			2: {ID: 2, Name: "Karma", Order: 102},
+			4: {ID: 4, Name: "Gem", Order: 80},
+			7: {ID: 7, Name: "Fractal Relic", Order: 150},
+		},
+		Entries: []gw2api.WalletEntry{{ID: 1, Value: 500}, {ID: 2, Value: 0}, {ID: 4, Value: 10}, {ID: 7, Value: 0}},
+		Total:   4,
+	}
+
+	view := newWalletView(wallet)
+
+	if view.UpdatedAt != "2024-05-01T12:00:00Z" {
+		t.Errorf("Expected RFC 3339 update time, got %q", view.UpdatedAt)
+	}
+	if view.ZeroBalances != 2 {
+		t.Errorf("Expected 2 zero balances summarized, got %d", view.ZeroBalances)
+	}
+
+	expected := []walletBalance{{ID: 4, Name: "Gem", Value: 10}, {ID: 1, Name: "Coin", Value: 500}}
+	if len(view.Balances) != len(expected) {
+		t.Fatalf("Expected %d balances, got %+v", len(expected), view.Balances)
+	}
+	for i, balance := range expected {
+		if view.Balances[i] != balance {
+			t.Errorf("Balance %d: expected %+v, got %+v", i, balance, view.Balances[i])
+		}
+	}
+}
+
+func TestToolFormats(t *testing.T) {
+	gw2Server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/account/wallet":
+			_, _ = w.Write([]byte(`[{"id":1,"value":123456},{"id":4,"value":0}]`))
+		case "/currencies":
+			_, _ = w.Write([]byte(`[{"id":1,"name":"Coin","description":"Gold","order":101,"icon":"coin.png"},` +
+				`{"id":4,"name":"Gem","description":"Purchased","order":80,"icon":"gem.png"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer gw2Server.Close()
+
+	wikiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("list") == "search" {
+			_, _ = w.Write([]byte(`{"query":{"search":[{"title":"Mystic Coin","pageid":1,` +
+				`"snippet":"<span class=\"searchmatch\">Mystic</span> Coin"}]}}`))
+			return
+		}
+		extract := "Mystic Coins are used in the Mystic Forge."
+		if r.URL.Query().Get("explaintext") == "" {
+			extract = `<p><b>Mystic Coins</b> are used in the <a href=\"/wiki/Mystic_Forge\">Mystic Forge</a>.</p>`
+		}
+		_, _ = w.Write([]byte(`{"query":{"pages":{"1":{"pageid":1,"title":"Mystic Coin","extract":"` + extract + `"}}}}`))
+	}))
+	defer wikiServer.Close()
+
+	s, err := NewMCPServer(log.New(io.Discard),
+		WithGW2APIOptions(gw2api.WithBaseURL(gw2Server.URL)),
+		WithWikiOptions(wiki.WithBaseURL(wiki.LanguageEnglish, wikiServer.URL)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	mcpClient, err := client.NewInProcessClient(s.mcp)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer func() { _ = mcpClient.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	if _, err := mcpClient.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		tool      string
+		arguments map[string]any
+		isError   bool
+		textOnly  bool // tool without an output schema
+		contains  []string
+	}{
+		{
+			name:      "markdown wallet",
+			tool:      "get_wallet",
+			arguments: map[string]any{"api_key": "KEY", "format": "markdown"},
+			contains:  []string{"| id | name | value |", "| 1 | Coin | 123456 |", "**zero_balances_omitted**: 1"},
+		},
+		{
+			name:      "compact currencies",
+			tool:      "get_currencies",
+			arguments: map[string]any{"ids": []int{1, 4}, "format": "compact"},
+			contains:  []string{`[{"name":"Gem","id":4,"order":80},{"name":"Coin","id":1,"order":101}]`},
+		},
+		{
+			name:      "markdown wiki search",
+			tool:      "wiki_search",
+			arguments: map[string]any{"query": "mystic coin", "format": "markdown"},
+			contains: []string{"**Mystic** Coin",
+				"**Mystic Coins** are used in the [Mystic Forge](" + wikiServer.URL + "/wiki/Mystic_Forge)."},
+		},
+		{
+			name:      "markdown wiki page",
+			tool:      "wiki_page",
+			arguments: map[string]any{"title": "Mystic Coin", "format": "markdown"},
+			textOnly:  true,
+			contains:  []string{"**Mystic Coins** are used in the [Mystic Forge](" + wikiServer.URL + "/wiki/Mystic_Forge)."},
+		},
+		{
+			name:      "plain text wiki search",
+			tool:      "wiki_search",
+			arguments: map[string]any{"query": "mystic coin", "format": "compact"},
+			contains:  []string{`"snippet":"Mystic Coin"`, `"extract":"Mystic Coins are used in the Mystic Forge."`},
+		},
+		{
+			name:      "invalid format",
+			tool:      "get_currencies",
+			arguments: map[string]any{"format": "xml"},
+			isError:   true,
+			contains:  []string{"Invalid format parameter"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callRequest := mcp.CallToolRequest{}
+			callRequest.Params.Name = tt.tool
+			callRequest.Params.Arguments = tt.arguments
+			result, err := mcpClient.CallTool(ctx, callRequest)
+			if err != nil {
+				t.Fatalf("Failed to call %s: %v", tt.tool, err)
+			}
+			if result.IsError != tt.isError {
+				t.Fatalf("Expected IsError %v, got %v: %v", tt.isError, result.IsError, result.Content)
+			}
+
+			text := result.Content[0].(mcp.TextContent).Text
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, text)
+				}
+			}
+			if strings.Contains(text, ".png") {
+				t.Errorf("Expected icons to be dropped, got:\n%s", text)
+			}
+
+			// Tools with an output schema keep returning the full structured content
+			if !tt.isError && !tt.textOnly && result.StructuredContent == nil {
+				t.Error("Expected structured content regardless of the text format")
+			}
+		})
+	}
+}
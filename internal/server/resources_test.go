@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+	"github.com/AlyxPink/gw2-mcp/internal/wiki"
+)
+
+func TestResourceTemplates(t *testing.T) {
+	gw2Server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/currencies" && r.URL.Query().Get("ids") == "4":
+			_, _ = w.Write([]byte(`[{"id":4,"name":"Gem","description":"Purchased","order":80,"icon":"gem.png"}]`))
+		case r.URL.Path == "/items/19721":
+			_, _ = w.Write([]byte(`{"id":19721,"name":"Glob of Ectoplasm","type":"CraftingMaterial","rarity":"Exotic","level":0}`))
+		case r.URL.Path == "/recipes/7319":
+			_, _ = w.Write([]byte(`{"id":7319,"type":"Refinement","output_item_id":19712,"output_item_count":1,` +
+				`"ingredients":[{"type":"Item","id":19723,"count":2}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"text":"no such id"}`))
+		}
+	}))
+	defer gw2Server.Close()
+
+	wikiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		title := r.URL.Query().Get("titles")
+		_, _ = w.Write([]byte(`{"query":{"pages":{"1":{"pageid":1,"title":` + jsonString(title) +
+			`,"extract":"A rare crafting material."}}}}`))
+	}))
+	defer wikiServer.Close()
+
+	s, err := NewMCPServer(log.New(io.Discard),
+		WithGW2APIOptions(gw2api.WithBaseURL(gw2Server.URL)),
+		WithWikiOptions(wiki.WithBaseURL(wiki.LanguageEnglish, wikiServer.URL)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	mcpClient, err := client.NewInProcessClient(s.mcp)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer func() { _ = mcpClient.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	if _, err := mcpClient.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	templates, err := mcpClient.ListResourceTemplates(ctx, mcp.ListResourceTemplatesRequest{})
+	if err != nil {
+		t.Fatalf("Failed to list resource templates: %v", err)
+	}
+	if len(templates.ResourceTemplates) != 4 {
+		t.Errorf("Expected 4 resource templates, got %d", len(templates.ResourceTemplates))
+	}
+
+	tests := []struct {
+		name     string
+		uri      string
+		contains string
+		wantErr  bool
+	}{
+		{name: "currency", uri: "gw2://currency/4", contains: `"name": "Gem"`},
+		{name: "item", uri: "gw2://item/19721", contains: `"name": "Glob of Ectoplasm"`},
+		{name: "recipe", uri: "gw2://recipe/7319", contains: `"output_item_id": 19712`},
+		{name: "wiki page", uri: "wiki://page/Glob%20of%20Ectoplasm", contains: `"title": "Glob of Ectoplasm"`},
+		{name: "unknown item", uri: "gw2://item/1", wantErr: true},
+		{name: "non-numeric id", uri: "gw2://item/ecto", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.ReadResourceRequest{}
+			request.Params.URI = tt.uri
+
+			result, err := mcpClient.ReadResource(ctx, request)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error reading %s", tt.uri)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", tt.uri, err)
+			}
+
+			if len(result.Contents) != 1 {
+				t.Fatalf("Expected 1 content, got %d", len(result.Contents))
+			}
+			text, ok := result.Contents[0].(mcp.TextResourceContents)
+			if !ok {
+				t.Fatalf("Expected text contents, got %T", result.Contents[0])
+			}
+			if text.URI != tt.uri {
+				t.Errorf("Expected URI %s, got %s", tt.uri, text.URI)
+			}
+			if !strings.Contains(text.Text, tt.contains) {
+				t.Errorf("Expected contents to contain %s, got %s", tt.contains, text.Text)
+			}
+		})
+	}
+}
+
+// jsonString encodes a string as a JSON literal
+func jsonString(value string) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
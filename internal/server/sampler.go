@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlyxPink/gw2-mcp/internal/store"
+)
+
+// DefaultPriceSampleInterval is how often the prices of the watchlist are recorded
+const DefaultPriceSampleInterval = 15 * time.Minute
+
+// WithDatabase stores data the GW2 API does not retain, such as price history, in a SQLite
+// database at path. The price history tool is only available with a database.
+func WithDatabase(path string) Option {
+	return func(s *MCPServer) {
+		s.databasePath = path
+	}
+}
+
+// WithPriceWatchlist records the trading post prices of the given items every interval
+// while the server runs. It requires a database.
+func WithPriceWatchlist(itemIDs []int, interval time.Duration) Option {
+	return func(s *MCPServer) {
+		s.priceWatchlist = itemIDs
+		s.sampleInterval = interval
+	}
+}
+
+// samplePrices records the prices of the watchlist now and then every sample interval,
+// until ctx is done
+func (s *MCPServer) samplePrices(ctx context.Context) {
+	defer s.background.Done()
+
+	s.logger.Info("Recording trading post prices", "items", len(s.priceWatchlist), "interval", s.sampleInterval)
+
+	ticker := time.NewTicker(s.sampleInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.recordPrices(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Warn("Failed to record trading post prices", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recordPrices stores one sample of the current prices of the watchlist
+func (s *MCPServer) recordPrices(ctx context.Context) error {
+	prices, err := s.gw2API.GetPrices(ctx, s.priceWatchlist)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	samples := make([]store.PriceSample, 0, len(prices))
+	for _, price := range prices {
+		samples = append(samples, store.PriceSample{
+			Time:         now,
+			ItemID:       price.ID,
+			BuyPrice:     price.Buys.UnitPrice,
+			BuyQuantity:  price.Buys.Quantity,
+			SellPrice:    price.Sells.UnitPrice,
+			SellQuantity: price.Sells.Quantity,
+		})
+	}
+
+	if err := s.store.AddPriceSamples(ctx, samples); err != nil {
+		return err
+	}
+
+	if skipped := len(s.priceWatchlist) - len(samples); skipped > 0 {
+		s.logger.Debug("Some watchlist items have no trading post prices", "skipped", skipped)
+	}
+	s.logger.Debug("Recorded trading post prices", "items", len(samples))
+	return nil
+}
+
+// parseWindow parses a price history window: a number of days such as 7d, or a Go
+// duration such as 12h
+func parseWindow(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+
+	var window time.Duration
+	if daysValue, ok := strings.CutSuffix(value, "d"); ok {
+		days, err := strconv.Atoi(daysValue)
+		if err != nil {
+			return 0, fmt.Errorf("expected a window such as 7d or 12h, got %q", value)
+		}
+		window = time.Duration(days) * 24 * time.Hour
+	} else {
+		var err error
+		if window, err = time.ParseDuration(value); err != nil {
+			return 0, fmt.Errorf("expected a window such as 7d or 12h, got %q", value)
+		}
+	}
+	if window <= 0 {
+		return 0, fmt.Errorf("window must be positive, got %q", value)
+	}
+	return window, nil
+}
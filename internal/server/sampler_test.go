@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+	"github.com/AlyxPink/gw2-mcp/internal/gw2mock"
+	"github.com/AlyxPink/gw2-mcp/internal/store"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "7d", want: 7 * 24 * time.Hour},
+		{value: " 30d ", want: 30 * 24 * time.Hour},
+		{value: "12h", want: 12 * time.Hour},
+		{value: "0d", wantErr: true},
+		{value: "-1h", wantErr: true},
+		{value: "a week", wantErr: true},
+		{value: "d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseWindow(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPriceHistory_MockGW2API(t *testing.T) {
+	mock := httptest.NewServer(gw2mock.New())
+	defer mock.Close()
+
+	// Mystic Clover cannot be traded, so it has no prices to record
+	s, err := NewMCPServer(log.New(io.Discard),
+		WithGW2APIOptions(gw2api.WithBaseURL(mock.URL+"/v2")),
+		WithDatabase(filepath.Join(t.TempDir(), "gw2-mcp.db")),
+		WithPriceWatchlist([]int{19721, 19675}, time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	t.Cleanup(func() { _ = s.store.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Earlier prices were higher, so the current one is below the average
+	now := time.Now()
+	earlier := []store.PriceSample{
+		{ItemID: 19721, Time: now.Add(-72 * time.Hour), BuyPrice: 2600, SellPrice: 2800},
+		{ItemID: 19721, Time: now.Add(-48 * time.Hour), BuyPrice: 2550, SellPrice: 2750},
+		{ItemID: 19721, Time: now.Add(-30 * 24 * time.Hour), BuyPrice: 9999, SellPrice: 9999}, // outside the window
+	}
+	if err := s.store.AddPriceSamples(ctx, earlier); err != nil {
+		t.Fatalf("AddPriceSamples failed: %v", err)
+	}
+	if err := s.recordPrices(ctx); err != nil {
+		t.Fatalf("recordPrices failed: %v", err)
+	}
+
+	mcpClient := newInProcessClient(ctx, t, s)
+
+	tests := []struct {
+		name      string
+		arguments map[string]any
+		isError   bool
+		contains  []string
+	}{
+		{
+			name:      "recorded item",
+			arguments: map[string]any{"item_id": 19721, "format": "compact"},
+			contains: []string{
+				`"item_name":"Glob of Ectoplasm"`,
+				`"buy":{"trend":"falling","current":2412,"min":2412,"max":2600,"average":2521`,
+				`"samples":3`,
+			},
+		},
+		{
+			name:      "shorter window",
+			arguments: map[string]any{"item_id": 19721, "window": "1h", "format": "compact"},
+			contains:  []string{`"samples":1`, `"trend":"flat"`},
+		},
+		{
+			name:      "item not on the watchlist",
+			arguments: map[string]any{"item_id": 19976},
+			isError:   true,
+			contains:  []string{"No prices of item 19976 were recorded in the last 7d"},
+		},
+		{
+			name:      "invalid window",
+			arguments: map[string]any{"item_id": 19721, "window": "forever"},
+			isError:   true,
+			contains:  []string{"Invalid window parameter"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callRequest := mcp.CallToolRequest{}
+			callRequest.Params.Name = "get_price_history"
+			callRequest.Params.Arguments = tt.arguments
+			result, err := mcpClient.CallTool(ctx, callRequest)
+			if err != nil {
+				t.Fatalf("Failed to call get_price_history: %v", err)
+			}
+
+			text := result.Content[0].(mcp.TextContent).Text
+			if result.IsError != tt.isError {
+				t.Fatalf("Expected IsError %v, got %v: %s", tt.isError, result.IsError, text)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("Expected result to contain %q, got:\n%s", want, text)
+				}
+			}
+		})
+	}
+}
+
+func TestPriceHistory_RequiresDatabase(t *testing.T) {
+	s, err := NewMCPServer(log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	if tool := s.mcp.GetTool("get_price_history"); tool != nil {
+		t.Error("Expected get_price_history to be unavailable without a database")
+	}
+
+	if _, err := NewMCPServer(log.New(io.Discard), WithPriceWatchlist([]int{19721}, time.Hour)); err == nil ||
+		!strings.Contains(err.Error(), "database is required") {
+		t.Errorf("Expected a missing database error, got %v", err)
+	}
+}
@@ -3,12 +3,19 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	mcpserver "github.com/mark3labs/mcp-go/server"
 
+	"github.com/AlyxPink/gw2-mcp/internal/auth"
 	"github.com/AlyxPink/gw2-mcp/internal/cache"
 	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+	"github.com/AlyxPink/gw2-mcp/internal/store"
 	"github.com/AlyxPink/gw2-mcp/internal/wiki"
 
 	"github.com/charmbracelet/log"
@@ -16,69 +23,200 @@ import (
 
 // MCPServer wraps the MCP server with GW2-specific functionality
 type MCPServer struct {
-	mcp    *mcpserver.MCPServer
-	logger *log.Logger
-	cache  *cache.Manager
-	gw2API *gw2api.Client
-	wiki   *wiki.Client
+	mcp         *mcpserver.MCPServer
+	logger      *log.Logger
+	cache       *cache.Manager
+	gw2API      *gw2api.Client
+	wiki        *wiki.Client
+	defaultLang gw2api.Language
+	transport   Transport
+	httpAddr    string
+	auth        *auth.Store
+	store       *store.Store
+
+	// Client configuration
+	cacheOpts  []cache.Option
+	gw2APIOpts []gw2api.Option
+	wikiOpts   []wiki.Option
+	indexItems bool
+
+	// Price history
+	databasePath   string
+	priceWatchlist []int
+	sampleInterval time.Duration
+
+	// Output
+	maxOutputSize int
+
+	// Lifecycle
+	cacheFile       string
+	shutdownTimeout time.Duration
+	lifecycleMu     sync.Mutex
+	inFlight        sync.WaitGroup
+	background      sync.WaitGroup
+	stopBackground  context.CancelFunc
+	draining        bool
+
+	// Streams of the stdio transport
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+// Option configures optional MCPServer settings
+type Option func(*MCPServer)
+
+// WithDefaultLanguage sets the GW2 API language used when a tool call does not specify one
+func WithDefaultLanguage(lang gw2api.Language) Option {
+	return func(s *MCPServer) {
+		s.defaultLang = lang
+	}
+}
+
+// WithCacheOptions configures the cache manager, e.g. its TTLs
+func WithCacheOptions(opts ...cache.Option) Option {
+	return func(s *MCPServer) {
+		s.cacheOpts = append(s.cacheOpts, opts...)
+	}
+}
+
+// WithGW2APIOptions configures the GW2 API client, e.g. its base URL or timeout
+func WithGW2APIOptions(opts ...gw2api.Option) Option {
+	return func(s *MCPServer) {
+		s.gw2APIOpts = append(s.gw2APIOpts, opts...)
+	}
+}
+
+// WithWikiOptions configures the wiki client, e.g. its base URLs or timeout
+func WithWikiOptions(opts ...wiki.Option) Option {
+	return func(s *MCPServer) {
+		s.wikiOpts = append(s.wikiOpts, opts...)
+	}
+}
+
+// WithItemIndex builds the item name index in the background on start, so item names
+// can be completed before they have been looked up once
+func WithItemIndex(enabled bool) Option {
+	return func(s *MCPServer) {
+		s.indexItems = enabled
+	}
 }
 
 // NewMCPServer creates a new GW2 MCP server instance
-func NewMCPServer(logger *log.Logger) (*MCPServer, error) {
+func NewMCPServer(logger *log.Logger, opts ...Option) (*MCPServer, error) {
+	gw2MCP := &MCPServer{
+		logger:          logger,
+		defaultLang:     gw2api.DefaultLanguage,
+		transport:       TransportStdio,
+		httpAddr:        DefaultHTTPAddr,
+		shutdownTimeout: DefaultShutdownTimeout,
+		maxOutputSize:   DefaultMaxOutputSize,
+		sampleInterval:  DefaultPriceSampleInterval,
+		stdin:           os.Stdin,
+		stdout:          os.Stdout,
+	}
+
+	for _, opt := range opts {
+		opt(gw2MCP)
+	}
+
 	// Create cache manager
-	cacheManager := cache.NewManager()
+	cacheOpts := gw2MCP.cacheOpts
+	if gw2MCP.cacheFile != "" {
+		cacheOpts = append(cacheOpts, cache.WithPersistence(gw2MCP.cacheFile))
+	}
+	gw2MCP.cache = cache.NewManager(cacheOpts...)
+	if err := gw2MCP.cache.Load(); err != nil {
+		logger.Warn("Failed to restore persisted cache, starting empty", "error", err)
+	}
 
 	// Create GW2 API client
-	gw2Client := gw2api.NewClient(cacheManager, logger)
+	gw2MCP.gw2API = gw2api.NewClient(gw2MCP.cache, logger, gw2MCP.gw2APIOpts...)
 
 	// Create wiki client
-	wikiClient := wiki.NewClient(cacheManager, logger)
+	gw2MCP.wiki = wiki.NewClient(gw2MCP.cache, logger, gw2MCP.wikiOpts...)
+
+	// Open the local database
+	if gw2MCP.databasePath != "" {
+		db, err := store.Open(gw2MCP.databasePath)
+		if err != nil {
+			return nil, err
+		}
+		gw2MCP.store = db
+	} else if len(gw2MCP.priceWatchlist) > 0 {
+		return nil, fmt.Errorf("a database is required to record the prices of %d watchlist items",
+			len(gw2MCP.priceWatchlist))
+	}
+
+	// Forget the profile bound to a session once its transport closes it, e.g. an SSE connection
+	hooks := &mcpserver.Hooks{}
+	if gw2MCP.auth != nil {
+		// The HTTP transports register sessions within the authenticated request opening them
+		hooks.AddOnRegisterSession(func(ctx context.Context, session mcpserver.ClientSession) {
+			if profile, ok := auth.ProfileFromContext(ctx); ok {
+				gw2MCP.auth.BindSession(session.SessionID(), profile)
+			}
+		})
+		hooks.AddOnUnregisterSession(func(_ context.Context, session mcpserver.ClientSession) {
+			gw2MCP.auth.EndSession(session.SessionID())
+		})
+	}
 
 	// Create MCP server
-	mcpServer := mcpserver.NewMCPServer(
+	gw2MCP.mcp = mcpserver.NewMCPServer(
 		"GW2 MCP Server",
 		"1.0.0",
 		mcpserver.WithToolCapabilities(true),
 		mcpserver.WithResourceCapabilities(true, true),
+		mcpserver.WithPromptCapabilities(true),
+		mcpserver.WithCompletions(),
+		mcpserver.WithPromptCompletionProvider(&completionProvider{server: gw2MCP}),
+		mcpserver.WithResourceCompletionProvider(&completionProvider{server: gw2MCP}),
 		mcpserver.WithRecovery(),
+		mcpserver.WithToolHandlerMiddleware(gw2MCP.trackInFlight),
+		mcpserver.WithToolHandlerMiddleware(gw2MCP.trackProgress),
+		mcpserver.WithHooks(hooks),
 	)
 
-	gw2MCP := &MCPServer{
-		mcp:    mcpServer,
-		logger: logger,
-		cache:  cacheManager,
-		gw2API: gw2Client,
-		wiki:   wikiClient,
-	}
-
 	// Register tools
 	gw2MCP.registerTools()
 
 	// Register resources
 	gw2MCP.registerResources()
 
+	// Register prompts
+	gw2MCP.registerPrompts()
+
 	return gw2MCP, nil
 }
 
-// Start starts the MCP server
+// Start starts the MCP server on the configured transport
 func (s *MCPServer) Start(ctx context.Context) error {
-	s.logger.Info("Starting MCP server on stdio")
+	// Background work stops with ctx, or on shutdown when the transport ends on its own
+	backgroundCtx, stopBackground := context.WithCancel(ctx)
+	s.stopBackground = stopBackground
 
-	// Create a channel to capture ServeStdio errors
-	errChan := make(chan error, 1)
+	if s.indexItems {
+		s.background.Add(1)
+		go s.buildItemIndex(backgroundCtx)
+	}
+
+	if s.store != nil && len(s.priceWatchlist) > 0 {
+		s.background.Add(1)
+		go s.samplePrices(backgroundCtx)
+	}
+
+	if s.transport == TransportStdio {
+		return s.startStdio(ctx)
+	}
+	return s.startHTTP(ctx)
+}
 
-	// Start the server in a goroutine
-	go func() {
-		errChan <- mcpserver.ServeStdio(s.mcp)
-	}()
+// buildItemIndex loads the item name index of the default language used for completion
+func (s *MCPServer) buildItemIndex(ctx context.Context) {
+	defer s.background.Done()
 
-	// Wait for either context cancellation or server error
-	select {
-	case <-ctx.Done():
-		s.logger.Info("Server shutdown requested")
-		return ctx.Err()
-	case err := <-errChan:
-		return err
+	if err := s.gw2API.BuildItemIndex(ctx, s.defaultLang); err != nil && ctx.Err() == nil {
+		s.logger.Warn("Failed to build item name index", "error", err)
 	}
 }
 
@@ -97,19 +235,115 @@ func (s *MCPServer) registerTools() {
 			"limit",
 			mcp.Description("Maximum number of results to return (default: 5)"),
 		),
+		wikiLanguageParam(),
+		formatParam(),
+		mcp.WithOutputSchema[wiki.SearchResponse](),
 	)
 
 	s.mcp.AddTool(wikiSearchTool, s.handleWikiSearch)
 
+	// Wiki page tool
+	wikiPageTool := mcp.NewTool(
+		"wiki_page",
+		mcp.WithDescription("Get a summary of a Guild Wars 2 wiki page and its title on the other language wikis"),
+		mcp.WithString(
+			"title",
+			mcp.Required(),
+			mcp.Description("Exact title of the wiki page (e.g., 'Mystic Coin')"),
+		),
+		wikiLanguageParam(),
+		formatParam(),
+	)
+
+	s.mcp.AddTool(wikiPageTool, s.handleWikiPage)
+
+	// Wiki title resolution tool
+	wikiResolveTool := mcp.NewTool(
+		"wiki_resolve",
+		mcp.WithDescription("Resolve a possibly misspelled or ambiguous title to a Guild Wars 2 wiki page, "+
+			"following redirects, listing disambiguation options and suggesting near matches"),
+		mcp.WithString(
+			"title",
+			mcp.Required(),
+			mcp.Description("Page title to resolve (e.g., 'Mistic coin')"),
+		),
+		wikiLanguageParam(),
+		formatParam(),
+	)
+
+	s.mcp.AddTool(wikiResolveTool, s.handleWikiResolve)
+
+	// Wiki title translation tool
+	wikiTranslateTool := mcp.NewTool(
+		"wiki_translate_title",
+		mcp.WithDescription("Translate a Guild Wars 2 wiki page title between the English, German, French and Spanish wikis"),
+		mcp.WithString(
+			"title",
+			mcp.Required(),
+			mcp.Description("Title of the wiki page in the source language (e.g., 'Mystic Coin')"),
+		),
+		mcp.WithString(
+			"from",
+			mcp.Description("Language of the given title (default: en)"),
+			mcp.Enum(wikiLanguageCodes()...),
+		),
+		mcp.WithString(
+			"to",
+			mcp.Required(),
+			mcp.Description("Language to translate the title to"),
+			mcp.Enum(wikiLanguageCodes()...),
+		),
+		formatParam(),
+	)
+
+	s.mcp.AddTool(wikiTranslateTool, s.handleWikiTranslateTitle)
+
+	// Wiki category members tool
+	wikiCategoryTool := mcp.NewTool(
+		"wiki_category_members",
+		mcp.WithDescription("List the pages in a Guild Wars 2 wiki category"),
+		mcp.WithString(
+			"category",
+			mcp.Required(),
+			mcp.Description("Category name, with or without the 'Category:' prefix or its localized form "+
+				"(e.g., 'Legendary weapons' or 'Kategorie:Legendäre Waffen')"),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Maximum number of pages to return (default: 100, max: 2000)"),
+		),
+		wikiLanguageParam(),
+		formatParam(),
+	)
+
+	s.mcp.AddTool(wikiCategoryTool, s.handleWikiCategoryMembers)
+
+	// Wiki recent changes tool
+	wikiRecentChangesTool := mcp.NewTool(
+		"wiki_recent_changes",
+		mcp.WithDescription("List the most recent edits and new pages on the Guild Wars 2 wiki"),
+		mcp.WithNumber(
+			"namespace",
+			mcp.Description("Wiki namespace to filter on (default: 0 for main articles, 14 for categories)"),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Maximum number of changes to return (default: 25, max: 500)"),
+		),
+		wikiLanguageParam(),
+		formatParam(),
+	)
+
+	s.mcp.AddTool(wikiRecentChangesTool, s.handleWikiRecentChanges)
+
 	// Wallet info tool
 	walletTool := mcp.NewTool(
 		"get_wallet",
 		mcp.WithDescription("Get user's wallet information including all currencies"),
-		mcp.WithString(
-			"api_key",
-			mcp.Required(),
-			mcp.Description("Guild Wars 2 API key with account scope"),
-		),
+		apiKeyParam(),
+		apiLanguageParam(),
+		formatParam(),
+		mcp.WithOutputSchema[walletSchema](),
 	)
 
 	s.mcp.AddTool(walletTool, s.handleGetWallet)
@@ -122,9 +356,344 @@ func (s *MCPServer) registerTools() {
 			"ids",
 			mcp.Description("Specific currency IDs to fetch (optional, returns all if not specified)"),
 		),
+		apiLanguageParam(),
+		formatParam(),
+		mcp.WithOutputSchema[currenciesSchema](),
 	)
 
 	s.mcp.AddTool(currencyTool, s.handleGetCurrencies)
+
+	// Gem exchange tool
+	gemExchangeTool := mcp.NewTool(
+		"get_gem_exchange",
+		mcp.WithDescription("Quote the gem exchange: how many gems given amounts of gold buy, or how much gold "+
+			"given amounts of gems sell for, with the rate and slippage of each amount"),
+		mcp.WithString(
+			"from",
+			mcp.Required(),
+			mcp.Description("Currency to exchange: coins to buy gems, or gems to sell them for coins"),
+			mcp.Enum(string(gw2api.ExchangeCoins), string(gw2api.ExchangeGems)),
+		),
+		mcp.WithArray(
+			"amounts",
+			mcp.Description(fmt.Sprintf("Amounts to quote, in gold when exchanging coins (e.g. 100 for 100 gold) "+
+				"or in gems (default: %s gold, or %s gems, max: %d amounts)",
+				joinAmounts(defaultGoldAmounts), joinAmounts(defaultGemAmounts), maxExchangeAmounts)),
+			mcp.WithNumberItems(mcp.Min(0)),
+		),
+		formatParam(),
+		mcp.WithOutputSchema[gw2api.GemExchange](),
+	)
+
+	s.mcp.AddTool(gemExchangeTool, s.handleGetGemExchange)
+
+	// Trading post transactions tool
+	transactionsTool := mcp.NewTool(
+		"get_tp_transactions",
+		mcp.WithDescription("Get the trading post buy and sell orders of an account, open or fulfilled in the last "+
+			"90 days, with item names and, for fulfilled orders, the coins spent, earned after fees and profit "+
+			"over a date range. Requires an API key with the tradingpost scope"),
+		apiKeyParam(),
+		mcp.WithString(
+			"state",
+			mcp.Description("Orders to list: history for fulfilled orders or current for open ones (default: history)"),
+			mcp.Enum(string(gw2api.TransactionsHistory), string(gw2api.TransactionsCurrent)),
+		),
+		mcp.WithString(
+			"type",
+			mcp.Description("Order type to list (default: both)"),
+			mcp.Enum(string(gw2api.TransactionBuys), string(gw2api.TransactionSells), "both"),
+		),
+		mcp.WithString(
+			"since",
+			mcp.Description("Only include orders from this date on, as YYYY-MM-DD or RFC 3339 (optional)"),
+		),
+		mcp.WithString(
+			"until",
+			mcp.Description("Only include orders up to this date, inclusive, as YYYY-MM-DD or RFC 3339 (optional)"),
+		),
+		mcp.WithNumber(
+			"page",
+			mcp.Description("Page of orders to return, newest first, starting at 1 (default: 1). "+
+				"The summary always covers every page"),
+		),
+		mcp.WithNumber(
+			"page_size",
+			mcp.Description(fmt.Sprintf("Orders per page (default: %d, max: %d)", defaultTransactionPageSize,
+				maxTransactionPageSize)),
+		),
+		apiLanguageParam(),
+		formatParam(),
+		mcp.WithOutputSchema[gw2api.TransactionReport](),
+	)
+
+	s.mcp.AddTool(transactionsTool, s.handleGetTPTransactions)
+
+	// Trading post delivery box tool
+	deliveryTool := mcp.NewTool(
+		"get_tp_delivery",
+		mcp.WithDescription("Get the coins and items waiting to be picked up from the trading post. "+
+			"Requires an API key with the tradingpost scope"),
+		apiKeyParam(),
+		apiLanguageParam(),
+		formatParam(),
+		mcp.WithOutputSchema[gw2api.Delivery](),
+	)
+
+	s.mcp.AddTool(deliveryTool, s.handleGetTPDelivery)
+
+	// Trading post flip finder tool
+	flipsTool := mcp.NewTool(
+		"find_tp_flips",
+		mcp.WithDescription("Find trading post flips: items whose spread between the highest buy order and the "+
+			"lowest sell listing stays profitable after the 15% fees. Scans every traded item, or the given ones, "+
+			"and ranks them by profit, return on investment, supply and demand, or capital required"),
+		mcp.WithArray(
+			"item_ids",
+			mcp.Description("Item IDs to consider (optional, scans every traded item if not specified)"),
+			mcp.WithNumberItems(),
+		),
+		mcp.WithNumber(
+			"min_volume",
+			mcp.Description(fmt.Sprintf("Minimum number of items both wanted by buy orders and offered by sell "+
+				"listings, to skip thinly traded items (default: %d)", defaultFlipMinVolume)),
+		),
+		mcp.WithNumber(
+			"min_roi",
+			mcp.Description("Minimum profit after fees in percent of the capital (default: 0)"),
+		),
+		mcp.WithNumber(
+			"max_capital",
+			mcp.Description("Maximum gold to spend on the buy order and listing fee of one flip (optional)"),
+		),
+		mcp.WithNumber(
+			"quantity",
+			mcp.Description(fmt.Sprintf("Items to flip at once, for the capital and profit (default: %d, max: %d)",
+				defaultFlipQuantity, maxFlipQuantity)),
+		),
+		mcp.WithString(
+			"sort_by",
+			mcp.Description("Ranking: profit, roi, volume (lower of supply and demand) or capital, lowest first "+
+				"(default: profit)"),
+			mcp.Enum(flipSortNames()...),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description(fmt.Sprintf("Maximum number of flips to return (default: %d, max: %d)",
+				defaultFlipLimit, maxFlipLimit)),
+		),
+		apiLanguageParam(),
+		formatParam(),
+		mcp.WithOutputSchema[gw2api.FlipReport](),
+	)
+
+	s.mcp.AddTool(flipsTool, s.handleFindTPFlips)
+
+	// Legendary armory tool
+	armoryTool := mcp.NewTool(
+		"get_legendary_armory",
+		mcp.WithDescription("Get the legendary armory of an account: the legendary weapons, armor, trinkets, backs "+
+			"and upgrades it unlocked, with how many of the armory items are still missing. Requires an API key "+
+			"with the inventories and unlocks scopes"),
+		apiKeyParam(),
+		mcp.WithString(
+			"type",
+			mcp.Description("Only include items of this type or subtype, e.g. Weapon, Armor, Trinket, Greatsword "+
+				"or Ring (optional)"),
+		),
+		mcp.WithBoolean(
+			"include_missing",
+			mcp.Description("List the items not unlocked yet instead of only counting them (default: false)"),
+		),
+		apiLanguageParam(),
+		formatParam(),
+		mcp.WithOutputSchema[gw2api.LegendaryArmory](),
+	)
+
+	s.mcp.AddTool(armoryTool, s.handleGetLegendaryArmory)
+
+	// Legendary crafting progress tool
+	legendaryProgressTool := mcp.NewTool(
+		"get_legendary_progress",
+		mcp.WithDescription("Track the progress of an account toward crafting a legendary whose recipe is known, "+
+			"currently Twilight and Aurora: which components, gifts and collections it already has from its "+
+			"bank, material storage, inventories, wallet and achievements, what is left, and the estimated gold cost of buying the rest on the trading post. "+
+			"Requires an API key with the inventories, characters, unlocks and wallet scopes, plus progression "+
+			"for legendaries needing collections"),
+		apiKeyParam(),
+		mcp.WithString(
+			"legendary",
+			mcp.Required(),
+			mcp.Description("Name or item ID of the legendary (e.g., Twilight or 30704)"),
+		),
+		apiLanguageParam(),
+		formatParam(),
+		mcp.WithOutputSchema[gw2api.LegendaryProgress](),
+	)
+
+	s.mcp.AddTool(legendaryProgressTool, s.handleGetLegendaryProgress)
+
+	// Account unlocks tool
+	unlocksTool := mcp.NewTool(
+		"get_unlocks",
+		mcp.WithDescription("Tell how complete the collection of an account is for one kind of unlocks: wardrobe "+
+			"skins, dyes, minis, outfits, gliders, mount skins, mounts, novelties, finishers, mail carriers, "+
+			"titles, emotes, jade bots or skiffs. Reports owned and missing counts overall and per category, and "+
+			"lists the missing or owned unlocks. Requires an API key with the unlocks scope"),
+		apiKeyParam(),
+		mcp.WithString(
+			"kind",
+			mcp.Required(),
+			mcp.Description("Kind of unlocks, named after its /v2/account endpoint"),
+			mcp.Enum(unlockKindNames()...),
+		),
+		mcp.WithString(
+			"category",
+			mcp.Description("Only include unlocks of this category or subtype, e.g. Armor, Coat or Greatsword for "+
+				"skins, a hue such as Red or a material such as Metal for dyes, a mount such as raptor for mount "+
+				"skins, or a slot such as Chair for novelties (optional)"),
+		),
+		mcp.WithString(
+			"name",
+			mcp.Description("Only include unlocks whose name contains this text (optional)"),
+		),
+		mcp.WithString(
+			"list",
+			mcp.Description("Unlocks to list besides the counts (default: missing)"),
+			mcp.Enum(string(gw2api.UnlockListMissing), string(gw2api.UnlockListOwned), string(gw2api.UnlockListNone)),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description(fmt.Sprintf("Maximum number of unlocks to list (default: %d, max: %d)",
+				defaultUnlockLimit, maxUnlockLimit)),
+		),
+		apiLanguageParam(),
+		formatParam(),
+		mcp.WithOutputSchema[gw2api.UnlockReport](),
+	)
+
+	s.mcp.AddTool(unlocksTool, s.handleGetUnlocks)
+
+	// Price history tool, backed by the prices recorded in the local database
+	if s.store != nil {
+		priceHistoryTool := mcp.NewTool(
+			"get_price_history",
+			mcp.WithDescription("Get the recorded trading post price history of an item over a window: min, max, "+
+				"average and trend of its highest buy order and lowest sell listing, and how the current price "+
+				"compares, to tell whether it is cheap right now. Only items on the server's price watchlist "+
+				"are recorded"),
+			mcp.WithNumber(
+				"item_id",
+				mcp.Required(),
+				mcp.Description("ID of the item (e.g., 19721 for Glob of Ectoplasm)"),
+			),
+			mcp.WithString(
+				"window",
+				mcp.Description(fmt.Sprintf("How far back to look, in days such as 30d or as a duration such as "+
+					"12h (default: %s)", defaultPriceWindow)),
+			),
+			apiLanguageParam(),
+			formatParam(),
+			mcp.WithOutputSchema[store.PriceHistory](),
+		)
+
+		s.mcp.AddTool(priceHistoryTool, s.handleGetPriceHistory)
+
+		// Account snapshot tools, recording valuations in the local database
+		snapshotTool := mcp.NewTool(
+			"snapshot_account",
+			mcp.WithDescription("Value an account and record the valuation in the local database: wallet coins, "+
+				"bank, material storage, shared and character inventories, and trading post buy orders, sell "+
+				"listings and delivery box. Items are valued at what selling them to the highest buy order yields "+
+				"after fees. Compare snapshots with diff_account. Requires an API key with the wallet, "+
+				"inventories, characters and tradingpost scopes"),
+			apiKeyParam(),
+			formatParam(),
+			mcp.WithOutputSchema[accountSnapshot](),
+		)
+
+		s.mcp.AddTool(snapshotTool, s.handleSnapshotAccount)
+
+		diffTool := mcp.NewTool(
+			"diff_account",
+			mcp.WithDescription("Compare two recorded snapshots of an account, e.g. to tell what was earned this "+
+				"week: the change of its value overall and per storage, of each wallet currency, and of the items "+
+				"whose value changed the most. Take snapshots with snapshot_account"),
+			apiKeyParam(),
+			mcp.WithString(
+				"from",
+				mcp.Description(fmt.Sprintf("Earlier snapshot: a snapshot ID, a date as YYYY-MM-DD or RFC 3339, or "+
+					"a window such as 7d back from now, selecting the latest snapshot taken by then, or else the "+
+					"earliest one (default: %s)", defaultDiffFrom)),
+			),
+			mcp.WithString(
+				"to",
+				mcp.Description("Later snapshot, as for from, a date including that day (default: the latest snapshot)"),
+			),
+			mcp.WithNumber(
+				"item_limit",
+				mcp.Description(fmt.Sprintf("Maximum number of item changes to return (default: %d, max: %d)",
+					defaultDiffItemLimit, maxDiffItemLimit)),
+			),
+			apiLanguageParam(),
+			formatParam(),
+			mcp.WithOutputSchema[gw2api.AccountDiff](),
+		)
+
+		s.mcp.AddTool(diffTool, s.handleDiffAccount)
+	}
+}
+
+// flipSortNames returns the supported flip rankings
+func flipSortNames() []string {
+	sorts := gw2api.FlipSorts()
+	names := make([]string, len(sorts))
+	for i, sortBy := range sorts {
+		names[i] = string(sortBy)
+	}
+	return names
+}
+
+// wikiLanguageParam returns the optional wiki language parameter shared by wiki tools
+func wikiLanguageParam() mcp.ToolOption {
+	return mcp.WithString(
+		"lang",
+		mcp.Description("Wiki language: en, de, fr or es (default: en)"),
+		mcp.Enum(wikiLanguageCodes()...),
+	)
+}
+
+// wikiLanguageCodes returns the codes of all supported wiki languages
+func wikiLanguageCodes() []string {
+	languages := wiki.Languages()
+	codes := make([]string, len(languages))
+	for i, lang := range languages {
+		codes[i] = string(lang)
+	}
+	return codes
+}
+
+// apiKeyParam returns the GW2 API key parameter shared by authenticated tools
+func apiKeyParam() mcp.ToolOption {
+	return mcp.WithString(
+		"api_key",
+		mcp.Description("Guild Wars 2 API key with account scope (not needed when the session is authenticated)"),
+	)
+}
+
+// apiLanguageParam returns the optional GW2 API language parameter shared by metadata tools
+func apiLanguageParam() mcp.ToolOption {
+	languages := gw2api.Languages()
+	codes := make([]string, len(languages))
+	for i, lang := range languages {
+		codes[i] = string(lang)
+	}
+
+	return mcp.WithString(
+		"lang",
+		mcp.Description("Language for names and descriptions: en, de, fr, es or zh (default: server language)"),
+		mcp.Enum(codes...),
+	)
 }
 
 // registerResources registers all available resources
@@ -138,4 +707,37 @@ func (s *MCPServer) registerResources() {
 	)
 
 	s.mcp.AddResource(currencyListResource, s.handleCurrencyListResource)
+
+	// Resource templates for individual game data and wiki pages
+	currencyTemplate := mcp.NewResourceTemplate(
+		"gw2://currency/{id}",
+		"Guild Wars 2 Currency",
+		mcp.WithTemplateDescription("Metadata of a single currency by ID, in the server language"),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+	s.mcp.AddResourceTemplate(currencyTemplate, s.handleCurrencyResource)
+
+	itemTemplate := mcp.NewResourceTemplate(
+		"gw2://item/{id}",
+		"Guild Wars 2 Item",
+		mcp.WithTemplateDescription("Metadata of a single item by ID, in the server language"),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+	s.mcp.AddResourceTemplate(itemTemplate, s.handleItemResource)
+
+	recipeTemplate := mcp.NewResourceTemplate(
+		"gw2://recipe/{id}",
+		"Guild Wars 2 Recipe",
+		mcp.WithTemplateDescription("A single crafting recipe by ID, with its ingredients and disciplines"),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+	s.mcp.AddResourceTemplate(recipeTemplate, s.handleRecipeResource)
+
+	wikiPageTemplate := mcp.NewResourceTemplate(
+		"wiki://page/{title}",
+		"Guild Wars 2 Wiki Page",
+		mcp.WithTemplateDescription("Content summary of a wiki page by title, from the wiki in the server language"),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+	s.mcp.AddResourceTemplate(wikiPageTemplate, s.handleWikiPageResource)
 }
@@ -0,0 +1,200 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+	"github.com/AlyxPink/gw2-mcp/internal/store"
+)
+
+// Account diff defaults and bounds
+const (
+	defaultDiffFrom      = "7d"
+	defaultDiffItemLimit = 20
+	maxDiffItemLimit     = 100
+)
+
+// accountSnapshot is the structured output of snapshot_account: a recorded valuation
+// without its per item and per currency details, which stay in the database for diffs
+type accountSnapshot struct {
+	TakenAt        time.Time                 `json:"taken_at"`
+	Account        string                    `json:"account"`
+	TotalFormatted string                    `json:"total_formatted"`
+	Unvalued       []int                     `json:"unvalued_items"` // IDs of held items without a buy order
+	Breakdown      gw2api.ValuationBreakdown `json:"breakdown"`
+	ID             int64                     `json:"id"`
+	Total          int                       `json:"total"`
+	Items          int                       `json:"items"` // distinct items held
+}
+
+// handleSnapshotAccount handles the snapshot_account tool
+func (s *MCPServer) handleSnapshotAccount(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey, err := s.resolveAPIKey(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	format, err := ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
+	}
+
+	s.logger.Debug("Account snapshot request", "api_key_length", len(apiKey))
+
+	valuation, err := s.gw2API.ValueAccount(ctx, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to value account: %v", err)), nil
+	}
+
+	data, err := json.Marshal(valuation)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to encode snapshot: %v", err)), nil
+	}
+	id, err := s.store.AddSnapshot(ctx, valuation.Account, valuation.TakenAt, valuation.Total, data)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to record snapshot: %v", err)), nil
+	}
+
+	snapshot := accountSnapshot{
+		TakenAt:        valuation.TakenAt,
+		Account:        valuation.Account,
+		TotalFormatted: valuation.TotalFormatted,
+		Unvalued:       valuation.Unvalued,
+		Breakdown:      valuation.Breakdown,
+		ID:             id,
+		Total:          valuation.Total,
+		Items:          len(valuation.Items),
+	}
+
+	return s.structuredResult(snapshot, nil, format, "account snapshot"), nil
+}
+
+// handleDiffAccount handles the diff_account tool
+func (s *MCPServer) handleDiffAccount(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	apiKey, err := s.resolveAPIKey(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	fromID, fromTime, err := parseSnapshotRef(request.GetString("from", defaultDiffFrom), false)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid from parameter: %v", err)), nil
+	}
+
+	toID, toTime, err := parseSnapshotRef(request.GetString("to", ""), true)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid to parameter: %v", err)), nil
+	}
+
+	itemLimit := request.GetInt("item_limit", defaultDiffItemLimit)
+	if itemLimit < 0 || itemLimit > maxDiffItemLimit {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid item_limit parameter: must be between 0 and %d, got %d",
+			maxDiffItemLimit, itemLimit)), nil
+	}
+
+	lang, err := gw2api.ParseLanguage(request.GetString("lang", ""), s.defaultLang)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid lang parameter: %v", err)), nil
+	}
+
+	format, err := ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
+	}
+
+	s.logger.Debug("Account diff request", "from_id", fromID, "from", fromTime, "to_id", toID, "to", toTime)
+
+	account, err := s.gw2API.GetAccount(ctx, apiKey)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get account: %v", err)), nil
+	}
+
+	from, err := s.findSnapshot(ctx, account.Name, fromID, fromTime)
+	if err != nil {
+		return snapshotError("from", account.Name, err), nil
+	}
+	to, err := s.findSnapshot(ctx, account.Name, toID, toTime)
+	if err != nil {
+		return snapshotError("to", account.Name, err), nil
+	}
+	if from.ID == to.ID {
+		return mcp.NewToolResultError(fmt.Sprintf("Both from and to select snapshot %d taken at %s. "+
+			"Take another one with snapshot_account to compare", from.ID, from.TakenAt.Format(time.RFC3339))), nil
+	}
+	if to.TakenAt.Before(from.TakenAt) {
+		from, to = to, from
+	}
+
+	var fromValuation, toValuation gw2api.AccountValuation
+	if err := json.Unmarshal(from.Data, &fromValuation); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to read snapshot %d: %v", from.ID, err)), nil
+	}
+	if err := json.Unmarshal(to.Data, &toValuation); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to read snapshot %d: %v", to.ID, err)), nil
+	}
+
+	diff, err := s.gw2API.DiffValuations(ctx, &fromValuation, &toValuation, itemLimit, lang)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to compare snapshots: %v", err)), nil
+	}
+
+	return s.structuredResult(diff, nil, format, "account diff"), nil
+}
+
+// parseSnapshotRef parses a snapshot ID, a date, or a window such as 7d counted back from
+// now, into an ID or the time of the snapshot to select. A date selects snapshots taken
+// before it, or on that day with endOfDay; an empty value selects the latest snapshot.
+func parseSnapshotRef(value string, endOfDay bool) (int64, time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, time.Now(), nil
+	}
+
+	if id, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if id <= 0 {
+			return 0, time.Time{}, fmt.Errorf("snapshot IDs are positive, got %d", id)
+		}
+		return id, time.Time{}, nil
+	}
+
+	if window, err := parseWindow(value); err == nil {
+		return 0, time.Now().Add(-window), nil
+	}
+
+	at, err := parseDate(value, endOfDay)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("expected a snapshot ID, a date as YYYY-MM-DD or RFC 3339, "+
+			"or a window such as 7d, got %q", value)
+	}
+	if endOfDay && !strings.Contains(value, "T") {
+		// parseDate returns the start of the next day, which is exclusive
+		at = at.Add(-time.Second)
+	}
+	return 0, at, nil
+}
+
+// findSnapshot returns the snapshot of an account with an ID, or else the latest one taken
+// at or before a time, falling back to the earliest one
+func (s *MCPServer) findSnapshot(ctx context.Context, account string, id int64, at time.Time) (*store.Snapshot, error) {
+	if id > 0 {
+		return s.store.Snapshot(ctx, account, id)
+	}
+	return s.store.SnapshotAt(ctx, account, at)
+}
+
+// snapshotError turns a failed snapshot lookup into a tool error
+func snapshotError(param, account string, err error) *mcp.CallToolResult {
+	if errors.Is(err, store.ErrNotFound) {
+		return mcp.NewToolResultError(fmt.Sprintf("No snapshot of %s matches the %s parameter. "+
+			"Take one with snapshot_account", account, param))
+	}
+	return mcp.NewToolResultError(fmt.Sprintf("Failed to get snapshot: %v", err))
+}
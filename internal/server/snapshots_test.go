@@ -0,0 +1,188 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+	"github.com/AlyxPink/gw2-mcp/internal/gw2mock"
+)
+
+func TestParseSnapshotRef(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		value    string
+		endOfDay bool
+		wantID   int64
+		wantAt   time.Time
+		wantErr  bool
+	}{
+		{value: "", wantAt: now},
+		{value: "42", wantID: 42},
+		{value: "7d", wantAt: now.Add(-7 * 24 * time.Hour)},
+		{value: "2026-10-11", wantAt: time.Date(2026, 10, 11, 0, 0, 0, 0, time.UTC)},
+		{value: "2026-10-11", endOfDay: true, wantAt: time.Date(2026, 10, 11, 23, 59, 59, 0, time.UTC)},
+		{value: "2026-10-11T08:00:00Z", endOfDay: true, wantAt: time.Date(2026, 10, 11, 8, 0, 0, 0, time.UTC)},
+		{value: "0", wantErr: true},
+		{value: "last week", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			id, at, err := parseSnapshotRef(tt.value, tt.endOfDay)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if id != tt.wantID {
+				t.Errorf("Expected ID %d, got %d", tt.wantID, id)
+			}
+			if diff := at.Sub(tt.wantAt); diff < -time.Minute || diff > time.Minute {
+				t.Errorf("Expected %v, got %v", tt.wantAt, at)
+			}
+		})
+	}
+}
+
+func TestAccountSnapshots_MockGW2API(t *testing.T) {
+	mock := httptest.NewServer(gw2mock.New())
+	defer mock.Close()
+
+	s, err := NewMCPServer(log.New(io.Discard),
+		WithGW2APIOptions(gw2api.WithBaseURL(mock.URL+"/v2")),
+		WithDatabase(filepath.Join(t.TempDir(), "gw2-mcp.db")),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	t.Cleanup(func() { _ = s.store.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// A week ago the account only held coins and fewer globs
+	earlier := gw2api.AccountValuation{
+		TakenAt:    time.Now().UTC().Add(-8 * 24 * time.Hour).Truncate(time.Second),
+		Account:    "Mock Account.1234",
+		Currencies: map[int]int{1: 10000000},
+		Items:      map[int]gw2api.ItemValue{19721: {Count: 430, Value: 881500}},
+		Breakdown:  gw2api.ValuationBreakdown{Wallet: 10000000, Materials: 881500},
+		Total:      10881500,
+	}
+	data, err := json.Marshal(earlier)
+	if err != nil {
+		t.Fatalf("Failed to encode valuation: %v", err)
+	}
+	if _, err := s.store.AddSnapshot(ctx, earlier.Account, earlier.TakenAt, earlier.Total, data); err != nil {
+		t.Fatalf("AddSnapshot failed: %v", err)
+	}
+
+	mcpClient := newInProcessClient(ctx, t, s)
+
+	tests := []struct {
+		name      string
+		tool      string
+		arguments map[string]any
+		isError   bool
+		contains  []string
+	}{
+		{
+			name:      "no later snapshot yet",
+			tool:      "diff_account",
+			arguments: map[string]any{"api_key": gw2mock.KeyFull},
+			isError:   true,
+			contains:  []string{"Both from and to select snapshot 1"},
+		},
+		{
+			name:      "snapshot",
+			tool:      "snapshot_account",
+			arguments: map[string]any{"api_key": gw2mock.KeyFull, "format": "compact"},
+			contains: []string{
+				`"account":"Mock Account.1234"`,
+				`"total_formatted":"1616g 93s 50c"`,
+				`"unvalued_items":[19675,30689]`,
+				`"id":2`,
+				`"total":16169350`,
+			},
+		},
+		{
+			name:      "snapshot without the inventories scope",
+			tool:      "snapshot_account",
+			arguments: map[string]any{"api_key": gw2mock.KeyTradingPost},
+			isError:   true,
+			contains:  []string{"Failed to value account", "requires scope"},
+		},
+		{
+			name:      "diff over the last week",
+			tool:      "diff_account",
+			arguments: map[string]any{"api_key": gw2mock.KeyFull, "format": "compact"},
+			contains: []string{
+				`"change_formatted":"528g 78s 50c"`,
+				`{"name":"Coin","id":1,"from":10000000,"to":12345678,"change":2345678}`,
+				`{"name":"Glob of Ectoplasm","id":19721,"count_change":100,"value_change":238500}`,
+			},
+		},
+		{
+			name:      "diff by IDs, in any order",
+			tool:      "diff_account",
+			arguments: map[string]any{"api_key": gw2mock.KeyFull, "from": "2", "to": "1", "item_limit": 1},
+			contains:  []string{"528g 78s 50c", `"items_changed": 7`},
+		},
+		{
+			name:      "unknown snapshot",
+			tool:      "diff_account",
+			arguments: map[string]any{"api_key": gw2mock.KeyFull, "from": "99"},
+			isError:   true,
+			contains:  []string{"No snapshot of Mock Account.1234 matches the from parameter"},
+		},
+		{
+			name:      "invalid reference",
+			tool:      "diff_account",
+			arguments: map[string]any{"api_key": gw2mock.KeyFull, "to": "yesterday"},
+			isError:   true,
+			contains:  []string{"Invalid to parameter"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callRequest := mcp.CallToolRequest{}
+			callRequest.Params.Name = tt.tool
+			callRequest.Params.Arguments = tt.arguments
+			result, err := mcpClient.CallTool(ctx, callRequest)
+			if err != nil {
+				t.Fatalf("Failed to call %s: %v", tt.tool, err)
+			}
+
+			text := result.Content[0].(mcp.TextContent).Text
+			if result.IsError != tt.isError {
+				t.Fatalf("Expected IsError %v, got %v: %s", tt.isError, result.IsError, text)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("Expected result to contain %q, got:\n%s", want, text)
+				}
+			}
+		})
+	}
+}
+
+func TestAccountSnapshots_RequireDatabase(t *testing.T) {
+	s, err := NewMCPServer(log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	for _, name := range []string{"snapshot_account", "diff_account"} {
+		if tool := s.mcp.GetTool(name); tool != nil {
+			t.Errorf("Expected %s to be unavailable without a database", name)
+		}
+	}
+}
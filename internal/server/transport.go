@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/AlyxPink/gw2-mcp/internal/auth"
+)
+
+// Transport identifies how the MCP server talks to its clients
+type Transport string
+
+// Supported transports
+const (
+	TransportStdio          Transport = "stdio"
+	TransportSSE            Transport = "sse"
+	TransportStreamableHTTP Transport = "http"
+)
+
+const (
+	// DefaultHTTPAddr is the listen address used by the HTTP transports when none is configured
+	DefaultHTTPAddr = "localhost:8080"
+
+	// httpReadHeaderTimeout protects the HTTP listener against slow clients
+	httpReadHeaderTimeout = 10 * time.Second
+
+	// streamableHTTPPath is the endpoint of the streamable HTTP transport
+	streamableHTTPPath = "/mcp"
+)
+
+// httpTransport is implemented by the mcp-go SSE and streamable HTTP servers
+type httpTransport interface {
+	http.Handler
+	Start(addr string) error
+	Shutdown(ctx context.Context) error
+}
+
+// ParseTransport converts a transport name into a Transport
+func ParseTransport(name string) (Transport, error) {
+	switch Transport(strings.ToLower(strings.TrimSpace(name))) {
+	case "", TransportStdio:
+		return TransportStdio, nil
+	case TransportSSE:
+		return TransportSSE, nil
+	case TransportStreamableHTTP, "streamable-http":
+		return TransportStreamableHTTP, nil
+	default:
+		return "", fmt.Errorf("unsupported transport %q (supported: stdio, sse, http)", name)
+	}
+}
+
+// WithTransport selects the transport used by Start
+func WithTransport(transport Transport) Option {
+	return func(s *MCPServer) {
+		s.transport = transport
+	}
+}
+
+// WithAuth requires HTTP clients to authenticate with a bearer token from the given store.
+// Tools needing a GW2 API key then use the key of the authenticated profile.
+func WithAuth(store *auth.Store) Option {
+	return func(s *MCPServer) {
+		s.auth = store
+	}
+}
+
+// WithHTTPAddr sets the listen address of the HTTP transports
+func WithHTTPAddr(addr string) Option {
+	return func(s *MCPServer) {
+		s.httpAddr = addr
+	}
+}
+
+// newHTTPTransport creates the mcp-go HTTP server matching the configured transport.
+// When httpServer is not nil, the transport listens and shuts down through it.
+func (s *MCPServer) newHTTPTransport(httpServer *http.Server) (httpTransport, error) {
+	switch s.transport {
+	case TransportSSE:
+		return mcpserver.NewSSEServer(s.mcp, mcpserver.WithHTTPServer(httpServer)), nil
+	case TransportStreamableHTTP:
+		return mcpserver.NewStreamableHTTPServer(s.mcp, mcpserver.WithStreamableHTTPServer(httpServer)), nil
+	default:
+		return nil, fmt.Errorf("transport %q is not served over HTTP", s.transport)
+	}
+}
+
+// httpHandler routes requests to the transport, behind authentication when enabled
+func (s *MCPServer) httpHandler(transport httpTransport) http.Handler {
+	var handler http.Handler = transport
+
+	// The SSE server routes its own endpoints, the streamable HTTP server does not
+	if s.transport == TransportStreamableHTTP {
+		mux := http.NewServeMux()
+		mux.Handle(streamableHTTPPath, transport)
+		handler = mux
+	}
+
+	if s.auth != nil {
+		handler = s.auth.Middleware(handler)
+	}
+
+	return handler
+}
+
+// startStdio serves MCP over standard input/output until the context is cancelled
+func (s *MCPServer) startStdio(ctx context.Context) error {
+	s.logger.Info("Starting MCP server on stdio")
+
+	// The listener gets its own context so in-flight calls can finish after ctx is cancelled
+	listenCtx, stopListening := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopListening()
+
+	// Create a channel to capture listener errors
+	errChan := make(chan error, 1)
+
+	stdioServer := mcpserver.NewStdioServer(s.mcp)
+	stdioServer.SetErrorLogger(s.logger.StandardLog(log.StandardLogOptions{ForceLevel: log.ErrorLevel}))
+
+	go func() {
+		errChan <- stdioServer.Listen(listenCtx, s.stdin, s.stdout)
+	}()
+
+	// Wait for either context cancellation or listener error
+	select {
+	case <-ctx.Done():
+		return s.shutdown(func(context.Context) error {
+			stopListening()
+			return nil
+		})
+	case err := <-errChan:
+		// Stdin was closed by the client, which is a normal way to end a stdio session
+		return errors.Join(err, s.shutdown(func(context.Context) error { return nil }))
+	}
+}
+
+// startHTTP serves MCP over HTTP until the context is cancelled, then drains open connections
+func (s *MCPServer) startHTTP(ctx context.Context) error {
+	httpServer := &http.Server{
+		Addr:              s.httpAddr,
+		ReadHeaderTimeout: httpReadHeaderTimeout,
+	}
+
+	transport, err := s.newHTTPTransport(httpServer)
+	if err != nil {
+		return err
+	}
+	httpServer.Handler = s.httpHandler(transport)
+
+	if s.auth == nil {
+		s.logger.Warn("HTTP transport running without authentication, clients must pass their own API keys")
+	}
+
+	s.logger.Info("Starting MCP server over HTTP", "transport", s.transport, "addr", s.httpAddr)
+
+	// Create a channel to capture listener errors
+	errChan := make(chan error, 1)
+
+	go func() {
+		errChan <- transport.Start(s.httpAddr)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return s.shutdown(transport.Shutdown)
+	case err := <-errChan:
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+		return errors.Join(err, s.shutdown(transport.Shutdown))
+	}
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestParseTransport(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Transport
+		wantErr  bool
+	}{
+		{input: "", expected: TransportStdio},
+		{input: "stdio", expected: TransportStdio},
+		{input: "SSE", expected: TransportSSE},
+		{input: "http", expected: TransportStreamableHTTP},
+		{input: "streamable-http", expected: TransportStreamableHTTP},
+		{input: "websocket", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		transport, err := ParseTransport(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseTransport(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if transport != tt.expected {
+			t.Errorf("ParseTransport(%q) = %q, want %q", tt.input, transport, tt.expected)
+		}
+	}
+}
+
+func TestHTTPTransports(t *testing.T) {
+	tests := []struct {
+		name      string
+		transport Transport
+		newClient func(baseURL string) (*client.Client, error)
+	}{
+		{
+			name:      "SSE",
+			transport: TransportSSE,
+			newClient: func(baseURL string) (*client.Client, error) {
+				return client.NewSSEMCPClient(baseURL + "/sse")
+			},
+		},
+		{
+			name:      "Streamable HTTP",
+			transport: TransportStreamableHTTP,
+			newClient: func(baseURL string) (*client.Client, error) {
+				return client.NewStreamableHttpClient(baseURL + "/mcp")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewMCPServer(log.New(io.Discard), WithTransport(tt.transport))
+			if err != nil {
+				t.Fatalf("Failed to create server: %v", err)
+			}
+
+			transport, err := s.newHTTPTransport(nil)
+			if err != nil {
+				t.Fatalf("Failed to create HTTP transport: %v", err)
+			}
+
+			httpServer := httptest.NewServer(s.httpHandler(transport))
+			defer httpServer.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			mcpClient, err := tt.newClient(httpServer.URL)
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			defer func() { _ = mcpClient.Close() }()
+
+			if err := mcpClient.Start(ctx); err != nil {
+				t.Fatalf("Failed to start client: %v", err)
+			}
+
+			initRequest := mcp.InitializeRequest{}
+			initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+			initRequest.Params.ClientInfo = mcp.Implementation{Name: "gw2-mcp-test", Version: "1.0.0"}
+			if _, err := mcpClient.Initialize(ctx, initRequest); err != nil {
+				t.Fatalf("Failed to initialize: %v", err)
+			}
+
+			tools, err := mcpClient.ListTools(ctx, mcp.ListToolsRequest{})
+			if err != nil {
+				t.Fatalf("Failed to list tools: %v", err)
+			}
+
+			found := false
+			for _, tool := range tools.Tools {
+				if tool.Name == "get_currencies" {
+					found = true
+				}
+			}
+			if !found {
+				t.Error("Expected get_currencies tool to be listed")
+			}
+
+			// An invalid language is rejected before any upstream request is made
+			callRequest := mcp.CallToolRequest{}
+			callRequest.Params.Name = "get_currencies"
+			callRequest.Params.Arguments = map[string]any{"lang": "xx"}
+			result, err := mcpClient.CallTool(ctx, callRequest)
+			if err != nil {
+				t.Fatalf("Failed to call tool: %v", err)
+			}
+			if !result.IsError {
+				t.Error("Expected tool error for invalid language")
+			}
+		})
+	}
+}
+
+func TestStart_HTTPShutdown(t *testing.T) {
+	s, err := NewMCPServer(log.New(io.Discard),
+		WithTransport(TransportStreamableHTTP),
+		WithHTTPAddr("127.0.0.1:0"),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- s.Start(ctx)
+	}()
+
+	// Give the listener a moment to come up before shutting it down
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errChan:
+		if err != nil {
+			t.Errorf("Expected clean shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Server did not shut down after context cancellation")
+	}
+}
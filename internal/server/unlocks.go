@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+)
+
+// Unlock report defaults and bounds
+const (
+	defaultUnlockLimit = 50
+	maxUnlockLimit     = 500
+)
+
+// handleGetUnlocks handles account unlock collection requests
+func (s *MCPServer) handleGetUnlocks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	kind, err := request.RequireString("kind")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid kind parameter: %v", err)), nil
+	}
+
+	query := gw2api.UnlockQuery{
+		Category: strings.TrimSpace(request.GetString("category", "")),
+		Name:     strings.TrimSpace(request.GetString("name", "")),
+		Limit:    request.GetInt("limit", defaultUnlockLimit),
+	}
+
+	if query.Kind, err = gw2api.ParseUnlockKind(kind); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid kind parameter: %v", err)), nil
+	}
+
+	if query.List, err = gw2api.ParseUnlockList(request.GetString("list", "")); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid list parameter: %v", err)), nil
+	}
+
+	if query.Limit < 1 || query.Limit > maxUnlockLimit {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid limit parameter: must be between 1 and %d",
+			maxUnlockLimit)), nil
+	}
+
+	apiKey, err := s.resolveAPIKey(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if query.Lang, err = gw2api.ParseLanguage(request.GetString("lang", ""), s.defaultLang); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid lang parameter: %v", err)), nil
+	}
+
+	format, err := ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format parameter: %v", err)), nil
+	}
+
+	s.logger.Debug("Unlocks request", "kind", query.Kind, "category", query.Category, "list", query.List)
+
+	report, err := s.gw2API.GetUnlockReport(ctx, apiKey, query)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get %s: %v", query.Kind, err)), nil
+	}
+
+	return s.structuredResult(report, nil, format, string(query.Kind)), nil
+}
+
+// unlockKindNames returns the supported unlock kinds
+func unlockKindNames() []string {
+	kinds := gw2api.UnlockKinds()
+	names := make([]string, len(kinds))
+	for i, kind := range kinds {
+		names[i] = string(kind)
+	}
+	return names
+}
@@ -0,0 +1,208 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// TrendThresholdPercent is the change over a window, relative to the average price, below
+// which a price is considered flat
+const TrendThresholdPercent = 2
+
+// PriceSample is the best buy order and sell listing of an item at one point in time
+type PriceSample struct {
+	Time         time.Time `json:"time"`
+	ItemID       int       `json:"item_id"`
+	BuyPrice     int       `json:"buy_price"` // 0 when there is no buy order
+	BuyQuantity  int       `json:"buy_quantity"`
+	SellPrice    int       `json:"sell_price"` // 0 when there is no sell listing
+	SellQuantity int       `json:"sell_quantity"`
+}
+
+// Trend describes the direction of a price over a window
+type Trend string
+
+const (
+	// TrendRising means the price went up by more than TrendThresholdPercent
+	TrendRising Trend = "rising"
+	// TrendFalling means the price went down by more than TrendThresholdPercent
+	TrendFalling Trend = "falling"
+	// TrendFlat means the price stayed within TrendThresholdPercent
+	TrendFlat Trend = "flat"
+	// TrendUnknown means there were no prices to compare
+	TrendUnknown Trend = "unknown"
+)
+
+// PriceStats summarizes one side of the order book over a window. Samples without an
+// order on that side are ignored.
+type PriceStats struct {
+	Trend            Trend   `json:"trend"`
+	Current          int     `json:"current"` // most recent price
+	Min              int     `json:"min"`
+	Max              int     `json:"max"`
+	Average          int     `json:"average"`
+	VsAveragePercent float64 `json:"vs_average_percent"` // current price relative to the average
+	RangePercent     float64 `json:"range_percent"`      // current price between the min (0) and the max (100)
+	TrendPercent     float64 `json:"trend_percent"`      // change along a least squares fit, relative to the average
+	Samples          int     `json:"samples"`
+}
+
+// PriceHistory summarizes the recorded prices of an item over a window
+type PriceHistory struct {
+	Since       time.Time  `json:"since"`
+	Until       time.Time  `json:"until"`
+	FirstSample *time.Time `json:"first_sample,omitempty"`
+	LastSample  *time.Time `json:"last_sample,omitempty"`
+	ItemName    string     `json:"item_name,omitempty"`
+	Buy         PriceStats `json:"buy"`  // highest buy order, what selling instantly yields
+	Sell        PriceStats `json:"sell"` // lowest sell listing, what buying instantly costs
+	ItemID      int        `json:"item_id"`
+	Samples     int        `json:"samples"`
+}
+
+// AddPriceSamples records price samples, replacing any sample of the same item at the same second
+func (s *Store) AddPriceSamples(ctx context.Context, samples []PriceSample) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT OR REPLACE INTO price_samples
+		(item_id, sampled_at, buy_price, buy_quantity, sell_price, sell_quantity) VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare price insert: %w", err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, sample := range samples {
+		if _, err := stmt.ExecContext(ctx, sample.ItemID, sample.Time.Unix(), sample.BuyPrice, sample.BuyQuantity,
+			sample.SellPrice, sample.SellQuantity); err != nil {
+			return fmt.Errorf("failed to record price of item %d: %w", sample.ItemID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit price samples: %w", err)
+	}
+	return nil
+}
+
+// PriceSamples returns the samples of an item recorded between since and until, inclusive, oldest first
+func (s *Store) PriceSamples(ctx context.Context, itemID int, since, until time.Time) ([]PriceSample, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT sampled_at, buy_price, buy_quantity, sell_price, sell_quantity
+		FROM price_samples WHERE item_id = ? AND sampled_at >= ? AND sampled_at <= ? ORDER BY sampled_at`,
+		itemID, since.Unix(), until.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prices of item %d: %w", itemID, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	samples := []PriceSample{}
+	for rows.Next() {
+		sample := PriceSample{ItemID: itemID}
+		var sampledAt int64
+		if err := rows.Scan(&sampledAt, &sample.BuyPrice, &sample.BuyQuantity, &sample.SellPrice,
+			&sample.SellQuantity); err != nil {
+			return nil, fmt.Errorf("failed to read price of item %d: %w", itemID, err)
+		}
+		sample.Time = time.Unix(sampledAt, 0).UTC()
+		samples = append(samples, sample)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read prices of item %d: %w", itemID, err)
+	}
+
+	return samples, nil
+}
+
+// SummarizePrices computes the price statistics of samples of one item, oldest first
+func SummarizePrices(itemID int, since, until time.Time, samples []PriceSample) PriceHistory {
+	history := PriceHistory{
+		Since:   since,
+		Until:   until,
+		ItemID:  itemID,
+		Samples: len(samples),
+	}
+	if len(samples) > 0 {
+		history.FirstSample = &samples[0].Time
+		history.LastSample = &samples[len(samples)-1].Time
+	}
+
+	history.Buy = priceStats(samples, func(sample PriceSample) int { return sample.BuyPrice })
+	history.Sell = priceStats(samples, func(sample PriceSample) int { return sample.SellPrice })
+
+	return history
+}
+
+// priceStats summarizes the non-zero prices selected by price
+func priceStats(samples []PriceSample, price func(PriceSample) int) PriceStats {
+	var points []PriceSample
+	for _, sample := range samples {
+		if price(sample) > 0 {
+			points = append(points, sample)
+		}
+	}
+	if len(points) == 0 {
+		return PriceStats{Trend: TrendUnknown}
+	}
+
+	stats := PriceStats{
+		Current: price(points[len(points)-1]),
+		Min:     math.MaxInt,
+		Samples: len(points),
+	}
+
+	sum := 0
+	for _, point := range points {
+		value := price(point)
+		stats.Min = min(stats.Min, value)
+		stats.Max = max(stats.Max, value)
+		sum += value
+	}
+	average := float64(sum) / float64(len(points))
+	stats.Average = int(math.Round(average))
+
+	stats.VsAveragePercent = roundPercent((float64(stats.Current) - average) / average * 100)
+	if stats.Max > stats.Min {
+		stats.RangePercent = roundPercent(float64(stats.Current-stats.Min) / float64(stats.Max-stats.Min) * 100)
+	}
+
+	// Fit a line through the prices over time and compare its start and end, so a single
+	// outlier at either end of the window does not decide the trend
+	start := points[0].Time
+	var meanHours float64
+	for _, point := range points {
+		meanHours += point.Time.Sub(start).Hours()
+	}
+	meanHours /= float64(len(points))
+
+	var covariance, variance float64
+	for _, point := range points {
+		dx := point.Time.Sub(start).Hours() - meanHours
+		covariance += dx * (float64(price(point)) - average)
+		variance += dx * dx
+	}
+	if variance > 0 {
+		span := points[len(points)-1].Time.Sub(start).Hours()
+		stats.TrendPercent = roundPercent(covariance / variance * span / average * 100)
+	}
+
+	switch {
+	case stats.TrendPercent > TrendThresholdPercent:
+		stats.Trend = TrendRising
+	case stats.TrendPercent < -TrendThresholdPercent:
+		stats.Trend = TrendFalling
+	default:
+		stats.Trend = TrendFlat
+	}
+
+	return stats
+}
+
+// roundPercent rounds a percentage to two decimals
+func roundPercent(percent float64) float64 {
+	return math.Round(percent*100) / 100
+}
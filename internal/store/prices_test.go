@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestStore_PriceSamples(t *testing.T) {
+	s, _ := openTestStore(t)
+	ctx := context.Background()
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	samples := []PriceSample{
+		{ItemID: 19721, Time: start, BuyPrice: 2400, BuyQuantity: 10, SellPrice: 2550, SellQuantity: 5},
+		{ItemID: 19721, Time: start.Add(time.Hour), BuyPrice: 2410, BuyQuantity: 11, SellPrice: 2560, SellQuantity: 6},
+		{ItemID: 19976, Time: start.Add(time.Hour), BuyPrice: 10300, SellPrice: 11500},
+		{ItemID: 19721, Time: start.Add(2 * time.Hour), BuyPrice: 2420, SellPrice: 2570},
+	}
+	if err := s.AddPriceSamples(ctx, samples); err != nil {
+		t.Fatalf("AddPriceSamples failed: %v", err)
+	}
+
+	// A sample at the same second replaces the previous one
+	replaced := PriceSample{ItemID: 19721, Time: start.Add(time.Hour), BuyPrice: 2415, BuyQuantity: 12,
+		SellPrice: 2565, SellQuantity: 7}
+	if err := s.AddPriceSamples(ctx, []PriceSample{replaced}); err != nil {
+		t.Fatalf("AddPriceSamples failed: %v", err)
+	}
+
+	got, err := s.PriceSamples(ctx, 19721, start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("PriceSamples failed: %v", err)
+	}
+	want := []PriceSample{samples[0], replaced}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	got, err = s.PriceSamples(ctx, 24277, start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("PriceSamples failed: %v", err)
+	}
+	if got == nil || len(got) != 0 {
+		t.Errorf("Expected no samples, got %+v", got)
+	}
+}
+
+func TestSummarizePrices(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	series := func(buys, sells []int) []PriceSample {
+		samples := make([]PriceSample, len(buys))
+		for i := range buys {
+			samples[i] = PriceSample{ItemID: 1, Time: start.Add(time.Duration(i) * time.Hour),
+				BuyPrice: buys[i], SellPrice: sells[i]}
+		}
+		return samples
+	}
+
+	tests := []struct {
+		name     string
+		samples  []PriceSample
+		wantBuy  PriceStats
+		wantSell PriceStats
+	}{
+		{
+			name:    "rising buys and falling sells",
+			samples: series([]int{100, 110, 120, 130}, []int{200, 190, 180, 170}),
+			wantBuy: PriceStats{Trend: TrendRising, Current: 130, Min: 100, Max: 130, Average: 115,
+				VsAveragePercent: 13.04, RangePercent: 100, TrendPercent: 26.09, Samples: 4},
+			wantSell: PriceStats{Trend: TrendFalling, Current: 170, Min: 170, Max: 200, Average: 185,
+				VsAveragePercent: -8.11, RangePercent: 0, TrendPercent: -16.22, Samples: 4},
+		},
+		{
+			name:    "flat with a dip, missing buy orders ignored",
+			samples: series([]int{0, 1000, 0, 1000}, []int{1000, 990, 1000, 1005}),
+			wantBuy: PriceStats{Trend: TrendFlat, Current: 1000, Min: 1000, Max: 1000, Average: 1000,
+				Samples: 2},
+			wantSell: PriceStats{Trend: TrendFlat, Current: 1005, Min: 990, Max: 1005, Average: 999,
+				VsAveragePercent: 0.63, RangePercent: 100, TrendPercent: 0.75, Samples: 4},
+		},
+		{
+			name:     "no samples",
+			wantBuy:  PriceStats{Trend: TrendUnknown},
+			wantSell: PriceStats{Trend: TrendUnknown},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := SummarizePrices(1, start, start.Add(24*time.Hour), tt.samples)
+			if history.Samples != len(tt.samples) {
+				t.Errorf("Expected %d samples, got %d", len(tt.samples), history.Samples)
+			}
+			if history.Buy != tt.wantBuy {
+				t.Errorf("Expected buy stats %+v, got %+v", tt.wantBuy, history.Buy)
+			}
+			if history.Sell != tt.wantSell {
+				t.Errorf("Expected sell stats %+v, got %+v", tt.wantSell, history.Sell)
+			}
+		})
+	}
+}
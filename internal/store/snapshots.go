@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotFound is returned when no record matches a lookup
+var ErrNotFound = errors.New("not found")
+
+// Snapshot is a valuation of an account recorded at one point in time. The valuation
+// itself is opaque to the store.
+type Snapshot struct {
+	TakenAt time.Time       `json:"taken_at"`
+	Account string          `json:"account"`
+	Data    json.RawMessage `json:"data"`
+	ID      int64           `json:"id"`
+	Total   int             `json:"total"` // in copper
+}
+
+// AddSnapshot records a valuation of an account and returns its ID
+func (s *Store) AddSnapshot(ctx context.Context, account string, takenAt time.Time, total int,
+	data json.RawMessage,
+) (int64, error) {
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO account_snapshots (account, taken_at, total, data) VALUES (?, ?, ?, ?)`,
+		account, takenAt.Unix(), total, string(data))
+	if err != nil {
+		return 0, fmt.Errorf("failed to record snapshot of %s: %w", account, err)
+	}
+	return result.LastInsertId()
+}
+
+// Snapshot returns a snapshot of an account by ID, or ErrNotFound
+func (s *Store) Snapshot(ctx context.Context, account string, id int64) (*Snapshot, error) {
+	return s.querySnapshot(ctx, `SELECT id, account, taken_at, total, data FROM account_snapshots
+		WHERE account = ? AND id = ?`, account, id)
+}
+
+// SnapshotAt returns the latest snapshot of an account taken at or before at, or the
+// earliest one when every snapshot is later, or ErrNotFound when there is none
+func (s *Store) SnapshotAt(ctx context.Context, account string, at time.Time) (*Snapshot, error) {
+	snapshot, err := s.querySnapshot(ctx, `SELECT id, account, taken_at, total, data FROM account_snapshots
+		WHERE account = ? AND taken_at <= ? ORDER BY taken_at DESC, id DESC LIMIT 1`, account, at.Unix())
+	if !errors.Is(err, ErrNotFound) {
+		return snapshot, err
+	}
+	return s.querySnapshot(ctx, `SELECT id, account, taken_at, total, data FROM account_snapshots
+		WHERE account = ? ORDER BY taken_at, id LIMIT 1`, account)
+}
+
+// querySnapshot returns the snapshot selected by query, or ErrNotFound
+func (s *Store) querySnapshot(ctx context.Context, query string, args ...any) (*Snapshot, error) {
+	var snapshot Snapshot
+	var takenAt int64
+	var data string
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&snapshot.ID, &snapshot.Account, &takenAt,
+		&snapshot.Total, &data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	snapshot.TakenAt = time.Unix(takenAt, 0).UTC()
+	snapshot.Data = json.RawMessage(data)
+	return &snapshot, nil
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestStore_Snapshots(t *testing.T) {
+	s, _ := openTestStore(t)
+	ctx := context.Background()
+
+	if _, err := s.SnapshotAt(ctx, "Mock Account.1234", time.Now()); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound without snapshots, got %v", err)
+	}
+
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	var ids []int64
+	for day := range 3 {
+		id, err := s.AddSnapshot(ctx, "Mock Account.1234", start.AddDate(0, 0, day*7), 1000*(day+1),
+			json.RawMessage(fmt.Sprintf(`{"total":%d}`, 1000*(day+1))))
+		if err != nil {
+			t.Fatalf("AddSnapshot failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if _, err := s.AddSnapshot(ctx, "Other.5678", start, 1, json.RawMessage(`{}`)); err != nil {
+		t.Fatalf("AddSnapshot failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want int64
+	}{
+		{name: "exact time", at: start.AddDate(0, 0, 7), want: ids[1]},
+		{name: "between snapshots", at: start.AddDate(0, 0, 10), want: ids[1]},
+		{name: "after the last", at: start.AddDate(1, 0, 0), want: ids[2]},
+		{name: "before the first", at: start.AddDate(0, 0, -30), want: ids[0]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot, err := s.SnapshotAt(ctx, "Mock Account.1234", tt.at)
+			if err != nil {
+				t.Fatalf("SnapshotAt failed: %v", err)
+			}
+			if snapshot.ID != tt.want {
+				t.Errorf("Expected snapshot %d, got %d", tt.want, snapshot.ID)
+			}
+		})
+	}
+
+	snapshot, err := s.Snapshot(ctx, "Mock Account.1234", ids[1])
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	if !snapshot.TakenAt.Equal(start.AddDate(0, 0, 7)) || snapshot.Total != 2000 ||
+		string(snapshot.Data) != `{"total":2000}` {
+		t.Errorf("Unexpected snapshot %+v", snapshot)
+	}
+
+	// Snapshots of other accounts are never returned
+	if _, err := s.Snapshot(ctx, "Other.5678", ids[1]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for another account, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+// Package store keeps data the GW2 API does not retain, such as past trading post prices
+// and account valuations, in a local SQLite database.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "modernc.org/sqlite" // registers the pure Go sqlite driver
+)
+
+// migrations upgrade the schema one version at a time. The number of applied migrations
+// is kept in the user_version pragma, so migrations must only ever be appended.
+var migrations = []string{
+	`CREATE TABLE price_samples (
+		item_id       INTEGER NOT NULL,
+		sampled_at    INTEGER NOT NULL, -- unix seconds
+		buy_price     INTEGER NOT NULL,
+		buy_quantity  INTEGER NOT NULL,
+		sell_price    INTEGER NOT NULL,
+		sell_quantity INTEGER NOT NULL,
+		PRIMARY KEY (item_id, sampled_at)
+	) WITHOUT ROWID`,
+	`CREATE TABLE account_snapshots (
+		id       INTEGER PRIMARY KEY AUTOINCREMENT,
+		account  TEXT    NOT NULL,
+		taken_at INTEGER NOT NULL, -- unix seconds
+		total    INTEGER NOT NULL, -- copper
+		data     TEXT    NOT NULL  -- valuation as JSON
+	);
+	CREATE INDEX account_snapshots_taken_at ON account_snapshots (account, taken_at)`,
+}
+
+// Store is a local SQLite database safe for concurrent use
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens or creates the database at path and brings its schema up to date
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+
+	// SQLite allows a single writer, so serialize access instead of retrying on busy errors
+	db.SetMaxOpenConns(1)
+
+	s := &Store{db: db}
+	if err := s.migrate(context.Background()); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to migrate database %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// migrate applies the migrations the database has not seen yet
+func (s *Store) migrate(ctx context.Context) error {
+	var version int
+	if err := s.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("schema version %d is newer than this server supports (%d)", version, len(migrations))
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migration %d failed: %w", i+1, err)
+		}
+		// PRAGMA does not accept bound parameters
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to record schema version %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
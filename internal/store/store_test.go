@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// openTestStore opens a store in a temporary directory, closed when the test ends
+func openTestStore(t *testing.T) (*Store, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "gw2-mcp.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s, path
+}
+
+func TestOpen_Migrations(t *testing.T) {
+	s, path := openTestStore(t)
+
+	var version int
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		t.Fatalf("Failed to read schema version: %v", err)
+	}
+	if version != len(migrations) {
+		t.Errorf("Expected schema version %d, got %d", len(migrations), version)
+	}
+
+	// Migrations may hold several statements
+	var indexes int
+	if err := s.db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type = 'index'
+		AND name = 'account_snapshots_taken_at'`).Scan(&indexes); err != nil || indexes != 1 {
+		t.Errorf("Expected the snapshot index to exist, got %d (%v)", indexes, err)
+	}
+
+	// Reopening an up to date database applies nothing
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer reopened.Close()
+
+	// A database written by a newer server is refused
+	if _, err := reopened.db.ExecContext(context.Background(), "PRAGMA user_version = 99"); err != nil {
+		t.Fatalf("Failed to bump schema version: %v", err)
+	}
+	if err := reopened.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := Open(path); err == nil || !strings.Contains(err.Error(), "newer than this server supports") {
+		t.Errorf("Expected a schema version error, got %v", err)
+	}
+}
@@ -14,13 +14,18 @@ import (
 	"github.com/charmbracelet/log"
 
 	"github.com/AlyxPink/gw2-mcp/internal/cache"
+	"github.com/AlyxPink/gw2-mcp/internal/progress"
 )
 
 const (
-	wikiBaseURL    = "https://wiki.guildwars2.com"
-	wikiAPIURL     = wikiBaseURL + "/api.php"
-	userAgent      = "github.com/AlyxPink/gw2-mcp"
-	requestTimeout = 30 * time.Second
+	// DefaultUserAgent identifies the server in upstream requests
+	DefaultUserAgent = "github.com/AlyxPink/gw2-mcp"
+
+	// DefaultTimeout is the default timeout of wiki API requests
+	DefaultTimeout = 30 * time.Second
+
+	// maxCategoryMembersPerRequest is the MediaWiki cmlimit cap for anonymous clients
+	maxCategoryMembersPerRequest = 500
 )
 
 // Client handles wiki API requests
@@ -28,6 +33,58 @@ type Client struct {
 	httpClient *http.Client
 	cache      *cache.Manager
 	logger     *log.Logger
+	userAgent  string
+	baseURLs   map[Language]string
+
+	// HTTP client settings, applied to a copy of httpClient once all options are set
+	timeout   time.Duration
+	transport http.RoundTripper
+}
+
+// Option configures optional Client settings
+type Option func(*Client)
+
+// WithBaseURL overrides the base URL of the wiki for a language, e.g. to use a mirror
+func WithBaseURL(lang Language, baseURL string) Option {
+	return func(c *Client) {
+		c.baseURLs[lang] = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithTransport sets the transport of wiki API requests, e.g. to record or replay fixtures
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
+
+// WithHTTPClient sets the HTTP client of wiki API requests, e.g. one going through a proxy.
+// The client is copied, so timeout and transport options never modify it.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header of wiki API requests
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithLogger replaces the logger given to NewClient
+func WithLogger(logger *log.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithTimeout sets the timeout of wiki API requests
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
 }
 
 // SearchResult represents a single search result from the wiki
@@ -46,10 +103,20 @@ type SearchResult struct {
 type SearchResponse struct {
 	SearchedAt time.Time      `json:"searched_at"`
 	Query      string         `json:"query"`
+	Language   Language       `json:"language"`
 	Results    []SearchResult `json:"results"`
 	Total      int            `json:"total"`
 }
 
+// Page represents a wiki page summary with its interlanguage links
+type Page struct {
+	Title        string              `json:"title"`
+	Language     Language            `json:"language"`
+	URL          string              `json:"url"`
+	Extract      string              `json:"extract"`
+	Translations map[Language]string `json:"translations,omitempty"`
+}
+
 // APIResponse represents the MediaWiki API response structure
 type APIResponse struct {
 	BatchComplete string `json:"batchcomplete"`
@@ -82,59 +149,170 @@ type PageContentResponse struct {
 	BatchComplete string `json:"batchcomplete"`
 }
 
+// LangLinksResponse represents the interlanguage links API response
+type LangLinksResponse struct {
+	Query struct {
+		Pages map[string]struct {
+			Title     string `json:"title"`
+			LangLinks []struct {
+				Lang  string `json:"lang"`
+				Title string `json:"*"`
+			} `json:"langlinks"`
+			PageID int `json:"pageid"`
+		} `json:"pages"`
+	} `json:"query"`
+}
+
+// CategoryMember represents a page belonging to a wiki category
+type CategoryMember struct {
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	PageID int    `json:"pageid"`
+	NS     int    `json:"ns"`
+}
+
+// CategoryMembersResponse represents the members of a wiki category
+type CategoryMembersResponse struct {
+	FetchedAt time.Time        `json:"fetched_at"`
+	Category  string           `json:"category"`
+	Language  Language         `json:"language"`
+	Members   []CategoryMember `json:"members"`
+	Total     int              `json:"total"`
+	Truncated bool             `json:"truncated"`
+}
+
+// RecentChange represents a single entry of the wiki recent changes feed
+type RecentChange struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+	User      string `json:"user"`
+	Comment   string `json:"comment,omitempty"`
+	Timestamp string `json:"timestamp"`
+	NS        int    `json:"ns"`
+	OldSize   int    `json:"old_size"`
+	NewSize   int    `json:"new_size"`
+}
+
+// RecentChangesResponse represents the recent changes of a wiki
+type RecentChangesResponse struct {
+	FetchedAt time.Time      `json:"fetched_at"`
+	Language  Language       `json:"language"`
+	Changes   []RecentChange `json:"changes"`
+	Namespace int            `json:"namespace"`
+	Total     int            `json:"total"`
+}
+
+// CategoryMembersAPIResponse represents the MediaWiki categorymembers API response
+type CategoryMembersAPIResponse struct {
+	Continue map[string]string `json:"continue"`
+	Query    struct {
+		CategoryMembers []struct {
+			Title  string `json:"title"`
+			PageID int    `json:"pageid"`
+			NS     int    `json:"ns"`
+		} `json:"categorymembers"`
+	} `json:"query"`
+}
+
+// RecentChangesAPIResponse represents the MediaWiki recentchanges API response
+type RecentChangesAPIResponse struct {
+	Query struct {
+		RecentChanges []struct {
+			Type      string `json:"type"`
+			Title     string `json:"title"`
+			User      string `json:"user"`
+			Comment   string `json:"comment"`
+			Timestamp string `json:"timestamp"`
+			NS        int    `json:"ns"`
+			OldLen    int    `json:"oldlen"`
+			NewLen    int    `json:"newlen"`
+		} `json:"recentchanges"`
+	} `json:"query"`
+}
+
 // NewClient creates a new wiki client
-func NewClient(cacheManager *cache.Manager, logger *log.Logger) *Client {
-	return &Client{
-		httpClient: &http.Client{
-			Timeout: requestTimeout,
-		},
-		cache:  cacheManager,
-		logger: logger,
+func NewClient(cacheManager *cache.Manager, logger *log.Logger, opts ...Option) *Client {
+	c := &Client{
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		cache:      cacheManager,
+		logger:     logger,
+		userAgent:  DefaultUserAgent,
+		baseURLs:   make(map[Language]string),
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	httpClient := *c.httpClient
+	if c.timeout > 0 {
+		httpClient.Timeout = c.timeout
+	}
+	if c.transport != nil {
+		httpClient.Transport = c.transport
+	}
+	c.httpClient = &httpClient
+
+	if c.logger == nil {
+		c.logger = log.New(io.Discard)
+	}
+
+	return c
 }
 
-// Search performs a search on the Guild Wars 2 wiki
-func (c *Client) Search(ctx context.Context, query string, limit int) (*SearchResponse, error) {
+// Search performs a search on the Guild Wars 2 wiki of the given language, with snippets
+// and extracts rendered in the given text format
+func (c *Client) Search(ctx context.Context, query string, limit int, lang Language,
+	textFormat TextFormat,
+) (*SearchResponse, error) {
 	// Normalize query for caching
 	normalizedQuery := strings.ToLower(strings.TrimSpace(query))
-	cacheKey := c.cache.GetWikiSearchKey(normalizedQuery)
+	cacheKey := c.cache.GetWikiSearchKey(string(lang), string(textFormat), normalizedQuery)
 
 	// Try cache first
 	var searchResponse SearchResponse
 	if c.cache.GetJSON(cacheKey, &searchResponse) {
-		c.logger.Debug("Wiki search cache hit", "query", query)
+		c.logger.Debug("Wiki search cache hit", "query", query, "lang", lang)
 		return &searchResponse, nil
 	}
 
-	c.logger.Debug("Wiki search cache miss, fetching from API", "query", query)
+	c.logger.Debug("Wiki search cache miss, fetching from API", "query", query, "lang", lang)
 
 	// Perform search
-	searchResults, err := c.performSearch(ctx, query, limit)
+	searchResults, err := c.performSearch(ctx, query, limit, lang, textFormat)
 	if err != nil {
 		return nil, fmt.Errorf("search failed: %w", err)
 	}
 
 	// Enhance results with page extracts
 	for i := range searchResults {
-		extract, err := c.getPageExtract(ctx, searchResults[i].Title)
+		extract, err := c.getPageExtract(ctx, searchResults[i].Title, lang, textFormat)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			// Stop fetching and do not cache partial results of a cancelled call
+			return nil, ctxErr
+		}
 		if err != nil {
 			c.logger.Warn("Failed to get page extract", "title", searchResults[i].Title, "error", err)
 		} else {
 			searchResults[i].Extract = extract
 		}
-		searchResults[i].URL = fmt.Sprintf("%s/wiki/%s", wikiBaseURL, url.QueryEscape(searchResults[i].Title))
+		searchResults[i].URL = pageURL(c.baseURL(lang), searchResults[i].Title)
+		progress.Report(ctx, i+1, len(searchResults),
+			fmt.Sprintf("fetched %d/%d page extracts", i+1, len(searchResults)))
 	}
 
 	// Create response
 	searchResponse = SearchResponse{
 		Query:      query,
+		Language:   lang,
 		Results:    searchResults,
 		Total:      len(searchResults),
 		SearchedAt: time.Now(),
 	}
 
 	// Cache the result
-	if err := c.cache.SetJSON(cacheKey, searchResponse, cache.WikiDataTTL); err != nil {
+	if err := c.cache.SetJSON(cacheKey, searchResponse, c.cache.TTLs().WikiData); err != nil {
 		c.logger.Warn("Failed to cache search results", "error", err)
 	}
 
@@ -142,7 +320,9 @@ func (c *Client) Search(ctx context.Context, query string, limit int) (*SearchRe
 }
 
 // performSearch makes the actual search API call
-func (c *Client) performSearch(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+func (c *Client) performSearch(ctx context.Context, query string, limit int, lang Language,
+	textFormat TextFormat,
+) ([]SearchResult, error) {
 	// Build search URL
 	params := url.Values{
 		"action":   {"query"},
@@ -153,37 +333,9 @@ func (c *Client) performSearch(ctx context.Context, query string, limit int) ([]
 		"srprop":   {"size|wordcount|timestamp|snippet"},
 	}
 
-	searchURL := fmt.Sprintf("%s?%s", wikiAPIURL, params.Encode())
-
-	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, http.NoBody)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("User-Agent", userAgent)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			c.logger.Warn("Failed to close response body", "error", closeErr)
-		}
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		body, readErr := io.ReadAll(resp.Body)
-		if readErr != nil {
-			return nil, fmt.Errorf("wiki API request failed with status %d and failed to read body: %w",
-				resp.StatusCode, readErr)
-		}
-		return nil, fmt.Errorf("wiki API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
 	var apiResponse APIResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
-		return nil, fmt.Errorf("failed to decode search response: %w", err)
+	if err := c.queryAPI(ctx, lang, params, &apiResponse); err != nil {
+		return nil, fmt.Errorf("failed to query search: %w", err)
 	}
 
 	// Convert to our format
@@ -194,7 +346,7 @@ func (c *Client) performSearch(ctx context.Context, query string, limit int) ([]
 			PageID:    item.PageID,
 			Size:      item.Size,
 			WordCount: item.WordCount,
-			Snippet:   c.cleanSnippet(item.Snippet),
+			Snippet:   c.formatHTML(item.Snippet, lang, textFormat),
 			Timestamp: item.Timestamp,
 		}
 	}
@@ -202,9 +354,10 @@ func (c *Client) performSearch(ctx context.Context, query string, limit int) ([]
 	return results, nil
 }
 
-// getPageExtract retrieves a short extract for a wiki page
-func (c *Client) getPageExtract(ctx context.Context, title string) (string, error) {
-	cacheKey := c.cache.GetWikiPageKey(title)
+// getPageExtract retrieves a short extract for a wiki page in the given text format
+func (c *Client) getPageExtract(ctx context.Context, title string, lang Language, textFormat TextFormat,
+) (string, error) {
+	cacheKey := c.cache.GetWikiPageKey(string(lang), string(textFormat), title)
 
 	// Try cache first
 	if extract, found := c.cache.GetString(cacheKey); found {
@@ -222,19 +375,280 @@ func (c *Client) getPageExtract(ctx context.Context, title string) (string, erro
 		"exsectionformat": {"plain"},
 		"exchars":         {"500"}, // Limit to 500 characters
 	}
+	if textFormat == TextMarkdown {
+		// Markdown is rendered from the HTML extract
+		params.Del("explaintext")
+		params.Del("exsectionformat")
+	}
+
+	var contentResponse PageContentResponse
+	if err := c.queryAPI(ctx, lang, params, &contentResponse); err != nil {
+		return "", fmt.Errorf("failed to query extract: %w", err)
+	}
+
+	// Extract the content
+	var extract string
+	for _, page := range contentResponse.Query.Pages {
+		extract = page.Extract
+		break // Take the first (and should be only) page
+	}
+	if textFormat == TextMarkdown {
+		extract = c.formatHTML(extract, lang, textFormat)
+	}
 
-	extractURL := fmt.Sprintf("%s?%s", wikiAPIURL, params.Encode())
+	// Cache the extract
+	c.cache.Set(cacheKey, extract, c.cache.TTLs().WikiData)
+
+	return extract, nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", extractURL, http.NoBody)
+// GetPage retrieves a page summary in the given text format along with the titles of the
+// same page on the other language wikis
+func (c *Client) GetPage(ctx context.Context, title string, lang Language, textFormat TextFormat) (*Page, error) {
+	extract, err := c.getPageExtract(ctx, title, lang, textFormat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get page extract: %w", err)
+	}
+
+	translations, err := c.GetLanguageLinks(ctx, title, lang)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	if err != nil {
+		c.logger.Warn("Failed to get language links", "title", title, "lang", lang, "error", err)
+	}
+
+	return &Page{
+		Title:        title,
+		Language:     lang,
+		URL:          pageURL(c.baseURL(lang), title),
+		Extract:      extract,
+		Translations: translations,
+	}, nil
+}
+
+// TranslateTitle maps a page title from one language wiki to another using interlanguage links
+func (c *Client) TranslateTitle(ctx context.Context, title string, from, to Language) (string, error) {
+	if from == to {
+		return title, nil
+	}
+
+	translations, err := c.GetLanguageLinks(ctx, title, from)
 	if err != nil {
 		return "", err
 	}
 
-	req.Header.Set("User-Agent", userAgent)
+	translated, ok := translations[to]
+	if !ok {
+		return "", fmt.Errorf("no %s interlanguage link found for %q on the %s wiki", to, title, from)
+	}
+
+	return translated, nil
+}
+
+// GetLanguageLinks retrieves the interlanguage links of a page, keyed by target language
+func (c *Client) GetLanguageLinks(ctx context.Context, title string, lang Language) (map[Language]string, error) {
+	cacheKey := c.cache.GetWikiLangLinksKey(string(lang), title)
+
+	// Try cache first
+	var translations map[Language]string
+	if c.cache.GetJSON(cacheKey, &translations) {
+		return translations, nil
+	}
+
+	params := url.Values{
+		"action":    {"query"},
+		"format":    {"json"},
+		"prop":      {"langlinks"},
+		"titles":    {title},
+		"redirects": {"1"},
+		"lllimit":   {"max"},
+	}
+
+	var linksResponse LangLinksResponse
+	if err := c.queryAPI(ctx, lang, params, &linksResponse); err != nil {
+		return nil, fmt.Errorf("failed to query language links: %w", err)
+	}
+
+	translations = make(map[Language]string)
+	for _, page := range linksResponse.Query.Pages {
+		for _, link := range page.LangLinks {
+			// Only keep links pointing to the official wikis we know how to query
+			linkLang, err := ParseLanguage(link.Lang)
+			if err != nil || link.Title == "" {
+				continue
+			}
+			translations[linkLang] = link.Title
+		}
+		break // Take the first (and should be only) page
+	}
+
+	// Cache the translations
+	if err := c.cache.SetJSON(cacheKey, translations, c.cache.TTLs().WikiData); err != nil {
+		c.logger.Warn("Failed to cache language links", "error", err)
+	}
+
+	return translations, nil
+}
+
+// GetCategoryMembers lists the pages of a wiki category, following API continuation up to limit members
+func (c *Client) GetCategoryMembers(ctx context.Context, category string, limit int,
+	lang Language,
+) (*CategoryMembersResponse, error) {
+	category = normalizeCategoryTitle(category)
+	cacheKey := c.cache.GetWikiCategoryKey(string(lang), category, limit)
+
+	// Try cache first
+	var membersResponse CategoryMembersResponse
+	if c.cache.GetJSON(cacheKey, &membersResponse) {
+		c.logger.Debug("Wiki category cache hit", "category", category, "lang", lang)
+		return &membersResponse, nil
+	}
+
+	c.logger.Debug("Wiki category cache miss, fetching from API", "category", category, "lang", lang)
+
+	var members []CategoryMember
+	truncated := false
+	continueParams := map[string]string{}
+
+	for {
+		params := url.Values{
+			"action":  {"query"},
+			"format":  {"json"},
+			"list":    {"categorymembers"},
+			"cmtitle": {category},
+			"cmprop":  {"ids|title"},
+			"cmlimit": {fmt.Sprintf("%d", min(limit-len(members), maxCategoryMembersPerRequest))},
+		}
+		for key, value := range continueParams {
+			params.Set(key, value)
+		}
+
+		var apiResponse CategoryMembersAPIResponse
+		if err := c.queryAPI(ctx, lang, params, &apiResponse); err != nil {
+			return nil, fmt.Errorf("failed to query category members: %w", err)
+		}
+
+		for _, item := range apiResponse.Query.CategoryMembers {
+			members = append(members, CategoryMember{
+				Title:  item.Title,
+				PageID: item.PageID,
+				NS:     item.NS,
+				URL:    pageURL(c.baseURL(lang), item.Title),
+			})
+		}
+
+		progress.Report(ctx, len(members), limit, fmt.Sprintf("fetched %d members of %s", len(members), category))
+
+		// Stop when the category is exhausted or enough members were collected
+		if len(apiResponse.Continue) == 0 {
+			break
+		}
+		if len(members) >= limit {
+			truncated = true
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		continueParams = apiResponse.Continue
+	}
+
+	// Create response
+	membersResponse = CategoryMembersResponse{
+		Category:  category,
+		Language:  lang,
+		Members:   members,
+		Total:     len(members),
+		Truncated: truncated,
+		FetchedAt: time.Now(),
+	}
+
+	// Cache the result
+	if err := c.cache.SetJSON(cacheKey, membersResponse, c.cache.TTLs().WikiData); err != nil {
+		c.logger.Warn("Failed to cache category members", "error", err)
+	}
+
+	return &membersResponse, nil
+}
+
+// GetRecentChanges lists the latest edits and page creations in a wiki namespace
+func (c *Client) GetRecentChanges(ctx context.Context, namespace, limit int,
+	lang Language,
+) (*RecentChangesResponse, error) {
+	cacheKey := c.cache.GetWikiRecentChangesKey(string(lang), namespace, limit)
+
+	// Try cache first
+	var changesResponse RecentChangesResponse
+	if c.cache.GetJSON(cacheKey, &changesResponse) {
+		c.logger.Debug("Wiki recent changes cache hit", "namespace", namespace, "lang", lang)
+		return &changesResponse, nil
+	}
+
+	c.logger.Debug("Wiki recent changes cache miss, fetching from API", "namespace", namespace, "lang", lang)
+
+	params := url.Values{
+		"action":      {"query"},
+		"format":      {"json"},
+		"list":        {"recentchanges"},
+		"rcnamespace": {fmt.Sprintf("%d", namespace)},
+		"rclimit":     {fmt.Sprintf("%d", limit)},
+		"rcprop":      {"title|timestamp|user|comment|sizes"},
+		"rctype":      {"edit|new"},
+	}
+
+	var apiResponse RecentChangesAPIResponse
+	if err := c.queryAPI(ctx, lang, params, &apiResponse); err != nil {
+		return nil, fmt.Errorf("failed to query recent changes: %w", err)
+	}
+
+	// Convert to our format
+	changes := make([]RecentChange, len(apiResponse.Query.RecentChanges))
+	for i, item := range apiResponse.Query.RecentChanges {
+		changes[i] = RecentChange{
+			Type:      item.Type,
+			Title:     item.Title,
+			URL:       pageURL(c.baseURL(lang), item.Title),
+			User:      item.User,
+			Comment:   item.Comment,
+			Timestamp: item.Timestamp,
+			NS:        item.NS,
+			OldSize:   item.OldLen,
+			NewSize:   item.NewLen,
+		}
+	}
+
+	// Create response
+	changesResponse = RecentChangesResponse{
+		Language:  lang,
+		Namespace: namespace,
+		Changes:   changes,
+		Total:     len(changes),
+		FetchedAt: time.Now(),
+	}
+
+	// Cache the result
+	if err := c.cache.SetJSON(cacheKey, changesResponse, c.cache.TTLs().WikiRecentChanges); err != nil {
+		c.logger.Warn("Failed to cache recent changes", "error", err)
+	}
+
+	return &changesResponse, nil
+}
+
+// queryAPI performs a GET request against the MediaWiki API of the given language and decodes the response
+func (c *Client) queryAPI(ctx context.Context, lang Language, params url.Values, dest interface{}) error {
+	apiURL := fmt.Sprintf("%s/api.php?%s", c.baseURL(lang), params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", err
+		return err
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -243,46 +657,57 @@ func (c *Client) getPageExtract(ctx context.Context, title string) (string, erro
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("extract API request failed with status %d", resp.StatusCode)
+		body, readErr := io.ReadAll(resp.Body)
+		if readErr != nil {
+			return fmt.Errorf("wiki API request failed with status %d and failed to read body: %w",
+				resp.StatusCode, readErr)
+		}
+		return fmt.Errorf("wiki API request failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
-	var contentResponse PageContentResponse
-	if err := json.NewDecoder(resp.Body).Decode(&contentResponse); err != nil {
-		return "", fmt.Errorf("failed to decode extract response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(dest); err != nil {
+		return fmt.Errorf("failed to decode wiki API response: %w", err)
 	}
 
-	// Extract the content
-	var extract string
-	for _, page := range contentResponse.Query.Pages {
-		extract = page.Extract
-		break // Take the first (and should be only) page
-	}
-
-	// Cache the extract
-	c.cache.Set(cacheKey, extract, cache.WikiDataTTL)
+	return nil
+}
 
-	return extract, nil
+// categoryNamespaces are the names of the category namespace on the supported wikis, which
+// all also accept the canonical English name
+var categoryNamespaces = []string{"Category", "Kategorie", "Catégorie", "Categoría"}
+
+// normalizeCategoryTitle ensures a category name carries the canonical Category namespace prefix,
+// replacing the localized prefix of any supported wiki
+func normalizeCategoryTitle(category string) string {
+	category = strings.TrimSpace(category)
+	if namespace, name, found := strings.Cut(category, ":"); found {
+		for _, known := range categoryNamespaces {
+			if strings.EqualFold(strings.TrimSpace(namespace), known) {
+				return "Category:" + strings.TrimSpace(name)
+			}
+		}
+	}
+	return "Category:" + category
 }
 
-// cleanSnippet removes HTML tags and cleans up the snippet text
-func (c *Client) cleanSnippet(snippet string) string {
-	// Remove HTML tags
-	cleaned := strings.ReplaceAll(snippet, "<span class=\"searchmatch\">", "")
-	cleaned = strings.ReplaceAll(cleaned, "</span>", "")
-	cleaned = strings.ReplaceAll(cleaned, "&quot;", "\"")
-	cleaned = strings.ReplaceAll(cleaned, "&amp;", "&")
-	cleaned = strings.ReplaceAll(cleaned, "&lt;", "<")
-	cleaned = strings.ReplaceAll(cleaned, "&gt;", ">")
+// baseURL returns the base URL of the wiki for a language, honoring configured overrides
+func (c *Client) baseURL(lang Language) string {
+	if baseURL, ok := c.baseURLs[lang]; ok {
+		return baseURL
+	}
+	return lang.BaseURL()
+}
 
-	// Clean up whitespace
-	cleaned = strings.TrimSpace(cleaned)
-	cleaned = strings.ReplaceAll(cleaned, "\n", " ")
-	cleaned = strings.ReplaceAll(cleaned, "\t", " ")
+// pageURL builds the public URL of a page on the wiki at baseURL
+func pageURL(baseURL, title string) string {
+	return fmt.Sprintf("%s/wiki/%s", baseURL, url.QueryEscape(title))
+}
 
-	// Remove multiple spaces
-	for strings.Contains(cleaned, "  ") {
-		cleaned = strings.ReplaceAll(cleaned, "  ", " ")
+// formatHTML converts an HTML snippet or extract into plain text, or into Markdown with
+// links resolved against the wiki of the given language
+func (c *Client) formatHTML(fragment string, lang Language, textFormat TextFormat) string {
+	if textFormat == TextMarkdown {
+		return HTMLToMarkdown(fragment, c.baseURL(lang))
 	}
-
-	return cleaned
+	return HTMLToText(fragment)
 }
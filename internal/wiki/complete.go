@@ -0,0 +1,93 @@
+package wiki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// minCompletionPrefix is the shortest prefix sent to the wiki for title completion
+	minCompletionPrefix = 2
+	// maxTitleCompletions is the number of titles requested per completion prefix
+	maxTitleCompletions = 50
+)
+
+// titleCompletions is the cached result of a title prefix search
+type titleCompletions struct {
+	Titles []string `json:"titles"`
+	// Complete is true when the wiki returned every title with this prefix,
+	// so longer prefixes can be filtered locally without a new request
+	Complete bool `json:"complete"`
+}
+
+// CompleteTitles returns article titles starting with prefix, for argument completion.
+// Results cached for a shorter prefix are filtered locally when they were complete, so
+// typing a title does not send a request per keystroke.
+func (c *Client) CompleteTitles(ctx context.Context, prefix string, lang Language) ([]string, error) {
+	prefix = strings.TrimSpace(prefix)
+	if len([]rune(prefix)) < minCompletionPrefix {
+		return nil, nil
+	}
+
+	// Reuse the longest cached prefix whose result can answer this one
+	runes := []rune(prefix)
+	for n := len(runes); n >= minCompletionPrefix; n-- {
+		var cached titleCompletions
+		cacheKey := c.cache.GetWikiPrefixKey(string(lang), strings.ToLower(string(runes[:n])))
+		if !c.cache.GetJSON(cacheKey, &cached) {
+			continue
+		}
+		if n == len(runes) || cached.Complete {
+			return filterTitles(cached.Titles, prefix), nil
+		}
+		break
+	}
+
+	c.logger.Debug("Wiki title completion cache miss, fetching from API", "prefix", prefix, "lang", lang)
+
+	params := url.Values{
+		"action":    {"opensearch"},
+		"format":    {"json"},
+		"search":    {prefix},
+		"limit":     {fmt.Sprintf("%d", maxTitleCompletions)},
+		"namespace": {"0"},
+		"redirects": {"return"},
+	}
+
+	// OpenSearch responds with [query, [titles], [descriptions], [urls]]
+	var openSearch []json.RawMessage
+	if err := c.queryAPI(ctx, lang, params, &openSearch); err != nil {
+		return nil, fmt.Errorf("failed to complete title: %w", err)
+	}
+
+	var result titleCompletions
+	if len(openSearch) > 1 {
+		if err := json.Unmarshal(openSearch[1], &result.Titles); err != nil {
+			return nil, fmt.Errorf("failed to decode title completions: %w", err)
+		}
+	}
+	result.Complete = len(result.Titles) < maxTitleCompletions
+
+	// Cache the result
+	cacheKey := c.cache.GetWikiPrefixKey(string(lang), strings.ToLower(prefix))
+	if err := c.cache.SetJSON(cacheKey, result, c.cache.TTLs().WikiData); err != nil {
+		c.logger.Warn("Failed to cache title completions", "error", err)
+	}
+
+	return filterTitles(result.Titles, prefix), nil
+}
+
+// filterTitles keeps the titles starting with prefix, ignoring case
+func filterTitles(titles []string, prefix string) []string {
+	prefix = strings.ToLower(prefix)
+	filtered := make([]string, 0, len(titles))
+	for _, title := range titles {
+		if strings.HasPrefix(strings.ToLower(title), prefix) {
+			filtered = append(filtered, title)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,58 @@
+package wiki
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/AlyxPink/gw2-mcp/internal/cache"
+)
+
+func TestClient_CompleteTitles(t *testing.T) {
+	var requests atomic.Int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("search") {
+		case "My":
+			_, _ = w.Write([]byte(`["My",["Mystic Coin","Mystic Clover","Mystic Forge","My Guild"],[],[]]`))
+		default:
+			_, _ = w.Write([]byte(`["",[],[],[]]`))
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewClient(cache.NewManager(), log.New(io.Discard), WithBaseURL(LanguageEnglish, mockServer.URL))
+	ctx := context.Background()
+
+	tests := []struct {
+		prefix       string
+		expected     []string
+		wantRequests int32
+	}{
+		{prefix: "M", expected: nil, wantRequests: 0}, // too short to query
+		{prefix: "My", expected: []string{"Mystic Coin", "Mystic Clover", "Mystic Forge", "My Guild"}, wantRequests: 1},
+		{prefix: "Myst", expected: []string{"Mystic Coin", "Mystic Clover", "Mystic Forge"}, wantRequests: 1},
+		{prefix: "mystic c", expected: []string{"Mystic Coin", "Mystic Clover"}, wantRequests: 1},
+		{prefix: "Zo", expected: []string{}, wantRequests: 2},
+	}
+
+	for _, tt := range tests {
+		titles, err := client.CompleteTitles(ctx, tt.prefix, LanguageEnglish)
+		if err != nil {
+			t.Fatalf("CompleteTitles(%q) failed: %v", tt.prefix, err)
+		}
+		if !reflect.DeepEqual(titles, tt.expected) {
+			t.Errorf("CompleteTitles(%q) = %v, want %v", tt.prefix, titles, tt.expected)
+		}
+		if got := requests.Load(); got != tt.wantRequests {
+			t.Errorf("After %q: %d requests, want %d", tt.prefix, got, tt.wantRequests)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package wiki
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// TextFormat selects how the HTML of wiki snippets and extracts is rendered
+type TextFormat string
+
+const (
+	// TextPlain renders plain text
+	TextPlain TextFormat = "text"
+	// TextMarkdown renders lightweight Markdown keeping bold text, links and list items
+	TextMarkdown TextFormat = "markdown"
+)
+
+// HTMLToText converts an HTML fragment returned by the wiki into plain text.
+// All tags are stripped, entities are decoded and whitespace is collapsed.
+func HTMLToText(input string) string {
+	return renderHTML(input, false, "")
+}
+
+// HTMLToMarkdown converts an HTML fragment returned by the wiki into lightweight Markdown.
+// Bold, italic, links, line breaks and list items are preserved; relative links are
+// resolved against baseURL when it is not empty.
+func HTMLToMarkdown(input, baseURL string) string {
+	return renderHTML(input, true, baseURL)
+}
+
+// markdownEscaper escapes the text of a fragment that Markdown would read as emphasis,
+// links or code
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "`", "\\`",
+)
+
+// renderHTML walks the token stream of an HTML fragment and writes its text content
+func renderHTML(input string, markdown bool, baseURL string) string {
+	var sb strings.Builder
+	var links []string // pending hrefs of currently open <a> tags
+	var matches []bool // whether each currently open <span> is a search match
+	skipDepth := 0     // >0 while inside <script> or <style>
+
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			break // io.EOF or malformed input, either way we keep what we have
+		}
+
+		token := tokenizer.Token()
+		switch tokenType {
+		case html.TextToken:
+			if skipDepth > 0 {
+				continue
+			}
+			if markdown {
+				sb.WriteString(markdownEscaper.Replace(token.Data))
+			} else {
+				sb.WriteString(token.Data)
+			}
+		case html.StartTagToken:
+			if token.DataAtom == atom.Script || token.DataAtom == atom.Style {
+				skipDepth++
+				continue
+			}
+			switch token.DataAtom {
+			case atom.A:
+				links = append(links, resolveLink(attr(token, "href"), baseURL))
+			case atom.Span:
+				matches = append(matches, hasClass(token, "searchmatch"))
+			}
+			writeTagMarkup(&sb, token, true, markdown)
+		case html.SelfClosingTagToken:
+			// An element closed as it opens, such as <br/>, has no content, so only
+			// separators are written; empty links and emphasis are dropped
+			switch token.DataAtom {
+			case atom.Script, atom.Style, atom.A, atom.Span, atom.B, atom.Strong, atom.I, atom.Em:
+				continue
+			}
+			writeTagMarkup(&sb, token, true, markdown)
+		case html.EndTagToken:
+			if token.DataAtom == atom.Script || token.DataAtom == atom.Style {
+				if skipDepth > 0 {
+					skipDepth--
+				}
+				continue
+			}
+			if token.DataAtom == atom.A && len(links) > 0 {
+				href := links[len(links)-1]
+				links = links[:len(links)-1]
+				if markdown && href != "" {
+					sb.WriteString("](" + href + ")")
+				}
+				continue
+			}
+			if token.DataAtom == atom.Span && len(matches) > 0 {
+				// End tags carry no attributes, so reuse the class seen on the opening tag
+				if matches[len(matches)-1] && markdown {
+					sb.WriteString("**")
+				}
+				matches = matches[:len(matches)-1]
+				continue
+			}
+			writeTagMarkup(&sb, token, false, markdown)
+		case html.CommentToken, html.DoctypeToken:
+			// Ignored
+		}
+	}
+
+	return collapseWhitespace(sb.String(), markdown)
+}
+
+// writeTagMarkup writes the text equivalent of an opening or closing tag
+func writeTagMarkup(sb *strings.Builder, token html.Token, opening, markdown bool) {
+	switch token.DataAtom {
+	case atom.B, atom.Strong:
+		if markdown {
+			sb.WriteString("**")
+		}
+	case atom.I, atom.Em:
+		if markdown {
+			sb.WriteString("*")
+		}
+	case atom.Span:
+		// Search matches are highlighted with <span class="searchmatch">
+		if markdown && opening && hasClass(token, "searchmatch") {
+			sb.WriteString("**")
+		}
+	case atom.A:
+		if markdown && opening && attr(token, "href") != "" {
+			sb.WriteString("[")
+		}
+	case atom.Li:
+		if markdown && opening {
+			sb.WriteString("\n- ")
+		} else {
+			sb.WriteString(" ")
+		}
+	case atom.Br, atom.P, atom.Div, atom.Tr, atom.Ul, atom.Ol, atom.Table,
+		atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		if markdown {
+			sb.WriteString("\n")
+		} else {
+			sb.WriteString(" ")
+		}
+	case atom.Td, atom.Th:
+		sb.WriteString(" ")
+	}
+}
+
+// collapseWhitespace collapses runs of whitespace, keeping single line breaks in Markdown mode
+func collapseWhitespace(text string, markdown bool) string {
+	if !markdown {
+		return strings.Join(strings.Fields(text), " ")
+	}
+
+	lines := strings.Split(text, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// attr returns the value of the named attribute of a token, or an empty string
+func attr(token html.Token, name string) string {
+	for _, a := range token.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// hasClass reports whether a token carries the given CSS class
+func hasClass(token html.Token, class string) bool {
+	for _, c := range strings.Fields(attr(token, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveLink turns a possibly relative wiki link into an absolute URL
+func resolveLink(href, baseURL string) string {
+	if href == "" || baseURL == "" {
+		return href
+	}
+
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return href
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return base.ResolveReference(ref).String()
+}
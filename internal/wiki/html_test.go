@@ -0,0 +1,64 @@
+package wiki
+
+import "testing"
+
+func TestHTMLToMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		baseURL  string
+		expected string
+	}{
+		{
+			name:     "Search match becomes bold",
+			input:    `<span class="searchmatch">Dragon</span> Bash is a festival`,
+			expected: "**Dragon** Bash is a festival",
+		},
+		{
+			name:     "Bold and italic",
+			input:    "<b>Mystic Coin</b> and <em>Mystic Clover</em>",
+			expected: "**Mystic Coin** and *Mystic Clover*",
+		},
+		{
+			name:     "Relative link resolved against base URL",
+			input:    `See <a href="/wiki/Mystic_Forge">the Mystic Forge</a>.`,
+			baseURL:  "https://wiki.guildwars2.com",
+			expected: "See [the Mystic Forge](https://wiki.guildwars2.com/wiki/Mystic_Forge).",
+		},
+		{
+			name:     "Anchor without href keeps text only",
+			input:    `<a name="top">Top</a> of page`,
+			expected: "Top of page",
+		},
+		{
+			name:     "List items and line breaks",
+			input:    "<ul><li>Aurora</li><li>Vision</li></ul>Line<br>break",
+			expected: "- Aurora\n- Vision\nLine\nbreak",
+		},
+		{
+			name:     "Markdown characters in text escaped",
+			input:    "Costs 2*3 [Mystic Coin] for <b>item_id</b> `19976`",
+			expected: "Costs 2\\*3 \\[Mystic Coin\\] for **item\\_id** \\`19976\\`",
+		},
+		{
+			name:     "Self-closing span and link do not open markup",
+			input:    `<span class="searchmatch"/>Dragon <a href="/wiki/Bash"/>Bash<br/>festival`,
+			baseURL:  "https://wiki.guildwars2.com",
+			expected: "Dragon Bash\nfestival",
+		},
+		{
+			name:     "Entities decoded",
+			input:    "Tom &amp; Jerry&#039;s &quot;show&quot;",
+			expected: `Tom & Jerry's "show"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := HTMLToMarkdown(tt.input, tt.baseURL)
+			if result != tt.expected {
+				t.Errorf("HTMLToMarkdown() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package wiki
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Language identifies one of the official Guild Wars 2 wikis
+type Language string
+
+// Supported wiki languages
+const (
+	LanguageEnglish Language = "en"
+	LanguageGerman  Language = "de"
+	LanguageFrench  Language = "fr"
+	LanguageSpanish Language = "es"
+
+	// DefaultLanguage is used when no language is specified
+	DefaultLanguage = LanguageEnglish
+)
+
+// wikiBaseURLs maps each language to the base URL of its wiki
+var wikiBaseURLs = map[Language]string{
+	LanguageEnglish: "https://wiki.guildwars2.com",
+	LanguageGerman:  "https://wiki-de.guildwars2.com",
+	LanguageFrench:  "https://wiki-fr.guildwars2.com",
+	LanguageSpanish: "https://wiki-es.guildwars2.com",
+}
+
+// Languages returns all supported wiki languages
+func Languages() []Language {
+	return []Language{LanguageEnglish, LanguageGerman, LanguageFrench, LanguageSpanish}
+}
+
+// ParseLanguage converts a language code into a Language, defaulting to English when empty
+func ParseLanguage(code string) (Language, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultLanguage, nil
+	}
+
+	lang := Language(code)
+	if _, ok := wikiBaseURLs[lang]; !ok {
+		return "", fmt.Errorf("unsupported wiki language %q (supported: en, de, fr, es)", code)
+	}
+
+	return lang, nil
+}
+
+// BaseURL returns the base URL of the wiki for this language
+func (l Language) BaseURL() string {
+	if baseURL, ok := wikiBaseURLs[l]; ok {
+		return baseURL
+	}
+	return wikiBaseURLs[DefaultLanguage]
+}
+
+// APIURL returns the MediaWiki API endpoint of the wiki for this language
+func (l Language) APIURL() string {
+	return l.BaseURL() + "/api.php"
+}
@@ -0,0 +1,49 @@
+package wiki
+
+import "testing"
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Language
+		wantErr  bool
+	}{
+		{name: "Empty defaults to English", input: "", expected: LanguageEnglish},
+		{name: "German", input: "de", expected: LanguageGerman},
+		{name: "Case and whitespace insensitive", input: " FR ", expected: LanguageFrench},
+		{name: "Spanish", input: "es", expected: LanguageSpanish},
+		{name: "Unsupported language", input: "zh", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lang, err := ParseLanguage(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLanguage(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if lang != tt.expected {
+				t.Errorf("ParseLanguage(%q) = %q, want %q", tt.input, lang, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLanguage_URLs(t *testing.T) {
+	tests := []struct {
+		lang        Language
+		expectedAPI string
+	}{
+		{LanguageEnglish, "https://wiki.guildwars2.com/api.php"},
+		{LanguageGerman, "https://wiki-de.guildwars2.com/api.php"},
+		{LanguageFrench, "https://wiki-fr.guildwars2.com/api.php"},
+		{LanguageSpanish, "https://wiki-es.guildwars2.com/api.php"},
+		{Language("xx"), "https://wiki.guildwars2.com/api.php"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.lang.APIURL(); got != tt.expectedAPI {
+			t.Errorf("Language(%q).APIURL() = %q, want %q", tt.lang, got, tt.expectedAPI)
+		}
+	}
+}
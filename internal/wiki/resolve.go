@@ -0,0 +1,311 @@
+package wiki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Resolution statuses
+const (
+	ResolutionExact          = "exact"
+	ResolutionRedirect       = "redirect"
+	ResolutionDisambiguation = "disambiguation"
+	ResolutionSuggestions    = "suggestions"
+	ResolutionNotFound       = "not_found"
+)
+
+// maxSuggestions is the number of near matches returned when a title cannot be resolved
+const maxSuggestions = 5
+
+// disambiguationCategories maps each language to the category its wiki puts disambiguation pages in
+var disambiguationCategories = map[Language]string{
+	LanguageEnglish: "Category:Disambiguation pages",
+	LanguageGerman:  "Kategorie:Begriffsklärung",
+	LanguageFrench:  "Catégorie:Homonymie",
+	LanguageSpanish: "Categoría:Desambiguación",
+}
+
+// Suggestion represents a near match for a title that could not be resolved
+type Suggestion struct {
+	Title string  `json:"title"`
+	URL   string  `json:"url"`
+	Score float64 `json:"score"`
+}
+
+// Resolution describes how a user supplied title maps onto a wiki page
+type Resolution struct {
+	ResolvedAt     time.Time    `json:"resolved_at"`
+	Query          string       `json:"query"`
+	Language       Language     `json:"language"`
+	Status         string       `json:"status"`
+	Title          string       `json:"title,omitempty"`
+	URL            string       `json:"url,omitempty"`
+	RedirectedFrom string       `json:"redirected_from,omitempty"`
+	Options        []string     `json:"options,omitempty"`
+	Suggestions    []Suggestion `json:"suggestions,omitempty"`
+}
+
+// ResolveAPIResponse represents the MediaWiki title lookup API response
+type ResolveAPIResponse struct {
+	Query struct {
+		Normalized []struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+		} `json:"normalized"`
+		Redirects []struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+		} `json:"redirects"`
+		Pages map[string]struct {
+			Missing   *string           `json:"missing"`
+			Invalid   *string           `json:"invalid"`
+			PageProps map[string]string `json:"pageprops"`
+			Title     string            `json:"title"`
+			Links     []struct {
+				Title string `json:"title"`
+			} `json:"links"`
+			Categories []struct {
+				Title string `json:"title"`
+			} `json:"categories"`
+			PageID int `json:"pageid"`
+		} `json:"pages"`
+	} `json:"query"`
+}
+
+// Resolve maps a possibly misspelled or ambiguous title onto a wiki page.
+// Redirects are followed, disambiguation pages are reported with their options, and
+// titles that do not exist fall back to prefix and full-text search for near matches.
+func (c *Client) Resolve(ctx context.Context, title string, lang Language) (*Resolution, error) {
+	title = strings.TrimSpace(title)
+	cacheKey := c.cache.GetWikiResolveKey(string(lang), strings.ToLower(title))
+
+	// Try cache first
+	var resolution Resolution
+	if c.cache.GetJSON(cacheKey, &resolution) {
+		c.logger.Debug("Wiki resolve cache hit", "title", title, "lang", lang)
+		return &resolution, nil
+	}
+
+	c.logger.Debug("Wiki resolve cache miss, fetching from API", "title", title, "lang", lang)
+
+	resolution = Resolution{
+		Query:      title,
+		Language:   lang,
+		ResolvedAt: time.Now(),
+	}
+
+	found, err := c.lookupTitle(ctx, title, lang, &resolution)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		suggestions, err := c.suggestTitles(ctx, title, lang)
+		if err != nil {
+			return nil, err
+		}
+
+		resolution.Status = ResolutionNotFound
+		if len(suggestions) > 0 {
+			resolution.Status = ResolutionSuggestions
+			resolution.Suggestions = suggestions
+		}
+	}
+
+	// Cache the result
+	if err := c.cache.SetJSON(cacheKey, resolution, c.cache.TTLs().WikiData); err != nil {
+		c.logger.Warn("Failed to cache title resolution", "error", err)
+	}
+
+	return &resolution, nil
+}
+
+// lookupTitle checks whether a title exists, following redirects and detecting disambiguation pages
+func (c *Client) lookupTitle(ctx context.Context, title string, lang Language, resolution *Resolution) (bool, error) {
+	params := url.Values{
+		"action":       {"query"},
+		"format":       {"json"},
+		"titles":       {title},
+		"redirects":    {"1"},
+		"prop":         {"pageprops|categories"},
+		"ppprop":       {"disambiguation"},
+		"clcategories": {disambiguationCategories[lang]},
+	}
+	if params.Get("clcategories") == "" {
+		// Without a category to filter on, categories would list every category of the page
+		params.Set("prop", "pageprops")
+		params.Del("clcategories")
+	}
+
+	var apiResponse ResolveAPIResponse
+	if err := c.queryAPI(ctx, lang, params, &apiResponse); err != nil {
+		return false, fmt.Errorf("failed to look up title: %w", err)
+	}
+
+	for _, page := range apiResponse.Query.Pages {
+		if page.Missing != nil || page.Invalid != nil {
+			return false, nil
+		}
+
+		resolution.Title = page.Title
+		resolution.URL = pageURL(c.baseURL(lang), page.Title)
+		resolution.Status = ResolutionExact
+
+		if len(apiResponse.Query.Redirects) > 0 {
+			resolution.Status = ResolutionRedirect
+			resolution.RedirectedFrom = apiResponse.Query.Redirects[0].From
+		}
+
+		_, hasDisambiguationProp := page.PageProps["disambiguation"]
+		if hasDisambiguationProp || len(page.Categories) > 0 {
+			options, err := c.disambiguationOptions(ctx, page.Title, lang)
+			if err != nil {
+				return false, err
+			}
+			resolution.Status = ResolutionDisambiguation
+			resolution.Options = options
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// disambiguationOptions lists the article links of a disambiguation page
+func (c *Client) disambiguationOptions(ctx context.Context, title string, lang Language) ([]string, error) {
+	params := url.Values{
+		"action":      {"query"},
+		"format":      {"json"},
+		"titles":      {title},
+		"prop":        {"links"},
+		"plnamespace": {"0"},
+		"pllimit":     {"max"},
+	}
+
+	var apiResponse ResolveAPIResponse
+	if err := c.queryAPI(ctx, lang, params, &apiResponse); err != nil {
+		return nil, fmt.Errorf("failed to get disambiguation options: %w", err)
+	}
+
+	var options []string
+	for _, page := range apiResponse.Query.Pages {
+		for _, link := range page.Links {
+			options = append(options, link.Title)
+		}
+		break // Take the first (and should be only) page
+	}
+
+	return options, nil
+}
+
+// suggestTitles finds near matches for a title using prefix search, then full-text search
+func (c *Client) suggestTitles(ctx context.Context, title string, lang Language) ([]Suggestion, error) {
+	params := url.Values{
+		"action":    {"opensearch"},
+		"format":    {"json"},
+		"search":    {title},
+		"limit":     {fmt.Sprintf("%d", maxSuggestions*2)},
+		"namespace": {"0"},
+		"redirects": {"resolve"},
+	}
+
+	// OpenSearch responds with [query, [titles], [descriptions], [urls]]
+	var openSearch []json.RawMessage
+	if err := c.queryAPI(ctx, lang, params, &openSearch); err != nil {
+		return nil, fmt.Errorf("failed to search title prefixes: %w", err)
+	}
+
+	var candidates []string
+	if len(openSearch) > 1 {
+		if err := json.Unmarshal(openSearch[1], &candidates); err != nil {
+			return nil, fmt.Errorf("failed to decode prefix search results: %w", err)
+		}
+	}
+
+	// Misspellings rarely share a prefix with the real title, so fall back to full-text search
+	if len(candidates) == 0 {
+		results, err := c.performSearch(ctx, title, maxSuggestions*2, lang, TextPlain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search similar titles: %w", err)
+		}
+		for _, result := range results {
+			candidates = append(candidates, result.Title)
+		}
+	}
+
+	return rankSuggestions(title, candidates, c.baseURL(lang)), nil
+}
+
+// rankSuggestions scores candidate titles by similarity to the query and keeps the best ones
+func rankSuggestions(query string, candidates []string, baseURL string) []Suggestion {
+	seen := make(map[string]bool, len(candidates))
+	suggestions := make([]Suggestion, 0, len(candidates))
+	for _, candidate := range candidates {
+		if seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+
+		suggestions = append(suggestions, Suggestion{
+			Title: candidate,
+			URL:   pageURL(baseURL, candidate),
+			Score: titleSimilarity(query, candidate),
+		})
+	}
+
+	// Stable sort keeps the wiki's own ordering for equally similar titles
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+
+	return suggestions
+}
+
+// titleSimilarity returns a case-insensitive similarity between 0 and 1 based on edit distance
+func titleSimilarity(a, b string) float64 {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	longest := max(utf8.RuneCountInString(a), utf8.RuneCountInString(b))
+	if longest == 0 {
+		return 1
+	}
+
+	similarity := 1 - float64(levenshtein(a, b))/float64(longest)
+	// Round to two decimals to keep output readable
+	return float64(int(similarity*100+0.5)) / 100
+}
+
+// levenshtein computes the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
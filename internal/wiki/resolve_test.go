@@ -0,0 +1,155 @@
+package wiki
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/AlyxPink/gw2-mcp/internal/cache"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"coin", "", 4},
+		{"mystic coin", "mystic coin", 0},
+		{"mistic coin", "mystic coin", 1},
+		{"mystc coin", "mystic coin", 1},
+		{"kitten", "sitting", 3},
+		{"café", "cafe", 1},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.expected {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestRankSuggestions(t *testing.T) {
+	candidates := []string{
+		"Mystic Clover",
+		"Mystic Coin",
+		"Mystic Coin",
+		"Mystic Forge",
+		"Mystic Crystal",
+		"Mystic Salvage Kit",
+		"Mystic Tribute",
+	}
+
+	suggestions := rankSuggestions("Mistic coin", candidates, LanguageEnglish.BaseURL())
+
+	if len(suggestions) != maxSuggestions {
+		t.Fatalf("Expected %d suggestions, got %d", maxSuggestions, len(suggestions))
+	}
+
+	if suggestions[0].Title != "Mystic Coin" {
+		t.Errorf("Expected best suggestion 'Mystic Coin', got %q", suggestions[0].Title)
+	}
+
+	if suggestions[0].Score != 0.91 {
+		t.Errorf("Expected score 0.91, got %v", suggestions[0].Score)
+	}
+
+	if suggestions[0].URL != "https://wiki.guildwars2.com/wiki/Mystic+Coin" {
+		t.Errorf("Unexpected suggestion URL %q", suggestions[0].URL)
+	}
+
+	for i := 1; i < len(suggestions); i++ {
+		if suggestions[i].Title == "Mystic Coin" {
+			t.Error("Expected duplicate candidates to be removed")
+		}
+		if suggestions[i].Score > suggestions[i-1].Score {
+			t.Errorf("Suggestions not sorted by score: %v", suggestions)
+		}
+	}
+}
+
+func TestClient_Resolve_Disambiguation(t *testing.T) {
+	tests := []struct {
+		name        string
+		lang        Language
+		title       string
+		category    string // disambiguation category of the page, if any
+		pageProp    bool   // whether the page has the disambiguation page property
+		wantStatus  string
+		wantOptions []string
+	}{
+		{
+			name:        "English category",
+			lang:        LanguageEnglish,
+			title:       "Eternity",
+			category:    "Category:Disambiguation pages",
+			wantStatus:  ResolutionDisambiguation,
+			wantOptions: []string{"Eternity (weapon)", "Eternity (skin)"},
+		},
+		{
+			name:        "German category",
+			lang:        LanguageGerman,
+			title:       "Ewigkeit",
+			category:    "Kategorie:Begriffsklärung",
+			wantStatus:  ResolutionDisambiguation,
+			wantOptions: []string{"Ewigkeit (Waffe)", "Ewigkeit (Skin)"},
+		},
+		{
+			name:        "French page property",
+			lang:        LanguageFrench,
+			title:       "Éternité",
+			pageProp:    true,
+			wantStatus:  ResolutionDisambiguation,
+			wantOptions: []string{"Éternité (arme)", "Éternité (apparence)"},
+		},
+		{
+			name:       "Spanish article",
+			lang:       LanguageSpanish,
+			title:      "Moneda mística",
+			wantStatus: ResolutionExact,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				query := r.URL.Query()
+				if query.Get("prop") == "links" {
+					_, _ = w.Write([]byte(`{"query":{"pages":{"1":{"pageid":1,"title":"` + tt.title + `","links":[` +
+						`{"ns":0,"title":"` + tt.wantOptions[0] + `"},{"ns":0,"title":"` + tt.wantOptions[1] + `"}]}}}}`))
+					return
+				}
+
+				// Categories are only listed when they match the requested ones, like MediaWiki does
+				page := `"pageid":1,"ns":0,"title":"` + tt.title + `"`
+				if tt.category != "" && query.Get("clcategories") == tt.category {
+					page += `,"categories":[{"ns":14,"title":"` + tt.category + `"}]`
+				}
+				if tt.pageProp {
+					page += `,"pageprops":{"disambiguation":""}`
+				}
+				_, _ = w.Write([]byte(`{"query":{"pages":{"1":{` + page + `}}}}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(cache.NewManager(), log.New(io.Discard), WithBaseURL(tt.lang, server.URL))
+
+			resolution, err := client.Resolve(context.Background(), tt.title, tt.lang)
+			if err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+			if resolution.Status != tt.wantStatus {
+				t.Errorf("Expected status %q, got %q", tt.wantStatus, resolution.Status)
+			}
+			if !slices.Equal(resolution.Options, tt.wantOptions) {
+				t.Errorf("Expected options %v, got %v", tt.wantOptions, resolution.Options)
+			}
+		})
+	}
+}
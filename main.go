@@ -4,13 +4,15 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"flag"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/charmbracelet/log"
 
+	"github.com/AlyxPink/gw2-mcp/internal/config"
 	"github.com/AlyxPink/gw2-mcp/internal/server"
 )
 
@@ -22,6 +24,15 @@ func main() {
 		Level:           log.DebugLevel,
 	})
 
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		logger.Fatal("Invalid configuration", "error", err)
+	}
+	logger.SetLevel(cfg.Level())
+
 	// Create context that cancels on interrupt
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -35,8 +46,13 @@ func main() {
 		cancel()
 	}()
 
+	opts, err := serverOptions(cfg)
+	if err != nil {
+		logger.Fatal("Failed to apply configuration", "error", err)
+	}
+
 	// Create and start the MCP server
-	mcpServer, err := server.NewMCPServer(logger)
+	mcpServer, err := server.NewMCPServer(logger, opts...)
 	if err != nil {
 		logger.Fatal("Failed to create MCP server", "error", err)
 	}
@@ -46,5 +62,5 @@ func main() {
 		logger.Fatal("Server failed", "error", err)
 	}
 
-	fmt.Println("Server stopped")
+	logger.Info("Server stopped")
 }
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/AlyxPink/gw2-mcp/internal/auth"
+	"github.com/AlyxPink/gw2-mcp/internal/cache"
+	"github.com/AlyxPink/gw2-mcp/internal/config"
+	"github.com/AlyxPink/gw2-mcp/internal/gw2api"
+	"github.com/AlyxPink/gw2-mcp/internal/httpfixture"
+	"github.com/AlyxPink/gw2-mcp/internal/server"
+	"github.com/AlyxPink/gw2-mcp/internal/wiki"
+)
+
+// serverOptions converts the configuration into MCP server options
+func serverOptions(c *config.Config) ([]server.Option, error) {
+	lang, err := gw2api.ParseLanguage(c.Language, gw2api.DefaultLanguage)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := server.ParseTransport(c.Server.Transport)
+	if err != nil {
+		return nil, err
+	}
+
+	wikiOpts := []wiki.Option{wiki.WithTimeout(c.Wiki.Timeout)}
+	for code, baseURL := range c.Wiki.BaseURLs {
+		wikiLang, err := wiki.ParseLanguage(code)
+		if err != nil {
+			return nil, err
+		}
+		wikiOpts = append(wikiOpts, wiki.WithBaseURL(wikiLang, baseURL))
+	}
+
+	gw2APIOpts := []gw2api.Option{
+		gw2api.WithBaseURL(c.GW2API.BaseURL),
+		gw2api.WithTimeout(c.GW2API.Timeout),
+	}
+
+	// Serve upstream requests from fixture files, or record them, instead of only the network
+	var upstream http.RoundTripper
+	switch {
+	case c.Fixtures.Offline:
+		upstream = httpfixture.NewReplayer(c.Fixtures.Dir)
+	case c.Fixtures.Record:
+		upstream = httpfixture.NewRecorder(c.Fixtures.Dir, nil)
+	}
+	if upstream != nil {
+		gw2APIOpts = append(gw2APIOpts, gw2api.WithTransport(upstream))
+		wikiOpts = append(wikiOpts, wiki.WithTransport(upstream))
+	}
+
+	opts := []server.Option{
+		server.WithDefaultLanguage(lang),
+		server.WithTransport(transport),
+		server.WithHTTPAddr(c.Server.Addr),
+		server.WithShutdownTimeout(c.Server.ShutdownTimeout),
+		server.WithMaxOutputSize(c.Server.MaxOutputSize),
+		server.WithCacheFile(c.Cache.File),
+		server.WithCacheOptions(cache.WithTTLs(cache.TTLs{
+			StaticData:        c.Cache.StaticTTL,
+			WikiData:          c.Cache.WikiTTL,
+			WikiRecentChanges: c.Cache.WikiRecentChangesTTL,
+			WalletData:        c.Cache.WalletTTL,
+			TradingPost:       c.Cache.TradingPostTTL,
+			CleanupInterval:   c.Cache.CleanupInterval,
+		})),
+		server.WithGW2APIOptions(gw2APIOpts...),
+		server.WithWikiOptions(wikiOpts...),
+		server.WithItemIndex(c.GW2API.IndexItems),
+		server.WithDatabase(c.Storage.Database),
+		server.WithPriceWatchlist(c.Storage.PriceWatchlist, c.Storage.PriceSampleInterval),
+	}
+
+	// Bind HTTP sessions to stored API key profiles when a profiles file is given
+	if c.Server.Profiles != "" {
+		store, err := auth.LoadStore(c.Server.Profiles)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, server.WithAuth(store))
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/AlyxPink/gw2-mcp/internal/config"
+	"github.com/AlyxPink/gw2-mcp/internal/server"
+)
+
+func TestServerOptions_Defaults(t *testing.T) {
+	cfg := config.Default()
+
+	// The configuration keeps its own copy of the server defaults
+	if cfg.Server.Transport != string(server.TransportStdio) || cfg.Server.Addr != server.DefaultHTTPAddr ||
+		cfg.Server.ShutdownTimeout != server.DefaultShutdownTimeout ||
+		cfg.Server.MaxOutputSize != server.DefaultMaxOutputSize ||
+		cfg.Storage.PriceSampleInterval != server.DefaultPriceSampleInterval {
+		t.Errorf("Expected the server defaults, got %+v and %+v", cfg.Server, cfg.Storage)
+	}
+
+	opts, err := serverOptions(cfg)
+	if err != nil {
+		t.Fatalf("serverOptions failed on defaults: %v", err)
+	}
+	if _, err := server.NewMCPServer(log.New(io.Discard), opts...); err != nil {
+		t.Errorf("Failed to create server from defaults: %v", err)
+	}
+}
+
+func TestServerOptions_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *config.Config)
+	}{
+		{name: "unsupported transport", modify: func(cfg *config.Config) { cfg.Server.Transport = "websocket" }},
+		{name: "missing profiles file", modify: func(cfg *config.Config) {
+			cfg.Server.Profiles = filepath.Join(t.TempDir(), "profiles.json")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			tt.modify(cfg)
+			if _, err := serverOptions(cfg); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}